@@ -0,0 +1,376 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	repoErrors "github.com/rauzh/cd-core/errors/repo"
+	"github.com/rauzh/cd-core/models"
+	"github.com/rauzh/cd-core/repo"
+	"github.com/rauzh/cd-core/statistics/convert"
+	cdtime "github.com/rauzh/cd-core/time"
+)
+
+var (
+	ErrUnknownChart = errors.New("unknown chart")
+	ErrBadPeriod    = errors.New("bad chart period")
+	ErrWeekNotOver  = errors.New("chart week is not over")
+)
+
+const (
+	DefaultLimit = 10
+	// DefaultMinGrowthBase keeps tracks going from 1 to 10 streams out of growth charts
+	DefaultMinGrowthBase = 1000
+)
+
+type Config struct {
+	// Limit is the number of chart entries, DefaultLimit if zero
+	Limit int
+	// MinGrowthBase is the least streams of the previous period to get into growth charts
+	MinGrowthBase uint64
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Limit:         DefaultLimit,
+		MinGrowthBase: DefaultMinGrowthBase,
+	}
+}
+
+type Query struct {
+	Entity models.ChartEntity
+	Metric models.ChartMetric
+	// From and To are inclusive dates, To is today if zero, From is a week before To if zero
+	From time.Time
+	To   time.Time
+	// Limit is Config.Limit if zero
+	Limit int
+}
+
+type IChartsService interface {
+	// Top ranks published tracks, releases or artists over any period
+	Top(query Query) ([]models.ChartEntry, error)
+	// Snapshot persists charts of every entity and metric for the week starting on the monday of week.
+	// The week must be over, stored snapshots are returned as they are
+	Snapshot(week time.Time) ([]*models.Chart, error)
+	GetForWeek(entity models.ChartEntity, metric models.ChartMetric, week time.Time) (*models.Chart, error)
+}
+
+type ChartsService struct {
+	statRepo    repo.StatisticsRepo
+	pbcRepo     repo.PublicationRepo
+	releaseRepo repo.ReleaseRepo
+	chartRepo   repo.ChartRepo
+
+	cfg Config
+
+	logger *slog.Logger
+}
+
+func NewChartsService(
+	statRepo repo.StatisticsRepo,
+	pbcRepo repo.PublicationRepo,
+	releaseRepo repo.ReleaseRepo,
+	chartRepo repo.ChartRepo,
+	cfg Config,
+	logger *slog.Logger) IChartsService {
+
+	if cfg.Limit <= 0 {
+		cfg.Limit = DefaultLimit
+	}
+
+	return &ChartsService{
+		statRepo:    statRepo,
+		pbcRepo:     pbcRepo,
+		releaseRepo: releaseRepo,
+		chartRepo:   chartRepo,
+		cfg:         cfg,
+		logger:      logger,
+	}
+}
+
+func (chartSvc *ChartsService) Top(query Query) ([]models.ChartEntry, error) {
+
+	query, err := chartSvc.normalize(query)
+	if err != nil {
+		return nil, err
+	}
+
+	charts, err := chartSvc.rank(context.Background(), query.From, query.To, query.Limit)
+	if err != nil {
+		chartSvc.logger.Error("CHARTS_SERVICE Top", "from", query.From, "to", query.To, slog.Any("error", err))
+		return nil, err
+	}
+
+	return charts[chartKey{entity: query.Entity, metric: query.Metric}], nil
+}
+
+func (chartSvc *ChartsService) Snapshot(week time.Time) ([]*models.Chart, error) {
+
+	ctx := context.Background()
+	week = cdtime.WeekStart(week)
+
+	// stats of the current week are not complete, its snapshot would be stored forever
+	if weekEnd := week.AddDate(0, 0, 6); !weekEnd.Before(cdtime.GetToday()) {
+		return nil, fmt.Errorf("%w: week of %s ends on %s", ErrWeekNotOver,
+			week.Format(time.DateOnly), weekEnd.Format(time.DateOnly))
+	}
+
+	snapshots := make([]*models.Chart, 0, len(chartKeys))
+	missing := make([]chartKey, 0, len(chartKeys))
+	for _, key := range chartKeys {
+		chart, err := chartSvc.chartRepo.GetForWeek(ctx, key.entity, key.metric, week)
+		if errors.Is(err, repoErrors.ErrorNotExists) {
+			missing = append(missing, key)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("can't get chart with err %w", err)
+		}
+		snapshots = append(snapshots, chart)
+	}
+
+	if len(missing) == 0 {
+		return snapshots, nil
+	}
+
+	charts, err := chartSvc.rank(ctx, week, week.AddDate(0, 0, 6), chartSvc.cfg.Limit)
+	if err != nil {
+		chartSvc.logger.Error("CHARTS_SERVICE Snapshot", "week", week, slog.Any("error", err))
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	for _, key := range missing {
+
+		chart := &models.Chart{
+			Entity:    key.entity,
+			Metric:    key.metric,
+			Week:      week,
+			Entries:   charts[key],
+			CreatedAt: now,
+		}
+
+		previous, err := chartSvc.chartRepo.GetForWeek(ctx, key.entity, key.metric, week.AddDate(0, 0, -7))
+		if err != nil && !errors.Is(err, repoErrors.ErrorNotExists) {
+			return nil, fmt.Errorf("can't get previous chart with err %w", err)
+		}
+		compare(chart, previous)
+
+		if err := chartSvc.chartRepo.Create(ctx, chart); err != nil {
+			chartSvc.logger.Error("CHARTS_SERVICE Snapshot can't create", "week", week, slog.Any("error", err))
+			return nil, fmt.Errorf("can't create chart with err %w", err)
+		}
+		snapshots = append(snapshots, chart)
+	}
+
+	chartSvc.logger.Info("CHARTS_SERVICE Snapshot", "week", week, "charts", len(missing))
+
+	return snapshots, nil
+}
+
+func (chartSvc *ChartsService) GetForWeek(entity models.ChartEntity, metric models.ChartMetric, week time.Time) (*models.Chart, error) {
+
+	chart, err := chartSvc.chartRepo.GetForWeek(context.Background(), entity, metric, cdtime.WeekStart(week))
+	if err != nil {
+		chartSvc.logger.Error("CHARTS_SERVICE GetForWeek", "entity", entity, "metric", metric, slog.Any("error", err))
+		return nil, fmt.Errorf("can't get chart with err %w", err)
+	}
+
+	return chart, nil
+}
+
+type chartKey struct {
+	entity models.ChartEntity
+	metric models.ChartMetric
+}
+
+var chartKeys = []chartKey{
+	{entity: models.TrackChart, metric: models.StreamsMetric},
+	{entity: models.TrackChart, metric: models.GrowthMetric},
+	{entity: models.ReleaseChart, metric: models.StreamsMetric},
+	{entity: models.ReleaseChart, metric: models.GrowthMetric},
+	{entity: models.ArtistChart, metric: models.StreamsMetric},
+	{entity: models.ArtistChart, metric: models.GrowthMetric},
+}
+
+type counter struct {
+	streams         uint64
+	previousStreams uint64
+}
+
+// rank computes every chart of the period at once, they all need the same stats.
+// Streams of the previous period of the same length are counted for growth
+func (chartSvc *ChartsService) rank(ctx context.Context, from, to time.Time, limit int) (map[chartKey][]models.ChartEntry, error) {
+
+	pubs, err := chartSvc.pbcRepo.GetAllUntilDate(ctx, to)
+	if err != nil {
+		return nil, fmt.Errorf("can't get publications with err %w", err)
+	}
+
+	releaseOf := make(map[uint64]*models.Release)
+	trackIDs := make([]uint64, 0)
+	for _, pub := range pubs {
+		release, err := chartSvc.releaseRepo.Get(ctx, pub.ReleaseID)
+		if err != nil {
+			return nil, fmt.Errorf("can't get release %d with err %w", pub.ReleaseID, err)
+		}
+		for _, trackID := range release.Tracks {
+			if _, ok := releaseOf[trackID]; !ok {
+				releaseOf[trackID] = release
+				trackIDs = append(trackIDs, trackID)
+			}
+		}
+	}
+
+	counters := map[models.ChartEntity]map[uint64]*counter{
+		models.TrackChart:   make(map[uint64]*counter),
+		models.ReleaseChart: make(map[uint64]*counter),
+		models.ArtistChart:  make(map[uint64]*counter),
+	}
+
+	if len(trackIDs) > 0 {
+		days := int(to.Sub(from)/(24*time.Hour)) + 1
+		previousFrom := from.AddDate(0, 0, -days)
+
+		deltas, err := convert.DeltasBetween(ctx, chartSvc.statRepo, trackIDs, previousFrom, to)
+		if err != nil {
+			return nil, fmt.Errorf("can't get stats with err %w", err)
+		}
+
+		for _, delta := range deltas {
+			release := releaseOf[delta.TrackID]
+			for entity, entityID := range map[models.ChartEntity]uint64{
+				models.TrackChart:   delta.TrackID,
+				models.ReleaseChart: release.ReleaseID,
+				models.ArtistChart:  release.ArtistID,
+			} {
+				count, ok := counters[entity][entityID]
+				if !ok {
+					count = &counter{}
+					counters[entity][entityID] = count
+				}
+				if delta.Date.Before(from) {
+					count.previousStreams += delta.Streams
+				} else {
+					count.streams += delta.Streams
+				}
+			}
+		}
+	}
+
+	charts := make(map[chartKey][]models.ChartEntry, len(chartKeys))
+	for _, key := range chartKeys {
+		charts[key] = chartSvc.top(counters[key.entity], key.metric, limit)
+	}
+
+	chartSvc.logger.Debug("CHARTS_SERVICE rank", "from", from, "to", to, "tracks_len", len(trackIDs))
+
+	return charts, nil
+}
+
+func (chartSvc *ChartsService) top(counters map[uint64]*counter, metric models.ChartMetric, limit int) []models.ChartEntry {
+
+	entries := make([]models.ChartEntry, 0, len(counters))
+	for entityID, count := range counters {
+		entry := models.ChartEntry{
+			EntityID:        entityID,
+			Streams:         count.streams,
+			PreviousStreams: count.previousStreams,
+		}
+		if count.previousStreams > 0 {
+			entry.Growth = (float64(count.streams) - float64(count.previousStreams)) / float64(count.previousStreams)
+		}
+
+		switch metric {
+		case models.GrowthMetric:
+			if count.previousStreams < chartSvc.cfg.MinGrowthBase || count.previousStreams == 0 || entry.Growth <= 0 {
+				continue
+			}
+		default:
+			if count.streams == 0 {
+				continue
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if metric == models.GrowthMetric && entries[i].Growth != entries[j].Growth {
+			return entries[i].Growth > entries[j].Growth
+		}
+		if entries[i].Streams != entries[j].Streams {
+			return entries[i].Streams > entries[j].Streams
+		}
+		return entries[i].EntityID < entries[j].EntityID
+	})
+
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	for i := range entries {
+		entries[i].Position = i + 1
+	}
+
+	return entries
+}
+
+// compare sets position changes against the chart of the previous week, previous may be nil
+func compare(chart, previous *models.Chart) {
+
+	previousEntries := make(map[uint64]models.ChartEntry)
+	if previous != nil {
+		for _, entry := range previous.Entries {
+			previousEntries[entry.EntityID] = entry
+		}
+	}
+
+	for i := range chart.Entries {
+		entry := &chart.Entries[i]
+		entry.WeeksOnChart = 1
+		if previousEntry, ok := previousEntries[entry.EntityID]; ok {
+			entry.PreviousPosition = previousEntry.Position
+			entry.WeeksOnChart += previousEntry.WeeksOnChart
+		}
+	}
+}
+
+func (chartSvc *ChartsService) normalize(query Query) (Query, error) {
+
+	if !isChart(query.Entity, query.Metric) {
+		return query, fmt.Errorf("%w: %s by %s", ErrUnknownChart, query.Entity, query.Metric)
+	}
+
+	if query.To.IsZero() {
+		query.To = cdtime.GetToday()
+	}
+	query.To = cdtime.Day(query.To)
+	if query.From.IsZero() {
+		query.From = query.To.AddDate(0, 0, -6)
+	}
+	query.From = cdtime.Day(query.From)
+
+	if query.From.After(query.To) {
+		return query, fmt.Errorf("%w: from %s to %s", ErrBadPeriod,
+			query.From.Format(time.DateOnly), query.To.Format(time.DateOnly))
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = chartSvc.cfg.Limit
+	}
+
+	return query, nil
+}
+
+func isChart(entity models.ChartEntity, metric models.ChartMetric) bool {
+	for _, key := range chartKeys {
+		if key.entity == entity && key.metric == metric {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"log/slog"
+	"testing"
+
+	repoErrors "github.com/rauzh/cd-core/errors/repo"
+	"github.com/rauzh/cd-core/models"
+	"github.com/rauzh/cd-core/repo/mocks"
+	cdtime "github.com/rauzh/cd-core/time"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// 2024-05-06 is monday
+var (
+	week     = cdtime.Date(2024, 5, 6)
+	weekEnd  = cdtime.Date(2024, 5, 12)
+	prevWeek = cdtime.Date(2024, 4, 29)
+)
+
+func newTestChartsService(t *testing.T) (*ChartsService, *mocks.ChartRepo) {
+
+	statMockRepo := mocks.NewStatisticsRepo(t)
+	pbcMockRepo := mocks.NewPublicationRepo(t)
+	rlsMockRepo := mocks.NewReleaseRepo(t)
+	chartMockRepo := mocks.NewChartRepo(t)
+
+	pbcMockRepo.EXPECT().GetAllUntilDate(mock.AnythingOfType("context.backgroundCtx"), weekEnd).Return(
+		[]models.Publication{{ReleaseID: 1}, {ReleaseID: 2}}, nil).Once()
+	rlsMockRepo.EXPECT().Get(mock.AnythingOfType("context.backgroundCtx"), uint64(1)).Return(
+		&models.Release{ReleaseID: 1, ArtistID: 100, Tracks: []uint64{1, 2}}, nil).Once()
+	rlsMockRepo.EXPECT().Get(mock.AnythingOfType("context.backgroundCtx"), uint64(2)).Return(
+		&models.Release{ReleaseID: 2, ArtistID: 200, Tracks: []uint64{3}}, nil).Once()
+
+	tracks := []uint64{1, 2, 3}
+	statMockRepo.EXPECT().GetLastForTracksBefore(mock.AnythingOfType("context.backgroundCtx"), tracks, prevWeek).Return(
+		nil, nil).Once()
+	statMockRepo.EXPECT().GetForTracksBetween(mock.AnythingOfType("context.backgroundCtx"), tracks, prevWeek, weekEnd).Return(
+		[]models.Statistics{
+			{TrackID: 1, Date: cdtime.Date(2024, 5, 1), Streams: 2000, Kind: models.DeltaStatistics},
+			{TrackID: 1, Date: cdtime.Date(2024, 5, 8), Streams: 3000, Kind: models.DeltaStatistics},
+			// fast growing, but too small for growth charts
+			{TrackID: 2, Date: cdtime.Date(2024, 5, 1), Streams: 500, Kind: models.DeltaStatistics},
+			{TrackID: 2, Date: cdtime.Date(2024, 5, 8), Streams: 4000, Kind: models.DeltaStatistics},
+			{TrackID: 3, Date: cdtime.Date(2024, 5, 2), Streams: 1000, Kind: models.DeltaStatistics},
+			{TrackID: 3, Date: cdtime.Date(2024, 5, 12), Streams: 1500, Kind: models.DeltaStatistics},
+		}, nil).Once()
+
+	chartSvc := NewChartsService(statMockRepo, pbcMockRepo, rlsMockRepo, chartMockRepo,
+		DefaultConfig(), slog.Default()).(*ChartsService)
+
+	return chartSvc, chartMockRepo
+}
+
+func TestChartsService_Top(t *testing.T) {
+
+	chartSvc, _ := newTestChartsService(t)
+
+	entries, err := chartSvc.Top(Query{Entity: models.ArtistChart, Metric: models.GrowthMetric, From: week, To: weekEnd})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []models.ChartEntry{
+		{Position: 1, EntityID: 100, Streams: 7000, PreviousStreams: 2500, Growth: 1.8},
+		{Position: 2, EntityID: 200, Streams: 1500, PreviousStreams: 1000, Growth: 0.5},
+	}, entries)
+
+	_, err = chartSvc.Top(Query{Entity: "labels", Metric: models.StreamsMetric})
+	assert.ErrorIs(t, err, ErrUnknownChart)
+}
+
+func TestChartsService_Snapshot(t *testing.T) {
+
+	chartSvc, chartMockRepo := newTestChartsService(t)
+
+	stored := &models.Chart{ChartID: 5, Entity: models.ReleaseChart, Metric: models.StreamsMetric, Week: week}
+	chartMockRepo.EXPECT().GetForWeek(mock.AnythingOfType("context.backgroundCtx"), models.ReleaseChart, models.StreamsMetric, week).Return(
+		stored, nil).Once()
+	chartMockRepo.EXPECT().GetForWeek(mock.AnythingOfType("context.backgroundCtx"), mock.Anything, mock.Anything, week).Return(
+		nil, repoErrors.ErrorNotExists).Times(5)
+
+	chartMockRepo.EXPECT().GetForWeek(mock.AnythingOfType("context.backgroundCtx"), models.TrackChart, models.StreamsMetric, prevWeek).Return(
+		&models.Chart{Entity: models.TrackChart, Metric: models.StreamsMetric, Week: prevWeek, Entries: []models.ChartEntry{
+			{Position: 1, EntityID: 3, WeeksOnChart: 1},
+			{Position: 2, EntityID: 9, WeeksOnChart: 4},
+			{Position: 3, EntityID: 1, WeeksOnChart: 2},
+		}}, nil).Once()
+	chartMockRepo.EXPECT().GetForWeek(mock.AnythingOfType("context.backgroundCtx"), mock.Anything, mock.Anything, prevWeek).Return(
+		nil, repoErrors.ErrorNotExists).Times(4)
+
+	chartMockRepo.EXPECT().Create(mock.AnythingOfType("context.backgroundCtx"), mock.AnythingOfType("*models.Chart")).Return(
+		nil).Times(5)
+
+	charts, err := chartSvc.Snapshot(cdtime.Date(2024, 5, 9))
+
+	assert.Nil(t, err)
+	assert.Len(t, charts, 6)
+	assert.Equal(t, stored, charts[0])
+
+	trackChart := charts[1]
+	assert.Equal(t, models.TrackChart, trackChart.Entity)
+	assert.Equal(t, models.StreamsMetric, trackChart.Metric)
+	assert.Equal(t, week, trackChart.Week)
+	assert.Equal(t, []models.ChartEntry{
+		{Position: 1, EntityID: 2, Streams: 4000, PreviousStreams: 500, Growth: 7, WeeksOnChart: 1},
+		{Position: 2, EntityID: 1, Streams: 3000, PreviousStreams: 2000, Growth: 0.5, PreviousPosition: 3, WeeksOnChart: 3},
+		{Position: 3, EntityID: 3, Streams: 1500, PreviousStreams: 1000, Growth: 0.5, PreviousPosition: 1, WeeksOnChart: 2},
+	}, trackChart.Entries)
+
+	assert.Equal(t, "new entry", trackChart.Entries[0].Movement())
+	assert.Equal(t, "up 1", trackChart.Entries[1].Movement())
+	assert.Equal(t, "down 2", trackChart.Entries[2].Movement())
+
+	// growth charts skip small tracks, equal growth goes by streams
+	assert.Equal(t, []uint64{1, 3}, []uint64{charts[2].Entries[0].EntityID, charts[2].Entries[1].EntityID})
+}
+
+func TestChartsService_SnapshotWeekNotOver(t *testing.T) {
+
+	chartSvc := NewChartsService(mocks.NewStatisticsRepo(t), mocks.NewPublicationRepo(t), mocks.NewReleaseRepo(t),
+		mocks.NewChartRepo(t), DefaultConfig(), slog.Default())
+
+	_, err := chartSvc.Snapshot(cdtime.GetToday())
+	assert.ErrorIs(t, err, ErrWeekNotOver)
+
+	_, err = chartSvc.Snapshot(cdtime.GetToday().AddDate(0, 0, 7))
+	assert.ErrorIs(t, err, ErrWeekNotOver)
+}
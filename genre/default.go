@@ -0,0 +1,39 @@
+package genre
+
+var defaultGenres = []Genre{
+	{ID: "rock", Name: "Rock"},
+	{ID: "alternative-rock", Name: "Alternative Rock", Parent: "rock", Aliases: []string{"alternative", "alt-rock"}},
+	{ID: "indie-rock", Name: "Indie Rock", Parent: "rock", Aliases: []string{"indie"}},
+	{ID: "punk", Name: "Punk", Parent: "rock", Aliases: []string{"punk-rock"}},
+	{ID: "metal", Name: "Metal", Parent: "rock", Aliases: []string{"heavy-metal"}},
+
+	{ID: "pop", Name: "Pop"},
+	{ID: "k-pop", Name: "K-Pop", Parent: "pop", Aliases: []string{"kpop"}},
+	{ID: "synth-pop", Name: "Synth-pop", Parent: "pop", Aliases: []string{"synthpop"}},
+
+	{ID: "hip-hop", Name: "Hip-Hop", Aliases: []string{"hiphop", "rap"}},
+	{ID: "trap", Name: "Trap", Parent: "hip-hop"},
+	{ID: "drill", Name: "Drill", Parent: "hip-hop"},
+
+	{ID: "electronic", Name: "Electronic", Aliases: []string{"electronica", "edm", "electro"}},
+	{ID: "house", Name: "House", Parent: "electronic"},
+	{ID: "techno", Name: "Techno", Parent: "electronic"},
+	{ID: "drum-and-bass", Name: "Drum and Bass", Parent: "electronic", Aliases: []string{"dnb", "drum-n-bass"}},
+	{ID: "dubstep", Name: "Dubstep", Parent: "electronic"},
+
+	{ID: "r&b", Name: "R&B", Aliases: []string{"rnb", "rhythm-and-blues"}},
+	{ID: "soul", Name: "Soul", Parent: "r&b"},
+
+	{ID: "jazz", Name: "Jazz"},
+	{ID: "blues", Name: "Blues"},
+	{ID: "classical", Name: "Classical", Aliases: []string{"classic"}},
+	{ID: "country", Name: "Country"},
+	{ID: "folk", Name: "Folk"},
+	{ID: "reggae", Name: "Reggae"},
+	{ID: "latin", Name: "Latin"},
+}
+
+// DefaultTaxonomy is the label taxonomy used when no other one is configured
+func DefaultTaxonomy() (ITaxonomy, error) {
+	return NewTaxonomy(defaultGenres)
+}
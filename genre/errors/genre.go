@@ -0,0 +1,10 @@
+package errors
+
+import "errors"
+
+var (
+	ErrUnknownGenre  error = errors.New("unknown genre")
+	ErrUnknownParent error = errors.New("unknown parent genre")
+	ErrAliasConflict error = errors.New("genre alias is already used by another genre")
+	ErrGenreCycle    error = errors.New("genre hierarchy has a cycle")
+)
@@ -0,0 +1,139 @@
+package genre
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	genreErrors "github.com/rauzh/cd-core/genre/errors"
+)
+
+type GenreID string
+
+const NoParent GenreID = ""
+
+type Genre struct {
+	ID      GenreID
+	Name    string
+	Parent  GenreID
+	Aliases []string
+}
+
+type ITaxonomy interface {
+	Resolve(name string) (GenreID, error)
+	Canonical(name string) GenreID
+	Get(id GenreID) (*Genre, bool)
+	Ancestors(id GenreID) []GenreID
+	Root(id GenreID) GenreID
+	SameFamily(first, second GenreID) bool
+}
+
+type Taxonomy struct {
+	genres  map[GenreID]Genre
+	aliases map[string]GenreID
+}
+
+func NewTaxonomy(genres []Genre) (ITaxonomy, error) {
+
+	taxonomy := &Taxonomy{
+		genres:  make(map[GenreID]Genre, len(genres)),
+		aliases: make(map[string]GenreID),
+	}
+
+	for _, g := range genres {
+		g.ID = GenreID(Normalize(string(g.ID)))
+		g.Parent = GenreID(Normalize(string(g.Parent)))
+		taxonomy.genres[g.ID] = g
+
+		for _, alias := range append([]string{string(g.ID), g.Name}, g.Aliases...) {
+			key := Normalize(alias)
+			if key == "" {
+				continue
+			}
+			if id, ok := taxonomy.aliases[key]; ok && id != g.ID {
+				return nil, fmt.Errorf("%w: %s", genreErrors.ErrAliasConflict, alias)
+			}
+			taxonomy.aliases[key] = g.ID
+		}
+	}
+
+	for _, g := range taxonomy.genres {
+		if g.Parent == NoParent {
+			continue
+		}
+		if _, ok := taxonomy.genres[g.Parent]; !ok {
+			return nil, fmt.Errorf("%w: %s for %s", genreErrors.ErrUnknownParent, g.Parent, g.ID)
+		}
+		if err := taxonomy.checkCycle(g.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return taxonomy, nil
+}
+
+func (taxonomy *Taxonomy) checkCycle(id GenreID) error {
+	visited := make(map[GenreID]bool)
+	for cur := id; cur != NoParent; cur = taxonomy.genres[cur].Parent {
+		if visited[cur] {
+			return fmt.Errorf("%w: %s", genreErrors.ErrGenreCycle, id)
+		}
+		visited[cur] = true
+	}
+	return nil
+}
+
+// Normalize lowercases genre name and joins words with single dash,
+// so "Hip Hop", "hip_hop" and "HIP-HOP" become "hip-hop"
+func Normalize(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return unicode.IsSpace(r) || r == '-' || r == '_'
+	})
+	return strings.Join(words, "-")
+}
+
+// Resolve returns canonical genre id by its id, name or alias
+func (taxonomy *Taxonomy) Resolve(name string) (GenreID, error) {
+	id, ok := taxonomy.aliases[Normalize(name)]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", genreErrors.ErrUnknownGenre, name)
+	}
+	return id, nil
+}
+
+// Canonical is like Resolve, but keeps unknown genres as normalized names
+func (taxonomy *Taxonomy) Canonical(name string) GenreID {
+	if id, err := taxonomy.Resolve(name); err == nil {
+		return id
+	}
+	return GenreID(Normalize(name))
+}
+
+func (taxonomy *Taxonomy) Get(id GenreID) (*Genre, bool) {
+	g, ok := taxonomy.genres[id]
+	if !ok {
+		return nil, false
+	}
+	return &g, true
+}
+
+// Ancestors returns parents of genre from the closest one to the root
+func (taxonomy *Taxonomy) Ancestors(id GenreID) []GenreID {
+	ancestors := make([]GenreID, 0)
+	for parent := taxonomy.genres[id].Parent; parent != NoParent; parent = taxonomy.genres[parent].Parent {
+		ancestors = append(ancestors, parent)
+	}
+	return ancestors
+}
+
+func (taxonomy *Taxonomy) Root(id GenreID) GenreID {
+	ancestors := taxonomy.Ancestors(id)
+	if len(ancestors) == 0 {
+		return id
+	}
+	return ancestors[len(ancestors)-1]
+}
+
+func (taxonomy *Taxonomy) SameFamily(first, second GenreID) bool {
+	return taxonomy.Root(first) == taxonomy.Root(second)
+}
@@ -0,0 +1,48 @@
+package genre
+
+import (
+	"testing"
+
+	genreErrors "github.com/rauzh/cd-core/genre/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaxonomy_Resolve(t *testing.T) {
+
+	taxonomy, err := DefaultTaxonomy()
+	assert.Nil(t, err)
+
+	for _, name := range []string{"Hip-Hop", "hip hop", "HIPHOP", "rap", " hip_hop "} {
+		id, err := taxonomy.Resolve(name)
+		assert.Nil(t, err, name)
+		assert.Equal(t, GenreID("hip-hop"), id, name)
+	}
+
+	_, err = taxonomy.Resolve("sea shanty")
+	assert.ErrorIs(t, err, genreErrors.ErrUnknownGenre)
+	assert.Equal(t, GenreID("sea-shanty"), taxonomy.Canonical("Sea Shanty"))
+}
+
+func TestTaxonomy_Hierarchy(t *testing.T) {
+
+	taxonomy, err := DefaultTaxonomy()
+	assert.Nil(t, err)
+
+	assert.Equal(t, []GenreID{"hip-hop"}, taxonomy.Ancestors("trap"))
+	assert.Equal(t, GenreID("hip-hop"), taxonomy.Root("trap"))
+	assert.Equal(t, GenreID("rock"), taxonomy.Root("rock"))
+	assert.True(t, taxonomy.SameFamily("trap", "drill"))
+	assert.False(t, taxonomy.SameFamily("trap", "house"))
+}
+
+func TestNewTaxonomy_Invalid(t *testing.T) {
+
+	_, err := NewTaxonomy([]Genre{{ID: "trap", Parent: "hip-hop"}})
+	assert.ErrorIs(t, err, genreErrors.ErrUnknownParent)
+
+	_, err = NewTaxonomy([]Genre{{ID: "a", Parent: "b"}, {ID: "b", Parent: "a"}})
+	assert.ErrorIs(t, err, genreErrors.ErrGenreCycle)
+
+	_, err = NewTaxonomy([]Genre{{ID: "hip-hop", Aliases: []string{"rap"}}, {ID: "rap"}})
+	assert.ErrorIs(t, err, genreErrors.ErrAliasConflict)
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// ChartEntity is what a chart ranks
+type ChartEntity string
+
+const (
+	TrackChart   ChartEntity = "tracks"
+	ReleaseChart ChartEntity = "releases"
+	ArtistChart  ChartEntity = "artists"
+)
+
+// ChartMetric is how a chart ranks
+type ChartMetric string
+
+const (
+	// StreamsMetric ranks by streams gained in the period
+	StreamsMetric ChartMetric = "streams"
+	// GrowthMetric ranks by streams gained against the previous period of the same length
+	GrowthMetric ChartMetric = "growth"
+)
+
+// Chart is a weekly snapshot, Week is the monday the chart week starts on
+type Chart struct {
+	ChartID   uint64
+	Entity    ChartEntity
+	Metric    ChartMetric
+	Week      time.Time
+	Entries   []ChartEntry
+	CreatedAt time.Time
+}
+
+type ChartEntry struct {
+	Position int
+	// EntityID is TrackID, ReleaseID or ArtistID
+	EntityID        uint64
+	Streams         uint64
+	PreviousStreams uint64
+	Growth          float64
+	// PreviousPosition is the position in the chart of the previous week, zero if the entity was not there
+	PreviousPosition int
+	// WeeksOnChart counts the current week, the streak restarts after a week off the chart
+	WeeksOnChart int
+}
+
+// Movement describes the position change like "up 3" or "new entry"
+func (entry *ChartEntry) Movement() string {
+	switch {
+	case entry.PreviousPosition == 0:
+		return "new entry"
+	case entry.Position < entry.PreviousPosition:
+		return fmt.Sprintf("up %d", entry.PreviousPosition-entry.Position)
+	case entry.Position > entry.PreviousPosition:
+		return fmt.Sprintf("down %d", entry.Position-entry.PreviousPosition)
+	default:
+		return "no change"
+	}
+}
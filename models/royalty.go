@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+const (
+	// FullShare is 100% in basis points
+	FullShare uint32 = 10000
+	// MicroUnits is the number of rate units in one minor unit of money
+	MicroUnits uint64 = 1_000_000
+)
+
+// StreamRate is paid by the platform per stream from ValidFrom till the next rate of the platform
+type StreamRate struct {
+	RateID   uint64
+	Platform string
+	// Country is empty for the platform default rate
+	Country string
+	// Currency is ISO 4217 code like "USD"
+	Currency string
+	// MicroPerStream is the rate in millionths of a minor unit, a stream pays a fraction of a cent
+	MicroPerStream uint64
+	ValidFrom      time.Time
+}
+
+// RoyaltyContract splits revenue of the artist releases. The label takes LabelShare,
+// every featured artist of a track takes FeaturedShare, the artist takes the rest.
+// Shares are in basis points of the track revenue
+type RoyaltyContract struct {
+	ContractID    uint64
+	ArtistID      uint64
+	LabelShare    uint32
+	FeaturedShare uint32
+	ValidFrom     time.Time
+	// ValidTo is exclusive, zero for open-ended contracts
+	ValidTo time.Time
+}
+
+// RoyaltyLedgerEntry is an amount computed for a closed period, entries are never changed.
+// ArtistID is zero for the label share
+type RoyaltyLedgerEntry struct {
+	EntryID    uint64
+	ArtistID   uint64
+	ContractID uint64
+	TrackID    uint64
+	Platform   string
+	PeriodFrom time.Time
+	PeriodTo   time.Time
+	Streams    uint64
+	Share      uint32
+	Currency   string
+	// Amount is in minor units like cents, rounded down
+	Amount int64
+	// AmountMicro is the exact amount in millionths of a minor unit.
+	// Statements sum these up, so fractions of a cent are not lost on every entry
+	AmountMicro int64
+	CreatedAt   time.Time
+}
+
+// RoyaltyStatement lists ledger entries of the artist for the period
+type RoyaltyStatement struct {
+	ArtistID uint64
+	From     time.Time
+	To       time.Time
+	Entries  []RoyaltyLedgerEntry
+	// Totals are amounts in minor units by currency, rounded down once per currency
+	Totals map[string]int64
+}
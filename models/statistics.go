@@ -1,6 +1,20 @@
 package models
 
-import "time"
+import (
+	"strings"
+	"time"
+)
+
+// StatisticsKind tells how Streams and Likes of a row are counted
+type StatisticsKind string
+
+const (
+	// CumulativeStatistics are totals since the track release up to the date.
+	// Rows without kind are cumulative, platforms report totals
+	CumulativeStatistics StatisticsKind = "cumulative"
+	// DeltaStatistics are increments for the date only, like in distributor reports
+	DeltaStatistics StatisticsKind = "delta"
+)
 
 type Statistics struct {
 	StatID  uint64
@@ -8,4 +22,74 @@ type Statistics struct {
 	Streams uint64
 	Likes   uint64
 	TrackID uint64
+	// Platform is the streaming platform the numbers come from
+	Platform string
+	// Country is ISO 3166-1 alpha-2 code of listeners, empty if the platform reports worldwide numbers
+	Country string
+	Kind    StatisticsKind
+}
+
+func (stat *Statistics) IsDelta() bool {
+	return stat.Kind == DeltaStatistics
+}
+
+// NormalizeCountry turns country codes from reports like " gb" into "GB"
+func NormalizeCountry(country string) string {
+	return strings.ToUpper(strings.TrimSpace(country))
+}
+
+// StatisticsWatermark is the latest date statistics of the release are stored for on every platform.
+// Platforms report with different lag, one date for all of them would drop late rows
+type StatisticsWatermark struct {
+	ReleaseID    uint64
+	FetchedUntil map[string]time.Time
+}
+
+// IsNew tells if stat is dated after the watermark of its platform
+func (watermark *StatisticsWatermark) IsNew(stat *Statistics) bool {
+	return stat.Date.After(watermark.FetchedUntil[stat.Platform])
+}
+
+// Advance moves the watermark of every platform to the latest date of its stats
+func (watermark *StatisticsWatermark) Advance(stats []Statistics) {
+	if watermark.FetchedUntil == nil {
+		watermark.FetchedUntil = make(map[string]time.Time)
+	}
+	for _, stat := range stats {
+		if stat.Date.After(watermark.FetchedUntil[stat.Platform]) {
+			watermark.FetchedUntil[stat.Platform] = stat.Date
+		}
+	}
+}
+
+// FetchedUntilDate tells if every given platform is fetched until date,
+// platform that was never fetched is not, e.g. one registered after the last fetch
+func (watermark *StatisticsWatermark) FetchedUntilDate(date time.Time, platforms []string) bool {
+	if len(platforms) == 0 {
+		return false
+	}
+	for _, platform := range platforms {
+		until, ok := watermark.FetchedUntil[platform]
+		if !ok || until.Before(date) {
+			return false
+		}
+	}
+	return true
+}
+
+// StatisticsFlag marks streams of a day that look like bot streams against the track baseline
+type StatisticsFlag struct {
+	FlagID   uint64
+	TrackID  uint64
+	Platform string
+	Country  string
+	Date     time.Time
+	// Streams are gained per day from the previous row of the series to Date
+	Streams uint64
+	// Median and MAD (median absolute deviation) of daily streams before Date
+	Median float64
+	MAD    float64
+	// Score is robust z-score of Streams
+	Score     float64
+	CreatedAt time.Time
 }
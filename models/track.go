@@ -7,4 +7,6 @@ type Track struct {
 	Genre    string
 	Type     string
 	Artists  []uint64
+	// ISRC is the international standard recording code, distributors report by it
+	ISRC string
 }
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type UserType int
 
 const (
@@ -15,4 +17,6 @@ type User struct {
 	Email    string
 	Password string
 	Type     UserType
+
+	DateCreation time.Time
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/rauzh/cd-core/genre"
 	"github.com/rauzh/cd-core/models"
 
 	releaseErrors "github.com/rauzh/cd-core/release/errors"
@@ -26,6 +27,7 @@ type ReleaseService struct {
 	trkSvc     trackService.ITrackService
 	repo       repo.ReleaseRepo
 	transactor transactor.Transactor
+	taxonomy   genre.ITaxonomy
 	logger     *slog.Logger
 }
 
@@ -33,8 +35,9 @@ func NewReleaseService(
 	trkSvc trackService.ITrackService,
 	transactor transactor.Transactor,
 	r repo.ReleaseRepo,
+	taxonomy genre.ITaxonomy,
 	logger *slog.Logger) IReleaseService {
-	return &ReleaseService{trkSvc: trkSvc, repo: r, transactor: transactor, logger: logger}
+	return &ReleaseService{trkSvc: trkSvc, repo: r, transactor: transactor, taxonomy: taxonomy, logger: logger}
 }
 
 func (rlsSvc *ReleaseService) validate(release *models.Release) error {
@@ -145,32 +148,34 @@ func (rlsSvc *ReleaseService) UpdateStatus(id uint64, stat models.ReleaseStatus)
 	return nil
 }
 
+// GetMainGenre returns the root genre most of release tracks belong to,
+// e.g. "hip-hop" for "trap" and "rap" tracks. Ties go to the smaller genre id
 func (rlsSvc *ReleaseService) GetMainGenre(releaseID uint64) (string, error) {
 	release, err := rlsSvc.repo.Get(context.Background(), releaseID)
 	if err != nil {
 		return "", fmt.Errorf("can't get release with err %w", err)
 	}
 
-	genres := make(map[string]int)
+	genres := make(map[genre.GenreID]int)
 	for _, trackID := range release.Tracks {
 		track, err := rlsSvc.trkSvc.Get(trackID)
 		if err != nil {
 			return "", fmt.Errorf("can't get track %d with err %w", trackID, err)
 		}
 
-		genres[track.Genre]++
+		genres[rlsSvc.taxonomy.Root(rlsSvc.taxonomy.Canonical(track.Genre))]++
 	}
 
 	var maxAmount int
-	var mainGenre string
-	for genre, amount := range genres {
-		if amount > maxAmount {
+	var mainGenre genre.GenreID
+	for genreID, amount := range genres {
+		if amount > maxAmount || (amount == maxAmount && genreID < mainGenre) {
 			maxAmount = amount
-			mainGenre = genre
+			mainGenre = genreID
 		}
 	}
 
-	return mainGenre, nil
+	return string(mainGenre), nil
 }
 
 //
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/rauzh/cd-core/genre"
+	"github.com/rauzh/cd-core/models"
+	"github.com/rauzh/cd-core/repo/mocks"
+	trackService "github.com/rauzh/cd-core/track/service"
+	transacMock "github.com/rauzh/cd-core/transactor/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReleaseService_GetMainGenre(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		genres []string
+		want   string
+	}{
+		{name: "RollUp", genres: []string{"trap", "Rap", "rock", "drill"}, want: "hip-hop"},
+		{name: "SubgenresOfOtherRoot", genres: []string{"punk", "metal", "hip-hop"}, want: "rock"},
+		{name: "Unknown", genres: []string{"Lo-Fi", "lo-fi", "jazz"}, want: "lo-fi"},
+		{name: "Tie", genres: []string{"rock", "soul", "jazz", "indie", "rnb"}, want: "r&b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			trkMockRepo := mocks.NewTrackRepo(t)
+			rlsMockRepo := mocks.NewReleaseRepo(t)
+
+			release := &models.Release{ReleaseID: 1}
+			for i, genreName := range tt.genres {
+				trackID := uint64(i + 1)
+				release.Tracks = append(release.Tracks, trackID)
+				trkMockRepo.EXPECT().Get(mock.AnythingOfType("context.backgroundCtx"), trackID).
+					Return(&models.Track{TrackID: trackID, Genre: genreName}, nil)
+			}
+			rlsMockRepo.EXPECT().Get(mock.AnythingOfType("context.backgroundCtx"), uint64(1)).Return(release, nil)
+
+			taxonomy, _ := genre.DefaultTaxonomy()
+			trkSvc := trackService.NewTrackService(trkMockRepo, taxonomy, slog.Default())
+			rlsSvc := NewReleaseService(trkSvc, transacMock.NewTransactor(t), rlsMockRepo, taxonomy, slog.Default())
+
+			// ties must not depend on map iteration order
+			for range 10 {
+				mainGenre, err := rlsSvc.GetMainGenre(1)
+				assert.Nil(t, err)
+				assert.Equal(t, tt.want, mainGenre)
+			}
+		})
+	}
+}
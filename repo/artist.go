@@ -11,5 +11,7 @@ type ArtistRepo interface {
 	Create(context.Context, *models.Artist) error
 	Get(context.Context, uint64) (*models.Artist, error)
 	GetByUserID(context.Context, uint64) (*models.Artist, error)
+	// GetByNickname matches nickname case insensitively, so "Artist" and "artist" are the same artist
+	GetByNickname(context.Context, string) (*models.Artist, error)
 	Update(context.Context, *models.Artist) error
 }
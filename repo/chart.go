@@ -0,0 +1,17 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/rauzh/cd-core/models"
+)
+
+// ChartRepo stores weekly chart snapshots with their entries,
+// GetForWeek returns repo_errors.ErrorNotExists if there is no snapshot for the week
+//
+//go:generate mockery --name ChartRepo --with-expecter
+type ChartRepo interface {
+	Create(context.Context, *models.Chart) error
+	GetForWeek(ctx context.Context, entity models.ChartEntity, metric models.ChartMetric, week time.Time) (*models.Chart, error)
+}
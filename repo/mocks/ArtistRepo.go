@@ -128,6 +128,65 @@ func (_c *ArtistRepo_Get_Call) RunAndReturn(run func(context.Context, uint64) (*
 	return _c
 }
 
+// GetByNickname provides a mock function with given fields: _a0, _a1
+func (_m *ArtistRepo) GetByNickname(_a0 context.Context, _a1 string) (*models.Artist, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByNickname")
+	}
+
+	var r0 *models.Artist
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.Artist, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Artist); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Artist)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ArtistRepo_GetByNickname_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByNickname'
+type ArtistRepo_GetByNickname_Call struct {
+	*mock.Call
+}
+
+// GetByNickname is a helper method to define mock.On call
+//   - _a0 context.Context
+//   - _a1 string
+func (_e *ArtistRepo_Expecter) GetByNickname(_a0 interface{}, _a1 interface{}) *ArtistRepo_GetByNickname_Call {
+	return &ArtistRepo_GetByNickname_Call{Call: _e.mock.On("GetByNickname", _a0, _a1)}
+}
+
+func (_c *ArtistRepo_GetByNickname_Call) Run(run func(_a0 context.Context, _a1 string)) *ArtistRepo_GetByNickname_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *ArtistRepo_GetByNickname_Call) Return(_a0 *models.Artist, _a1 error) *ArtistRepo_GetByNickname_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ArtistRepo_GetByNickname_Call) RunAndReturn(run func(context.Context, string) (*models.Artist, error)) *ArtistRepo_GetByNickname_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetByUserID provides a mock function with given fields: _a0, _a1
 func (_m *ArtistRepo) GetByUserID(_a0 context.Context, _a1 uint64) (*models.Artist, error) {
 	ret := _m.Called(_a0, _a1)
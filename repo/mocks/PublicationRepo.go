@@ -308,6 +308,65 @@ func (_c *PublicationRepo_GetAllByManager_Call) RunAndReturn(run func(context.Co
 	return _c
 }
 
+// GetAllUntilDate provides a mock function with given fields: ctx, date
+func (_m *PublicationRepo) GetAllUntilDate(ctx context.Context, date time.Time) ([]models.Publication, error) {
+	ret := _m.Called(ctx, date)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllUntilDate")
+	}
+
+	var r0 []models.Publication
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]models.Publication, error)); ok {
+		return rf(ctx, date)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []models.Publication); ok {
+		r0 = rf(ctx, date)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Publication)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, date)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PublicationRepo_GetAllUntilDate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllUntilDate'
+type PublicationRepo_GetAllUntilDate_Call struct {
+	*mock.Call
+}
+
+// GetAllUntilDate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - date time.Time
+func (_e *PublicationRepo_Expecter) GetAllUntilDate(ctx interface{}, date interface{}) *PublicationRepo_GetAllUntilDate_Call {
+	return &PublicationRepo_GetAllUntilDate_Call{Call: _e.mock.On("GetAllUntilDate", ctx, date)}
+}
+
+func (_c *PublicationRepo_GetAllUntilDate_Call) Run(run func(ctx context.Context, date time.Time)) *PublicationRepo_GetAllUntilDate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *PublicationRepo_GetAllUntilDate_Call) Return(_a0 []models.Publication, _a1 error) *PublicationRepo_GetAllUntilDate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *PublicationRepo_GetAllUntilDate_Call) RunAndReturn(run func(context.Context, time.Time) ([]models.Publication, error)) *PublicationRepo_GetAllUntilDate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Update provides a mock function with given fields: _a0, _a1
 func (_m *PublicationRepo) Update(_a0 context.Context, _a1 *models.Publication) error {
 	ret := _m.Called(_a0, _a1)
@@ -295,6 +295,127 @@ func (_c *StatisticsRepo_GetForTrack_Call) RunAndReturn(run func(context.Context
 	return _c
 }
 
+// GetForTracksBetween provides a mock function with given fields: ctx, trackIDs, from, to
+func (_m *StatisticsRepo) GetForTracksBetween(ctx context.Context, trackIDs []uint64, from time.Time, to time.Time) ([]models.Statistics, error) {
+	ret := _m.Called(ctx, trackIDs, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetForTracksBetween")
+	}
+
+	var r0 []models.Statistics
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uint64, time.Time, time.Time) ([]models.Statistics, error)); ok {
+		return rf(ctx, trackIDs, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []uint64, time.Time, time.Time) []models.Statistics); ok {
+		r0 = rf(ctx, trackIDs, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Statistics)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []uint64, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, trackIDs, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StatisticsRepo_GetForTracksBetween_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetForTracksBetween'
+type StatisticsRepo_GetForTracksBetween_Call struct {
+	*mock.Call
+}
+
+// GetForTracksBetween is a helper method to define mock.On call
+//   - ctx context.Context
+//   - trackIDs []uint64
+//   - from time.Time
+//   - to time.Time
+func (_e *StatisticsRepo_Expecter) GetForTracksBetween(ctx interface{}, trackIDs interface{}, from interface{}, to interface{}) *StatisticsRepo_GetForTracksBetween_Call {
+	return &StatisticsRepo_GetForTracksBetween_Call{Call: _e.mock.On("GetForTracksBetween", ctx, trackIDs, from, to)}
+}
+
+func (_c *StatisticsRepo_GetForTracksBetween_Call) Run(run func(ctx context.Context, trackIDs []uint64, from time.Time, to time.Time)) *StatisticsRepo_GetForTracksBetween_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]uint64), args[2].(time.Time), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *StatisticsRepo_GetForTracksBetween_Call) Return(_a0 []models.Statistics, _a1 error) *StatisticsRepo_GetForTracksBetween_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StatisticsRepo_GetForTracksBetween_Call) RunAndReturn(run func(context.Context, []uint64, time.Time, time.Time) ([]models.Statistics, error)) *StatisticsRepo_GetForTracksBetween_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLastForTracksBefore provides a mock function with given fields: ctx, trackIDs, date
+func (_m *StatisticsRepo) GetLastForTracksBefore(ctx context.Context, trackIDs []uint64, date time.Time) ([]models.Statistics, error) {
+	ret := _m.Called(ctx, trackIDs, date)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLastForTracksBefore")
+	}
+
+	var r0 []models.Statistics
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uint64, time.Time) ([]models.Statistics, error)); ok {
+		return rf(ctx, trackIDs, date)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []uint64, time.Time) []models.Statistics); ok {
+		r0 = rf(ctx, trackIDs, date)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Statistics)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []uint64, time.Time) error); ok {
+		r1 = rf(ctx, trackIDs, date)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StatisticsRepo_GetLastForTracksBefore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLastForTracksBefore'
+type StatisticsRepo_GetLastForTracksBefore_Call struct {
+	*mock.Call
+}
+
+// GetLastForTracksBefore is a helper method to define mock.On call
+//   - ctx context.Context
+//   - trackIDs []uint64
+//   - date time.Time
+func (_e *StatisticsRepo_Expecter) GetLastForTracksBefore(ctx interface{}, trackIDs interface{}, date interface{}) *StatisticsRepo_GetLastForTracksBefore_Call {
+	return &StatisticsRepo_GetLastForTracksBefore_Call{Call: _e.mock.On("GetLastForTracksBefore", ctx, trackIDs, date)}
+}
+
+func (_c *StatisticsRepo_GetLastForTracksBefore_Call) Run(run func(ctx context.Context, trackIDs []uint64, date time.Time)) *StatisticsRepo_GetLastForTracksBefore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]uint64), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *StatisticsRepo_GetLastForTracksBefore_Call) Return(_a0 []models.Statistics, _a1 error) *StatisticsRepo_GetLastForTracksBefore_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StatisticsRepo_GetLastForTracksBefore_Call) RunAndReturn(run func(context.Context, []uint64, time.Time) ([]models.Statistics, error)) *StatisticsRepo_GetLastForTracksBefore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewStatisticsRepo creates a new instance of StatisticsRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewStatisticsRepo(t interface {
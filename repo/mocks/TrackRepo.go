@@ -138,6 +138,125 @@ func (_c *TrackRepo_Get_Call) RunAndReturn(run func(context.Context, uint64) (*m
 	return _c
 }
 
+// GetByISRC provides a mock function with given fields: ctx, isrc
+func (_m *TrackRepo) GetByISRC(ctx context.Context, isrc string) (*models.Track, error) {
+	ret := _m.Called(ctx, isrc)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByISRC")
+	}
+
+	var r0 *models.Track
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.Track, error)); ok {
+		return rf(ctx, isrc)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Track); ok {
+		r0 = rf(ctx, isrc)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Track)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, isrc)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TrackRepo_GetByISRC_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByISRC'
+type TrackRepo_GetByISRC_Call struct {
+	*mock.Call
+}
+
+// GetByISRC is a helper method to define mock.On call
+//   - ctx context.Context
+//   - isrc string
+func (_e *TrackRepo_Expecter) GetByISRC(ctx interface{}, isrc interface{}) *TrackRepo_GetByISRC_Call {
+	return &TrackRepo_GetByISRC_Call{Call: _e.mock.On("GetByISRC", ctx, isrc)}
+}
+
+func (_c *TrackRepo_GetByISRC_Call) Run(run func(ctx context.Context, isrc string)) *TrackRepo_GetByISRC_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *TrackRepo_GetByISRC_Call) Return(_a0 *models.Track, _a1 error) *TrackRepo_GetByISRC_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TrackRepo_GetByISRC_Call) RunAndReturn(run func(context.Context, string) (*models.Track, error)) *TrackRepo_GetByISRC_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByTitleAndArtist provides a mock function with given fields: ctx, title, artistID
+func (_m *TrackRepo) GetByTitleAndArtist(ctx context.Context, title string, artistID uint64) (*models.Track, error) {
+	ret := _m.Called(ctx, title, artistID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByTitleAndArtist")
+	}
+
+	var r0 *models.Track
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64) (*models.Track, error)); ok {
+		return rf(ctx, title, artistID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64) *models.Track); ok {
+		r0 = rf(ctx, title, artistID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Track)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint64) error); ok {
+		r1 = rf(ctx, title, artistID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TrackRepo_GetByTitleAndArtist_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByTitleAndArtist'
+type TrackRepo_GetByTitleAndArtist_Call struct {
+	*mock.Call
+}
+
+// GetByTitleAndArtist is a helper method to define mock.On call
+//   - ctx context.Context
+//   - title string
+//   - artistID uint64
+func (_e *TrackRepo_Expecter) GetByTitleAndArtist(ctx interface{}, title interface{}, artistID interface{}) *TrackRepo_GetByTitleAndArtist_Call {
+	return &TrackRepo_GetByTitleAndArtist_Call{Call: _e.mock.On("GetByTitleAndArtist", ctx, title, artistID)}
+}
+
+func (_c *TrackRepo_GetByTitleAndArtist_Call) Run(run func(ctx context.Context, title string, artistID uint64)) *TrackRepo_GetByTitleAndArtist_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(uint64))
+	})
+	return _c
+}
+
+func (_c *TrackRepo_GetByTitleAndArtist_Call) Return(_a0 *models.Track, _a1 error) *TrackRepo_GetByTitleAndArtist_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TrackRepo_GetByTitleAndArtist_Call) RunAndReturn(run func(context.Context, string, uint64) (*models.Track, error)) *TrackRepo_GetByTitleAndArtist_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewTrackRepo creates a new instance of TrackRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewTrackRepo(t interface {
@@ -14,5 +14,7 @@ type PublicationRepo interface {
 	GetAllByDate(context.Context, time.Time) ([]models.Publication, error)
 	GetAllByManager(ctx context.Context, mng uint64) ([]models.Publication, error)
 	GetAllByArtistSinceDate(ctx context.Context, date time.Time, artistID uint64) ([]models.Publication, error)
+	// GetAllUntilDate returns publications dated up to date, i.e. already published
+	GetAllUntilDate(ctx context.Context, date time.Time) ([]models.Publication, error)
 	Update(context.Context, *models.Publication) error
 }
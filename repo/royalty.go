@@ -0,0 +1,32 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/rauzh/cd-core/models"
+)
+
+//go:generate mockery --name StreamRateRepo --with-expecter
+type StreamRateRepo interface {
+	Create(context.Context, *models.StreamRate) error
+	// GetAllUntil returns all rates valid from date or earlier
+	GetAllUntil(ctx context.Context, date time.Time) ([]models.StreamRate, error)
+}
+
+//go:generate mockery --name RoyaltyContractRepo --with-expecter
+type RoyaltyContractRepo interface {
+	Create(context.Context, *models.RoyaltyContract) error
+	GetForArtist(ctx context.Context, artistID uint64) ([]models.RoyaltyContract, error)
+}
+
+// RoyaltyLedgerRepo is append only, entries are never updated or deleted
+//
+//go:generate mockery --name RoyaltyLedgerRepo --with-expecter
+type RoyaltyLedgerRepo interface {
+	Append(context.Context, []models.RoyaltyLedgerEntry) error
+	// GetLastPeriodEnd returns zero time if no period is closed yet
+	GetLastPeriodEnd(ctx context.Context) (time.Time, error)
+	// GetForArtist returns entries of periods within from and to inclusive
+	GetForArtist(ctx context.Context, artistID uint64, from, to time.Time) ([]models.RoyaltyLedgerEntry, error)
+}
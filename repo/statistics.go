@@ -14,4 +14,8 @@ type StatisticsRepo interface {
 	GetByID(context.Context, uint64) (*models.Statistics, error)
 	GetAllGroupByTracksSince(ctx context.Context, date time.Time) (*map[uint64][]models.Statistics, error)
 	CreateMany(context.Context, []models.Statistics) error
+	// GetForTracksBetween returns stats of tracks dated from from to to inclusive
+	GetForTracksBetween(ctx context.Context, trackIDs []uint64, from, to time.Time) ([]models.Statistics, error)
+	// GetLastForTracksBefore returns the latest row of every track, platform and country dated before date
+	GetLastForTracksBefore(ctx context.Context, trackIDs []uint64, date time.Time) ([]models.Statistics, error)
 }
@@ -0,0 +1,13 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/rauzh/cd-core/models"
+)
+
+//go:generate mockery --name StatisticsFlagRepo --with-expecter
+type StatisticsFlagRepo interface {
+	CreateMany(context.Context, []models.StatisticsFlag) error
+	GetForTrack(ctx context.Context, trackID uint64) ([]models.StatisticsFlag, error)
+}
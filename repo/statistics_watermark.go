@@ -0,0 +1,15 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/rauzh/cd-core/models"
+)
+
+// StatisticsWatermarkRepo returns repo_errors.ErrorNotExists for releases that were never fetched
+//
+//go:generate mockery --name StatisticsWatermarkRepo --with-expecter
+type StatisticsWatermarkRepo interface {
+	Get(ctx context.Context, releaseID uint64) (*models.StatisticsWatermark, error)
+	Upsert(context.Context, *models.StatisticsWatermark) error
+}
@@ -10,4 +10,6 @@ import (
 type TrackRepo interface {
 	Create(context.Context, *models.Track) (uint64, error)
 	Get(context.Context, uint64) (*models.Track, error)
+	GetByISRC(ctx context.Context, isrc string) (*models.Track, error)
+	GetByTitleAndArtist(ctx context.Context, title string, artistID uint64) (*models.Track, error)
 }
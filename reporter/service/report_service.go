@@ -2,6 +2,7 @@ package service
 
 import (
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"time"
 
@@ -11,7 +12,17 @@ import (
 	managerService "github.com/rauzh/cd-core/manager/service"
 	publicationService "github.com/rauzh/cd-core/publication/service"
 	releaseService "github.com/rauzh/cd-core/release/service"
+	"github.com/rauzh/cd-core/statistics/convert"
+	"github.com/rauzh/cd-core/statistics/forecast"
 	statisticsServive "github.com/rauzh/cd-core/statistics/service"
+	cdtime "github.com/rauzh/cd-core/time"
+)
+
+const (
+	// ForecastKey is the report section with expected streams for the next quarter
+	ForecastKey = "streams_forecast"
+	// BreakdownKey is the report section with streams of the relevant period by country and platform
+	BreakdownKey = "streams_breakdown"
 )
 
 type IReportService interface {
@@ -64,6 +75,18 @@ func (rptSvc *ReportServiceJSON) GetReportForManager(mngID uint64) (map[string][
 
 	report["artists_stats"] = artistStatsJson
 
+	forecastJSON, err := rptSvc.getForecastsForManager(mngID)
+	if err != nil {
+		return nil, err
+	}
+	report[ForecastKey] = forecastJSON
+
+	breakdownJSON, err := rptSvc.getBreakdownsForManager(mngID)
+	if err != nil {
+		return nil, err
+	}
+	report[BreakdownKey] = breakdownJSON
+
 	return report, nil
 }
 
@@ -78,6 +101,7 @@ func (rptSvc *ReportServiceJSON) GetReportForArtist(artistID uint64) (map[string
 		return nil, err
 	}
 
+	releaseForecasts := make(map[string]releaseForecast)
 	for _, release := range releases {
 		tracks, err := rptSvc.rlsSvc.GetAllTracks(&release)
 		if err != nil {
@@ -98,7 +122,42 @@ func (rptSvc *ReportServiceJSON) GetReportForArtist(artistID uint64) (map[string
 			return nil, err
 		}
 		report[release.Title] = releaseStatsJson
+
+		rlsForecast, err := rptSvc.getReleaseForecast(release.ReleaseID)
+		if err != nil {
+			return nil, err
+		}
+		if rlsForecast == nil {
+			continue
+		}
+
+		tracksForecast := make(map[string]uint64)
+		for _, track := range tracks {
+			if trackForecast, ok := rlsForecast.Tracks[track.TrackID]; ok {
+				tracksForecast[track.Title] = trackForecast.TotalStreams
+			}
+		}
+		releaseForecasts[release.Title] = releaseForecast{
+			TotalStreams: rlsForecast.TotalStreams,
+			Tracks:       tracksForecast,
+		}
+	}
+
+	forecastJSON, err := json.Marshal(releaseForecasts)
+	if err != nil {
+		return nil, err
+	}
+	report[ForecastKey] = forecastJSON
+
+	breakdown, err := rptSvc.getArtistBreakdown(artistID)
+	if err != nil {
+		return nil, err
 	}
+	breakdownJSON, err := json.Marshal(breakdown)
+	if err != nil {
+		return nil, err
+	}
+	report[BreakdownKey] = breakdownJSON
 
 	return report, nil
 }
@@ -170,19 +229,18 @@ func (rptSvc *ReportServiceJSON) getAllArtistsStatsForManager(mngID uint64) ([]b
 				return nil, err
 			}
 
-			var closestToLastSeasonDate time.Time
-			lastSeasonStat := models.Statistics{}
-			latestStatDate := stats[0].Date
-			latestStat := stats[0]
-			for _, stat := range stats {
-				if stat.Date.After(latestStatDate) {
-					latestStatDate = stat.Date
-					latestStat = stat
-				}
+			// season to season comparison needs totals, deltas and platforms are summed up
+			totals := convert.Totals(stats)
+			if len(totals) == 0 {
+				continue
+			}
 
-				lastSeasonStatDate := latestStatDate.AddDate(0, -3, 0)
-				if stat.Date.Before(lastSeasonStatDate) && stat.Date.After(closestToLastSeasonDate) {
-					closestToLastSeasonDate = stat.Date
+			latestStat := totals[len(totals)-1]
+			lastSeasonStatDate := latestStat.Date.AddDate(0, -3, 0)
+
+			lastSeasonStat := models.Statistics{}
+			for _, stat := range totals {
+				if stat.Date.Before(lastSeasonStatDate) {
 					lastSeasonStat = stat
 				}
 			}
@@ -200,3 +258,117 @@ func (rptSvc *ReportServiceJSON) getAllArtistsStatsForManager(mngID uint64) ([]b
 
 	return artistStatsJSON, nil
 }
+
+type releaseForecast struct {
+	TotalStreams uint64            `json:"total_streams"`
+	Tracks       map[string]uint64 `json:"tracks"`
+}
+
+// getReleaseForecast returns nil if the release is too fresh to forecast
+func (rptSvc *ReportServiceJSON) getReleaseForecast(releaseID uint64) (*statisticsServive.Forecast, error) {
+
+	rlsForecast, err := rptSvc.statSvc.ReleaseForecast(releaseID, statisticsServive.QuarterHorizon)
+	if errors.Is(err, forecast.ErrNotEnoughHistory) {
+		rptSvc.logger.Debug("REPORT SERVICE no forecast", "release_id", releaseID)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return rlsForecast, nil
+}
+
+// getForecastsForManager returns expected streams of published releases by artist and release
+func (rptSvc *ReportServiceJSON) getForecastsForManager(mngID uint64) ([]byte, error) {
+
+	pubs, err := rptSvc.pbcSvc.GetAllByManager(mngID)
+	if err != nil {
+		return nil, err
+	}
+
+	currentDate := time.Now().UTC()
+
+	forecasts := make(map[string]map[string]uint64)
+	for _, pub := range pubs {
+
+		if pub.Date.After(currentDate) {
+			continue
+		}
+
+		release, err := rptSvc.rlsSvc.Get(pub.ReleaseID)
+		if err != nil {
+			return nil, err
+		}
+
+		rlsForecast, err := rptSvc.getReleaseForecast(release.ReleaseID)
+		if err != nil {
+			return nil, err
+		}
+		if rlsForecast == nil {
+			continue
+		}
+
+		artist, err := rptSvc.artSvc.Get(release.ArtistID)
+		if err != nil {
+			return nil, err
+		}
+
+		if forecasts[artist.Nickname] == nil {
+			forecasts[artist.Nickname] = make(map[string]uint64)
+		}
+		forecasts[artist.Nickname][release.Title] = rlsForecast.TotalStreams
+	}
+
+	return json.Marshal(forecasts)
+}
+
+type artistBreakdown struct {
+	Countries []statisticsServive.BreakdownRow `json:"countries"`
+	Platforms []statisticsServive.BreakdownRow `json:"platforms"`
+}
+
+// getArtistBreakdown compares the relevant period with the previous one by country and platform
+func (rptSvc *ReportServiceJSON) getArtistBreakdown(artistID uint64) (*artistBreakdown, error) {
+
+	query := statisticsServive.BreakdownQuery{From: cdtime.RelevantPeriod(), Dimension: statisticsServive.ByCountry}
+	countries, err := rptSvc.statSvc.ArtistBreakdown(artistID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	query.Dimension = statisticsServive.ByPlatform
+	platforms, err := rptSvc.statSvc.ArtistBreakdown(artistID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	breakdown := &artistBreakdown{Countries: countries.Rows, Platforms: platforms.Rows}
+
+	return breakdown, nil
+}
+
+// getBreakdownsForManager returns breakdowns of the manager artists by nickname
+func (rptSvc *ReportServiceJSON) getBreakdownsForManager(mngID uint64) ([]byte, error) {
+
+	manager, err := rptSvc.mngSvc.Get(mngID)
+	if err != nil {
+		return nil, err
+	}
+
+	breakdowns := make(map[string]*artistBreakdown)
+	for _, artistID := range manager.Artists {
+		artist, err := rptSvc.artSvc.Get(artistID)
+		if err != nil {
+			return nil, err
+		}
+
+		breakdown, err := rptSvc.getArtistBreakdown(artistID)
+		if err != nil {
+			return nil, err
+		}
+		breakdowns[artist.Nickname] = breakdown
+	}
+
+	return json.Marshal(breakdowns)
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/rauzh/cd-core/genre"
 	"github.com/rauzh/cd-core/models"
 
 	artService "github.com/rauzh/cd-core/artist/service"
@@ -15,6 +16,7 @@ import (
 	mocks "github.com/rauzh/cd-core/repo/mocks"
 	statFetcher "github.com/rauzh/cd-core/statistics/fetcher/mocks"
 	statService "github.com/rauzh/cd-core/statistics/service"
+	cdtime "github.com/rauzh/cd-core/time"
 	trackService "github.com/rauzh/cd-core/track/service"
 	transacMock "github.com/rauzh/cd-core/transactor/mocks"
 	"github.com/stretchr/testify/assert"
@@ -56,7 +58,7 @@ func TestReportServiceJSON_GetReportForArtist(t *testing.T) {
 			release1,
 			release2,
 		},
-		nil).Once()
+		nil).Times(3)
 
 	rlsMockRepo.EXPECT().GetAllTracks(mock.AnythingOfType("context.backgroundCtx"), &release1).Return(
 		[]models.Track{
@@ -192,19 +194,76 @@ func TestReportServiceJSON_GetReportForArtist(t *testing.T) {
 			},
 		}, nil).Once()
 
+	rlsMockRepo.EXPECT().Get(mock.AnythingOfType("context.backgroundCtx"), uint64(1)).Return(&release1, nil).Once()
+	rlsMockRepo.EXPECT().Get(mock.AnythingOfType("context.backgroundCtx"), uint64(2)).Return(&release2, nil).Once()
+
+	// album 1 has no stats for the last year, track 21 gains 500 streams a week
+	weekAgo := now.AddDate(0, 0, -7)
+	weeklyStats := make([]models.Statistics, 0)
+	for week := 0; week < 4; week++ {
+		weeklyStats = append(weeklyStats, models.Statistics{
+			TrackID: 21, Date: weekAgo.AddDate(0, 0, -7*week), Streams: 500, Kind: models.DeltaStatistics,
+		})
+	}
+
+	statMockRepo.EXPECT().GetLastForTracksBefore(mock.AnythingOfType("context.backgroundCtx"), release1.Tracks, mock.Anything).Return(
+		nil, nil).Once()
+	statMockRepo.EXPECT().GetLastForTracksBefore(mock.AnythingOfType("context.backgroundCtx"), release2.Tracks, mock.Anything).Return(
+		nil, nil).Once()
+	statMockRepo.EXPECT().GetForTracksBetween(mock.AnythingOfType("context.backgroundCtx"), release1.Tracks, mock.Anything, mock.Anything).Return(
+		nil, nil).Once()
+	statMockRepo.EXPECT().GetForTracksBetween(mock.AnythingOfType("context.backgroundCtx"), release2.Tracks, mock.Anything, mock.Anything).Return(
+		weeklyStats, nil).Once()
+
+	// streams by country of the relevant period and the previous one
+	allTracks := append(append([]uint64{}, release1.Tracks...), release2.Tracks...)
+	current, previous := cdtime.GetToday().AddDate(0, 0, -10), cdtime.RelevantPeriod().AddDate(0, 0, -10)
+	countryStats := []models.Statistics{
+		{TrackID: 21, Platform: "spotify", Country: "DE", Date: previous, Streams: 100, Kind: models.DeltaStatistics},
+		{TrackID: 21, Platform: "spotify", Country: "DE", Date: current, Streams: 300, Kind: models.DeltaStatistics},
+		{TrackID: 22, Platform: "spotify", Country: "BR", Date: current, Streams: 200, Kind: models.DeltaStatistics},
+	}
+
+	statMockRepo.EXPECT().GetLastForTracksBefore(mock.AnythingOfType("context.backgroundCtx"), allTracks, mock.Anything).Return(
+		nil, nil).Twice()
+	statMockRepo.EXPECT().GetForTracksBetween(mock.AnythingOfType("context.backgroundCtx"), allTracks, mock.Anything, mock.Anything).Return(
+		countryStats, nil).Twice()
+
 	statMockFetcher := statFetcher.NewStatFetcher(t)
 
-	trkSvc := trackService.NewTrackService(trkMockRepo, slog.Default())
+	taxonomy, _ := genre.DefaultTaxonomy()
+	trkSvc := trackService.NewTrackService(trkMockRepo, taxonomy, slog.Default())
 	mngSvc := mngService.NewManagerService(mockMngRepo, slog.Default())
 	artSvc := artService.NewArtistService(mockArtRepo, slog.Default())
 	pbcSvc := pbcService.NewPublicationService(pbcMockRepo, slog.Default())
-	rlsSvc := rlsService.NewReleaseService(trkSvc, transactionMock, rlsMockRepo, slog.Default())
-	statSvc := statService.NewStatisticsService(trkSvc, statMockFetcher, statMockRepo, rlsSvc, slog.Default())
+	rlsSvc := rlsService.NewReleaseService(trkSvc, transactionMock, rlsMockRepo, taxonomy, slog.Default())
+	statSvc := statService.NewStatisticsService(trkSvc, statMockFetcher, statMockRepo, rlsSvc, taxonomy, slog.Default())
 
 	rptSvc := NewReportService(mngSvc, statSvc, artSvc, pbcSvc, rlsSvc, slog.Default())
 
 	report, err := rptSvc.GetReportForArtist(777)
 
+	forecastReport := make(map[string]releaseForecast)
+	_ = json.Unmarshal(report[ForecastKey], &forecastReport)
+	assert.Equal(t, map[string]releaseForecast{
+		"album 2": {
+			TotalStreams: 500 * statService.QuarterHorizon,
+			Tracks:       map[string]uint64{"track 21": 500 * statService.QuarterHorizon},
+		},
+	}, forecastReport)
+	delete(report, ForecastKey)
+
+	breakdownReport := artistBreakdown{}
+	_ = json.Unmarshal(report[BreakdownKey], &breakdownReport)
+	assert.Equal(t, []statService.BreakdownRow{
+		{Key: "DE", Streams: 300, PreviousStreams: 100, Growth: 2},
+		{Key: "BR", Streams: 200, New: true},
+	}, breakdownReport.Countries)
+	assert.Equal(t, []statService.BreakdownRow{
+		{Key: "spotify", Streams: 500, PreviousStreams: 100, Growth: 4},
+	}, breakdownReport.Platforms)
+	delete(report, BreakdownKey)
+
 	reportMap := make(map[string]map[string]uint64)
 	for releaseName, releaseStatsJSON := range report {
 		releaseStats := make(map[string]uint64)
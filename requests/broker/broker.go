@@ -8,6 +8,10 @@ import (
 var (
 	ErrNoBroker   error = errors.New("no broker")
 	ErrNoConsumer error = errors.New("no consumer")
+	// ErrNoRequestID is returned for consumer config with Dedup but without RequestID
+	ErrNoRequestID error = errors.New("deduplication requires request id")
+	// ErrForwardFailed is returned by RetryPolicy when failed message is not moved to retry or dead letter topic
+	ErrForwardFailed error = errors.New("can't forward failed message")
 )
 
 //go:generate mockery --name IBroker --with-expecter
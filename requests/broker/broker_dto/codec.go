@@ -0,0 +1,100 @@
+package broker_dto
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/IBM/sarama"
+	"github.com/rauzh/cd-core/requests/broker"
+)
+
+var (
+	ErrUnknownCodec   = errors.New("unknown message codec")
+	ErrUnsupportedDTO = errors.New("codec doesn't support message type")
+)
+
+// Codec encodes envelope and payload of request messages.
+// Codec name is carried in broker.HeaderCodec, so consumers decode
+// mixed traffic while topic codec is migrated
+type Codec interface {
+	Name() string
+
+	MarshalEnvelope(*Envelope) ([]byte, error)
+	// UnmarshalEnvelope decodes envelope, schema is used for messages produced before envelopes
+	UnmarshalEnvelope(value []byte, schema string) (*Envelope, error)
+
+	MarshalPayload(dto any) ([]byte, error)
+	UnmarshalPayload(payload []byte, dto any) error
+}
+
+// CodecRegistry selects codec to produce by topic and codec to consume by header.
+// Messages without header were produced before codecs and are decoded by the fallback
+type CodecRegistry struct {
+	mu sync.RWMutex
+
+	codecs   map[string]Codec
+	topics   map[string]string
+	fallback Codec
+}
+
+// Codecs is the registry of request messages, topics are produced with json unless configured
+var Codecs = NewCodecRegistry(JSONCodec{}, ProtobufCodec{}, AvroCodec{})
+
+func NewCodecRegistry(fallback Codec, codecs ...Codec) *CodecRegistry {
+	registry := &CodecRegistry{
+		codecs:   map[string]Codec{fallback.Name(): fallback},
+		topics:   make(map[string]string),
+		fallback: fallback,
+	}
+	for _, codec := range codecs {
+		registry.codecs[codec.Name()] = codec
+	}
+	return registry
+}
+
+// SetTopicCodec makes topic produced with the codec
+func (registry *CodecRegistry) SetTopicCodec(topic, name string) error {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if _, ok := registry.codecs[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownCodec, name)
+	}
+	registry.topics[topic] = name
+	return nil
+}
+
+// ResetTopicCodec makes topic produced with the fallback codec again
+func (registry *CodecRegistry) ResetTopicCodec(topic string) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	delete(registry.topics, topic)
+}
+
+func (registry *CodecRegistry) ForTopic(topic string) Codec {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	if name, ok := registry.topics[topic]; ok {
+		return registry.codecs[name]
+	}
+	return registry.fallback
+}
+
+func (registry *CodecRegistry) ForMessage(msg *sarama.ConsumerMessage) (Codec, error) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	name, ok := broker.GetHeader(msg, broker.HeaderCodec)
+	if !ok {
+		return registry.fallback, nil
+	}
+
+	codec, ok := registry.codecs[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCodec, name)
+	}
+	return codec, nil
+}
@@ -0,0 +1,200 @@
+package broker_dto
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hamba/avro/v2"
+	"github.com/rauzh/cd-core/requests/base"
+)
+
+const AvroCodecName = "avro"
+
+var (
+	avroEnvelopeSchema = avro.MustParse(`{
+		"type": "record", "name": "Envelope", "namespace": "cdcore.requests",
+		"fields": [
+			{"name": "schema", "type": "string"},
+			{"name": "version", "type": "int"},
+			{"name": "message_id", "type": "string"},
+			{"name": "produced_at", "type": {"type": "long", "logicalType": "timestamp-micros"}},
+			{"name": "correlation_id", "type": "string"},
+			{"name": "payload", "type": "bytes"}
+		]
+	}`)
+
+	avroPublishReqSchema = avro.MustParse(`{
+		"type": "record", "name": "PublishRequest", "namespace": "cdcore.requests",
+		"fields": [
+			{"name": "request_id", "type": "long"},
+			{"name": "type", "type": "string"},
+			{"name": "status", "type": "string"},
+			{"name": "date", "type": {"type": "long", "logicalType": "timestamp-micros"}},
+			{"name": "applier_id", "type": "long"},
+			{"name": "manager_id", "type": "long"},
+			{"name": "release_id", "type": "long"},
+			{"name": "grade", "type": "long"},
+			{"name": "expected_date", "type": {"type": "long", "logicalType": "timestamp-micros"}},
+			{"name": "description", "type": "string"}
+		]
+	}`)
+
+	avroSignContractReqSchema = avro.MustParse(`{
+		"type": "record", "name": "SignContractRequest", "namespace": "cdcore.requests",
+		"fields": [
+			{"name": "request_id", "type": "long"},
+			{"name": "type", "type": "string"},
+			{"name": "status", "type": "string"},
+			{"name": "date", "type": {"type": "long", "logicalType": "timestamp-micros"}},
+			{"name": "applier_id", "type": "long"},
+			{"name": "manager_id", "type": "long"},
+			{"name": "nickname", "type": "string"},
+			{"name": "grade", "type": "long"},
+			{"name": "description", "type": "string"}
+		]
+	}`)
+)
+
+// AvroCodec encodes messages with the schemas above, avro has no unsigned
+// types, so ids are written as long
+type AvroCodec struct{}
+
+type avroEnvelope struct {
+	Schema        string    `avro:"schema"`
+	Version       int       `avro:"version"`
+	MessageID     string    `avro:"message_id"`
+	ProducedAt    time.Time `avro:"produced_at"`
+	CorrelationID string    `avro:"correlation_id"`
+	Payload       []byte    `avro:"payload"`
+}
+
+type avroPublishReq struct {
+	RequestID    int64     `avro:"request_id"`
+	Type         string    `avro:"type"`
+	Status       string    `avro:"status"`
+	Date         time.Time `avro:"date"`
+	ApplierID    int64     `avro:"applier_id"`
+	ManagerID    int64     `avro:"manager_id"`
+	ReleaseID    int64     `avro:"release_id"`
+	Grade        int64     `avro:"grade"`
+	ExpectedDate time.Time `avro:"expected_date"`
+	Description  string    `avro:"description"`
+}
+
+type avroSignContractReq struct {
+	RequestID   int64     `avro:"request_id"`
+	Type        string    `avro:"type"`
+	Status      string    `avro:"status"`
+	Date        time.Time `avro:"date"`
+	ApplierID   int64     `avro:"applier_id"`
+	ManagerID   int64     `avro:"manager_id"`
+	Nickname    string    `avro:"nickname"`
+	Grade       int64     `avro:"grade"`
+	Description string    `avro:"description"`
+}
+
+func (AvroCodec) Name() string {
+	return AvroCodecName
+}
+
+func (AvroCodec) MarshalEnvelope(env *Envelope) ([]byte, error) {
+	return avro.Marshal(avroEnvelopeSchema, &avroEnvelope{
+		Schema:        env.Schema,
+		Version:       env.Version,
+		MessageID:     env.MessageID,
+		ProducedAt:    env.ProducedAt,
+		CorrelationID: env.CorrelationID,
+		Payload:       env.Payload,
+	})
+}
+
+func (AvroCodec) UnmarshalEnvelope(value []byte, schema string) (*Envelope, error) {
+
+	env := &avroEnvelope{}
+	if err := avro.Unmarshal(avroEnvelopeSchema, value, env); err != nil {
+		return nil, err
+	}
+
+	return &Envelope{
+		Schema:        env.Schema,
+		Version:       env.Version,
+		MessageID:     env.MessageID,
+		ProducedAt:    env.ProducedAt,
+		CorrelationID: env.CorrelationID,
+		Payload:       env.Payload,
+	}, nil
+}
+
+func (AvroCodec) MarshalPayload(dto any) ([]byte, error) {
+	switch msg := dto.(type) {
+	case *PublishReqMessage:
+		return avro.Marshal(avroPublishReqSchema, &avroPublishReq{
+			RequestID:    int64(msg.RequestID),
+			Type:         string(msg.Type),
+			Status:       string(msg.Status),
+			Date:         msg.Date,
+			ApplierID:    int64(msg.ApplierID),
+			ManagerID:    int64(msg.ManagerID),
+			ReleaseID:    int64(msg.ReleaseID),
+			Grade:        int64(msg.Grade),
+			ExpectedDate: msg.ExpectedDate,
+			Description:  msg.Description,
+		})
+	case *SignContractReqMessage:
+		return avro.Marshal(avroSignContractReqSchema, &avroSignContractReq{
+			RequestID:   int64(msg.RequestID),
+			Type:        string(msg.Type),
+			Status:      string(msg.Status),
+			Date:        msg.Date,
+			ApplierID:   int64(msg.ApplierID),
+			ManagerID:   int64(msg.ManagerID),
+			Nickname:    msg.Nickname,
+			Grade:       int64(msg.Grade),
+			Description: msg.Description,
+		})
+	default:
+		return nil, fmt.Errorf("%w: %s %T", ErrUnsupportedDTO, AvroCodecName, dto)
+	}
+}
+
+func (AvroCodec) UnmarshalPayload(payload []byte, dto any) error {
+	switch msg := dto.(type) {
+	case *PublishReqMessage:
+		avroMsg := &avroPublishReq{}
+		if err := avro.Unmarshal(avroPublishReqSchema, payload, avroMsg); err != nil {
+			return err
+		}
+		*msg = PublishReqMessage{
+			RequestID:    uint64(avroMsg.RequestID),
+			Type:         base.RequestType(avroMsg.Type),
+			Status:       base.RequestStatus(avroMsg.Status),
+			Date:         avroMsg.Date,
+			ApplierID:    uint64(avroMsg.ApplierID),
+			ManagerID:    uint64(avroMsg.ManagerID),
+			ReleaseID:    uint64(avroMsg.ReleaseID),
+			Grade:        int(avroMsg.Grade),
+			ExpectedDate: avroMsg.ExpectedDate,
+			Description:  avroMsg.Description,
+		}
+		return nil
+	case *SignContractReqMessage:
+		avroMsg := &avroSignContractReq{}
+		if err := avro.Unmarshal(avroSignContractReqSchema, payload, avroMsg); err != nil {
+			return err
+		}
+		*msg = SignContractReqMessage{
+			RequestID:   uint64(avroMsg.RequestID),
+			Type:        base.RequestType(avroMsg.Type),
+			Status:      base.RequestStatus(avroMsg.Status),
+			Date:        avroMsg.Date,
+			ApplierID:   uint64(avroMsg.ApplierID),
+			ManagerID:   uint64(avroMsg.ManagerID),
+			Nickname:    avroMsg.Nickname,
+			Grade:       int(avroMsg.Grade),
+			Description: avroMsg.Description,
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: %s %T", ErrUnsupportedDTO, AvroCodecName, dto)
+	}
+}
@@ -0,0 +1,68 @@
+package broker_dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const JSONCodecName = "json"
+
+type JSONCodec struct{}
+
+type jsonEnvelope struct {
+	Schema        string          `json:"schema"`
+	Version       int             `json:"version"`
+	MessageID     string          `json:"message_id"`
+	ProducedAt    time.Time       `json:"produced_at"`
+	CorrelationID string          `json:"correlation_id"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+func (JSONCodec) Name() string {
+	return JSONCodecName
+}
+
+func (JSONCodec) MarshalEnvelope(env *Envelope) ([]byte, error) {
+	return json.Marshal(&jsonEnvelope{
+		Schema:        env.Schema,
+		Version:       env.Version,
+		MessageID:     env.MessageID,
+		ProducedAt:    env.ProducedAt,
+		CorrelationID: env.CorrelationID,
+		Payload:       env.Payload,
+	})
+}
+
+// UnmarshalEnvelope wraps bare legacy messages as LegacyVersion of the schema
+func (JSONCodec) UnmarshalEnvelope(value []byte, schema string) (*Envelope, error) {
+
+	env := &jsonEnvelope{}
+	if err := json.Unmarshal(value, env); err != nil {
+		return nil, err
+	}
+
+	if env.Schema == "" {
+		return &Envelope{
+			Schema:  schema,
+			Version: LegacyVersion,
+			Payload: value,
+		}, nil
+	}
+
+	return &Envelope{
+		Schema:        env.Schema,
+		Version:       env.Version,
+		MessageID:     env.MessageID,
+		ProducedAt:    env.ProducedAt,
+		CorrelationID: env.CorrelationID,
+		Payload:       env.Payload,
+	}, nil
+}
+
+func (JSONCodec) MarshalPayload(dto any) ([]byte, error) {
+	return json.Marshal(dto)
+}
+
+func (JSONCodec) UnmarshalPayload(payload []byte, dto any) error {
+	return json.Unmarshal(payload, dto)
+}
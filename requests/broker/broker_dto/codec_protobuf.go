@@ -0,0 +1,129 @@
+package broker_dto
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rauzh/cd-core/requests/base"
+	"github.com/rauzh/cd-core/requests/broker/broker_dto/pb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const ProtobufCodecName = "protobuf"
+
+// ProtobufCodec encodes messages described in pb/request_messages.proto
+type ProtobufCodec struct{}
+
+func (ProtobufCodec) Name() string {
+	return ProtobufCodecName
+}
+
+func (ProtobufCodec) MarshalEnvelope(env *Envelope) ([]byte, error) {
+	return proto.Marshal(&pb.Envelope{
+		Schema:        env.Schema,
+		Version:       int32(env.Version),
+		MessageId:     env.MessageID,
+		ProducedAt:    timestamppb.New(env.ProducedAt),
+		CorrelationId: env.CorrelationID,
+		Payload:       env.Payload,
+	})
+}
+
+func (ProtobufCodec) UnmarshalEnvelope(value []byte, schema string) (*Envelope, error) {
+
+	env := &pb.Envelope{}
+	if err := proto.Unmarshal(value, env); err != nil {
+		return nil, err
+	}
+
+	return &Envelope{
+		Schema:        env.GetSchema(),
+		Version:       int(env.GetVersion()),
+		MessageID:     env.GetMessageId(),
+		ProducedAt:    fromTimestamp(env.GetProducedAt()),
+		CorrelationID: env.GetCorrelationId(),
+		Payload:       env.GetPayload(),
+	}, nil
+}
+
+func (ProtobufCodec) MarshalPayload(dto any) ([]byte, error) {
+	switch msg := dto.(type) {
+	case *PublishReqMessage:
+		return proto.Marshal(&pb.PublishRequest{
+			RequestId:    msg.RequestID,
+			Type:         string(msg.Type),
+			Status:       string(msg.Status),
+			Date:         timestamppb.New(msg.Date),
+			ApplierId:    msg.ApplierID,
+			ManagerId:    msg.ManagerID,
+			ReleaseId:    msg.ReleaseID,
+			Grade:        int64(msg.Grade),
+			ExpectedDate: timestamppb.New(msg.ExpectedDate),
+			Description:  msg.Description,
+		})
+	case *SignContractReqMessage:
+		return proto.Marshal(&pb.SignContractRequest{
+			RequestId:   msg.RequestID,
+			Type:        string(msg.Type),
+			Status:      string(msg.Status),
+			Date:        timestamppb.New(msg.Date),
+			ApplierId:   msg.ApplierID,
+			ManagerId:   msg.ManagerID,
+			Nickname:    msg.Nickname,
+			Grade:       int64(msg.Grade),
+			Description: msg.Description,
+		})
+	default:
+		return nil, fmt.Errorf("%w: %s %T", ErrUnsupportedDTO, ProtobufCodecName, dto)
+	}
+}
+
+func (ProtobufCodec) UnmarshalPayload(payload []byte, dto any) error {
+	switch msg := dto.(type) {
+	case *PublishReqMessage:
+		pbMsg := &pb.PublishRequest{}
+		if err := proto.Unmarshal(payload, pbMsg); err != nil {
+			return err
+		}
+		*msg = PublishReqMessage{
+			RequestID:    pbMsg.GetRequestId(),
+			Type:         base.RequestType(pbMsg.GetType()),
+			Status:       base.RequestStatus(pbMsg.GetStatus()),
+			Date:         fromTimestamp(pbMsg.GetDate()),
+			ApplierID:    pbMsg.GetApplierId(),
+			ManagerID:    pbMsg.GetManagerId(),
+			ReleaseID:    pbMsg.GetReleaseId(),
+			Grade:        int(pbMsg.GetGrade()),
+			ExpectedDate: fromTimestamp(pbMsg.GetExpectedDate()),
+			Description:  pbMsg.GetDescription(),
+		}
+		return nil
+	case *SignContractReqMessage:
+		pbMsg := &pb.SignContractRequest{}
+		if err := proto.Unmarshal(payload, pbMsg); err != nil {
+			return err
+		}
+		*msg = SignContractReqMessage{
+			RequestID:   pbMsg.GetRequestId(),
+			Type:        base.RequestType(pbMsg.GetType()),
+			Status:      base.RequestStatus(pbMsg.GetStatus()),
+			Date:        fromTimestamp(pbMsg.GetDate()),
+			ApplierID:   pbMsg.GetApplierId(),
+			ManagerID:   pbMsg.GetManagerId(),
+			Nickname:    pbMsg.GetNickname(),
+			Grade:       int(pbMsg.GetGrade()),
+			Description: pbMsg.GetDescription(),
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: %s %T", ErrUnsupportedDTO, ProtobufCodecName, dto)
+	}
+}
+
+func fromTimestamp(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
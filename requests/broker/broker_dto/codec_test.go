@@ -0,0 +1,76 @@
+package broker_dto
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/rauzh/cd-core/requests/base"
+	"github.com/rauzh/cd-core/requests/broker"
+	"github.com/rauzh/cd-core/requests/publish"
+	"github.com/stretchr/testify/assert"
+)
+
+func toConsumerMessage(msg *sarama.ProducerMessage) *sarama.ConsumerMessage {
+	value, _ := msg.Value.Encode()
+	consumerMsg := &sarama.ConsumerMessage{Topic: msg.Topic, Value: value}
+	for i := range msg.Headers {
+		consumerMsg.Headers = append(consumerMsg.Headers, &msg.Headers[i])
+	}
+	return consumerMsg
+}
+
+func TestCodecs_MixedTraffic(t *testing.T) {
+
+	req := &publish.PublishRequest{
+		Request: base.Request{
+			RequestID: 1,
+			Type:      publish.PubReq,
+			Status:    base.NewRequest,
+			Date:      time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+			ApplierID: 12,
+			ManagerID: 9,
+		},
+		ReleaseID:    777,
+		Grade:        -1,
+		ExpectedDate: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		Description:  "descr",
+	}
+
+	for _, codec := range []string{JSONCodecName, ProtobufCodecName, AvroCodecName} {
+		t.Run(codec, func(t *testing.T) {
+			topic := "topic_" + codec
+			assert.Nil(t, Codecs.SetTopicCodec(topic, codec))
+			t.Cleanup(func() { Codecs.ResetTopicCodec(topic) })
+
+			producerMsg, err := NewPublishRequestProducerMsg(context.Background(), topic, req)
+			assert.Nil(t, err)
+
+			msg := toConsumerMessage(producerMsg)
+			codecName, _ := broker.GetHeader(msg, broker.HeaderCodec)
+			assert.Equal(t, codec, codecName)
+
+			dto, err := DecodePublishReqMessage(msg)
+			assert.Nil(t, err)
+			assert.Equal(t, req, dto.ToPublishReq())
+		})
+	}
+
+	registry := NewCodecRegistry(JSONCodec{})
+	assert.ErrorIs(t, registry.SetTopicCodec("topic", "xml"), ErrUnknownCodec)
+	assert.Equal(t, JSONCodecName, Codecs.ForTopic("topic_"+ProtobufCodecName).Name())
+}
+
+func TestCodecs_OlderBinaryVersion(t *testing.T) {
+
+	codec := ProtobufCodec{}
+	payload, _ := codec.MarshalPayload(&PublishReqMessage{RequestID: 1})
+	value, _ := codec.MarshalEnvelope(&Envelope{Schema: PublishReqSchema, Version: LegacyVersion, Payload: payload})
+
+	_, err := DecodePublishReqMessage(&sarama.ConsumerMessage{
+		Value:   value,
+		Headers: []*sarama.RecordHeader{{Key: []byte(broker.HeaderCodec), Value: []byte(ProtobufCodecName)}},
+	})
+	assert.ErrorIs(t, err, ErrNoUpcaster)
+}
@@ -0,0 +1,229 @@
+package broker_dto
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/rauzh/cd-core/requests/broker"
+	"github.com/rauzh/cd-core/requests/broker/tracing"
+)
+
+// LegacyVersion is the version of bare messages produced before envelopes
+const LegacyVersion = 1
+
+var (
+	ErrUnknownSchema    = errors.New("unknown message schema")
+	ErrUnexpectedSchema = errors.New("unexpected message schema")
+	ErrNewerVersion     = errors.New("message version is newer than supported")
+	ErrNoUpcaster       = errors.New("no upcaster for message version")
+)
+
+// Envelope wraps every broker message, so consumers know schema and version
+// of the payload and can upcast older messages during deploys.
+// Payload is encoded by the same codec as the envelope
+type Envelope struct {
+	Schema        string
+	Version       int
+	MessageID     string
+	ProducedAt    time.Time
+	CorrelationID string
+	Payload       []byte
+}
+
+// NewEnvelope wraps encoded payload, correlation id starts as the message id
+func NewEnvelope(schema string, version int, payload []byte) (*Envelope, error) {
+
+	messageID, err := newMessageID()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Envelope{
+		Schema:        schema,
+		Version:       version,
+		MessageID:     messageID,
+		ProducedAt:    time.Now().UTC(),
+		CorrelationID: messageID,
+		Payload:       payload,
+	}, nil
+}
+
+func newMessageID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("can't generate message id with err %w", err)
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// ToProducerMessage encodes envelope, its message id is copied to headers,
+// so consumers can deduplicate messages without decoding them
+func (env *Envelope) ToProducerMessage(topic string, codec Codec) (*sarama.ProducerMessage, error) {
+	value, err := codec.MarshalEnvelope(env)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sarama.ProducerMessage{
+		Topic: topic,
+		Value: sarama.ByteEncoder(value),
+		Headers: []sarama.RecordHeader{
+			{Key: []byte(broker.HeaderCodec), Value: []byte(codec.Name())},
+			{Key: []byte(broker.HeaderMessageID), Value: []byte(env.MessageID)},
+		},
+	}, nil
+}
+
+// NewProducerMessage encodes dto with the codec of the topic and wraps it into envelope,
+// correlation id and trace context of ctx are written to envelope and headers
+func NewProducerMessage(ctx context.Context, topic, schema string, version int, dto any) (*sarama.ProducerMessage, error) {
+
+	codec := Codecs.ForTopic(topic)
+
+	payload, err := codec.MarshalPayload(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := NewEnvelope(schema, version, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if id := tracing.CorrelationID(ctx); id != "" {
+		env.CorrelationID = id
+	} else {
+		ctx = tracing.WithCorrelationID(ctx, env.CorrelationID)
+	}
+
+	msg, err := env.ToProducerMessage(topic, codec)
+	if err != nil {
+		return nil, err
+	}
+
+	tracing.Inject(ctx, msg)
+
+	return msg, nil
+}
+
+// Upcaster converts payload of some version to the next one
+type Upcaster func(json.RawMessage) (json.RawMessage, error)
+
+// SchemaRegistry knows current version of every schema and upcasters to reach it
+type SchemaRegistry struct {
+	current   map[string]int
+	upcasters map[string]map[int]Upcaster
+}
+
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{
+		current:   make(map[string]int),
+		upcasters: make(map[string]map[int]Upcaster),
+	}
+}
+
+func (registry *SchemaRegistry) Register(schema string, current int) {
+	registry.current[schema] = current
+	if registry.upcasters[schema] == nil {
+		registry.upcasters[schema] = make(map[int]Upcaster)
+	}
+}
+
+// AddUpcaster registers conversion of schema payload from version to version+1
+func (registry *SchemaRegistry) AddUpcaster(schema string, from int, upcaster Upcaster) {
+	if registry.upcasters[schema] == nil {
+		registry.upcasters[schema] = make(map[int]Upcaster)
+	}
+	registry.upcasters[schema][from] = upcaster
+}
+
+func (registry *SchemaRegistry) Current(schema string) (int, error) {
+	current, ok := registry.current[schema]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownSchema, schema)
+	}
+	return current, nil
+}
+
+// Upcast returns envelope payload converted to the current version of its schema.
+// Upcasters convert json payloads, so only json envelopes can be upcasted
+func (registry *SchemaRegistry) Upcast(env *Envelope) (json.RawMessage, error) {
+
+	current, err := registry.Current(env.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	if env.Version > current {
+		return nil, fmt.Errorf("%w: %s v%d, supported v%d", ErrNewerVersion, env.Schema, env.Version, current)
+	}
+
+	payload := json.RawMessage(env.Payload)
+	for version := env.Version; version < current; version++ {
+		upcaster, ok := registry.upcasters[env.Schema][version]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s v%d", ErrNoUpcaster, env.Schema, version)
+		}
+		if payload, err = upcaster(payload); err != nil {
+			return nil, fmt.Errorf("can't upcast %s v%d with err %w", env.Schema, version, err)
+		}
+	}
+
+	return payload, nil
+}
+
+// Decode parses envelope of schema with the codec from message header
+// and decodes payload of the current version into the DTO
+func Decode[T any](registry *SchemaRegistry, schema string, msg *sarama.ConsumerMessage) (*T, error) {
+
+	codec, err := Codecs.ForMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := codec.UnmarshalEnvelope(msg.Value, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	if env.Schema != schema {
+		return nil, fmt.Errorf("%w: got %s, want %s", ErrUnexpectedSchema, env.Schema, schema)
+	}
+
+	payload := env.Payload
+	if codec.Name() == JSONCodecName {
+		if payload, err = registry.Upcast(env); err != nil {
+			return nil, err
+		}
+	} else if err := registry.sameAsCurrent(env); err != nil {
+		return nil, fmt.Errorf("%w with %s codec", err, codec.Name())
+	}
+
+	dto := new(T)
+	if err := codec.UnmarshalPayload(payload, dto); err != nil {
+		return nil, err
+	}
+	return dto, nil
+}
+
+// sameAsCurrent checks binary envelope version, binary codecs were added
+// with the current versions, so there is nothing to upcast yet
+func (registry *SchemaRegistry) sameAsCurrent(env *Envelope) error {
+	current, err := registry.Current(env.Schema)
+	if err != nil {
+		return err
+	}
+	if env.Version > current {
+		return fmt.Errorf("%w: %s v%d, supported v%d", ErrNewerVersion, env.Schema, env.Version, current)
+	}
+	if env.Version < current {
+		return fmt.Errorf("%w: %s v%d", ErrNoUpcaster, env.Schema, env.Version)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package broker_dto
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/rauzh/cd-core/requests/base"
+	"github.com/rauzh/cd-core/requests/broker"
+	"github.com/rauzh/cd-core/requests/sign_contract"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeSignContractReqMessage(t *testing.T) {
+
+	req := &sign_contract.SignContractRequest{
+		Request:  base.Request{RequestID: 1, Type: sign_contract.SignRequest, ApplierID: 12},
+		Nickname: "skibidi",
+		Grade:    2,
+	}
+
+	producerMsg, err := NewSignRequestProducerMsg(context.Background(), "topic", req)
+	assert.Nil(t, err)
+	value, _ := producerMsg.Value.Encode()
+
+	// message id header is the one of the envelope
+	env, err := JSONCodec{}.UnmarshalEnvelope(value, SignContractReqSchema)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, env.MessageID)
+	assert.Contains(t, producerMsg.Headers, sarama.RecordHeader{
+		Key: []byte(broker.HeaderMessageID), Value: []byte(env.MessageID)})
+
+	msg, err := DecodeSignContractReqMessage(&sarama.ConsumerMessage{Value: value})
+	assert.Nil(t, err)
+	assert.Equal(t, req, msg.ToSignContractReq())
+
+	// bare message produced before envelopes
+	legacy, _ := json.Marshal(map[string]any{"request_id": 1, "nickname": "skibidi"})
+	msg, err = DecodeSignContractReqMessage(&sarama.ConsumerMessage{Value: legacy})
+	assert.Nil(t, err)
+	assert.Equal(t, "skibidi", msg.Nickname)
+
+	_, err = DecodePublishReqMessage(&sarama.ConsumerMessage{Value: value})
+	assert.ErrorIs(t, err, ErrUnexpectedSchema)
+}
+
+func TestSchemaRegistry_Upcast(t *testing.T) {
+
+	registry := NewSchemaRegistry()
+	registry.Register("schema", 3)
+	registry.AddUpcaster("schema", 1, func(payload json.RawMessage) (json.RawMessage, error) {
+		return json.RawMessage(`{"name": "v2"}`), nil
+	})
+
+	_, err := registry.Upcast(&Envelope{Schema: "schema", Version: 1, Payload: json.RawMessage(`{}`)})
+	assert.ErrorIs(t, err, ErrNoUpcaster)
+
+	registry.AddUpcaster("schema", 2, func(payload json.RawMessage) (json.RawMessage, error) {
+		fields := make(map[string]string)
+		if err := json.Unmarshal(payload, &fields); err != nil {
+			return nil, err
+		}
+		fields["name"] += "-v3"
+		return json.Marshal(fields)
+	})
+
+	payload, err := registry.Upcast(&Envelope{Schema: "schema", Version: 1, Payload: json.RawMessage(`{}`)})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"name": "v2-v3"}`, string(payload))
+
+	_, err = registry.Upcast(&Envelope{Schema: "schema", Version: 4})
+	assert.ErrorIs(t, err, ErrNewerVersion)
+
+	_, err = registry.Upcast(&Envelope{Schema: "unknown", Version: 1})
+	assert.ErrorIs(t, err, ErrUnknownSchema)
+}
@@ -0,0 +1,4 @@
+// Package pb contains protobuf messages of protobuf codec
+package pb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative request_messages.proto
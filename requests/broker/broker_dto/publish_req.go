@@ -1,7 +1,7 @@
 package broker_dto
 
 import (
-	"encoding/json"
+	"context"
 	"time"
 
 	"github.com/IBM/sarama"
@@ -54,23 +54,6 @@ func (msg *PublishReqMessage) ToPublishReq() *publish.PublishRequest {
 	}
 }
 
-func NewMsgValue(msg *PublishReqMessage) ([]byte, error) {
-	msgjson, err := json.Marshal(msg)
-	if err != nil {
-		return nil, err
-	}
-	return msgjson, nil
-}
-
-func NewPublishRequestProducerMsg(topic string, req *publish.PublishRequest) (*sarama.ProducerMessage, error) {
-	msg := NewPublishReqMessage(req)
-	msgJson, err := NewMsgValue(msg)
-	if err != nil {
-		return nil, err
-	}
-
-	return &sarama.ProducerMessage{
-		Topic: topic,
-		Value: sarama.StringEncoder(msgJson),
-	}, nil
+func NewPublishRequestProducerMsg(ctx context.Context, topic string, req *publish.PublishRequest) (*sarama.ProducerMessage, error) {
+	return NewProducerMessage(ctx, topic, PublishReqSchema, PublishReqVersion, NewPublishReqMessage(req))
 }
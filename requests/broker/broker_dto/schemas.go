@@ -0,0 +1,66 @@
+package broker_dto
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/IBM/sarama"
+)
+
+const (
+	PublishReqSchema  = "publish_request"
+	PublishReqVersion = 2
+
+	SignContractReqSchema  = "sign_contract_request"
+	SignContractReqVersion = 2
+)
+
+// Schemas is the registry of request messages, upcasters are added here
+// when a DTO version is bumped
+var Schemas = newRequestSchemas()
+
+func newRequestSchemas() *SchemaRegistry {
+	registry := NewSchemaRegistry()
+
+	registry.Register(PublishReqSchema, PublishReqVersion)
+	// v1 is the bare message, its fields are the same
+	registry.AddUpcaster(PublishReqSchema, LegacyVersion, sameFields)
+
+	registry.Register(SignContractReqSchema, SignContractReqVersion)
+	// v1 is the bare message without grade, it is zero until criteria are applied
+	registry.AddUpcaster(SignContractReqSchema, LegacyVersion, sameFields)
+
+	return registry
+}
+
+func sameFields(payload json.RawMessage) (json.RawMessage, error) {
+	return payload, nil
+}
+
+func DecodePublishReqMessage(msg *sarama.ConsumerMessage) (*PublishReqMessage, error) {
+	return Decode[PublishReqMessage](Schemas, PublishReqSchema, msg)
+}
+
+func DecodeSignContractReqMessage(msg *sarama.ConsumerMessage) (*SignContractReqMessage, error) {
+	return Decode[SignContractReqMessage](Schemas, SignContractReqSchema, msg)
+}
+
+// RequestID decodes request message of schema with any codec and version and returns its request id
+func RequestID(schema string, msg *sarama.ConsumerMessage) (uint64, error) {
+	switch schema {
+	case PublishReqSchema:
+		dto, err := DecodePublishReqMessage(msg)
+		if err != nil {
+			return 0, err
+		}
+		return dto.RequestID, nil
+	case SignContractReqSchema:
+		dto, err := DecodeSignContractReqMessage(msg)
+		if err != nil {
+			return 0, err
+		}
+		return dto.RequestID, nil
+	default:
+		return 0, fmt.Errorf("%w: %s", ErrUnknownSchema, schema)
+	}
+}
@@ -1,7 +1,7 @@
 package broker_dto
 
 import (
-	"encoding/json"
+	"context"
 	"time"
 
 	"github.com/IBM/sarama"
@@ -17,20 +17,12 @@ type SignContractReqMessage struct {
 	ApplierID   uint64             `json:"applier_id"`
 	ManagerID   uint64             `json:"manager_id"`
 	Nickname    string             `json:"nickname"`
+	Grade       int                `json:"grade"`
 	Description string             `json:"description"`
 }
 
-func NewSignRequestProducerMsg(topic string, req *sign_contract.SignContractRequest) (*sarama.ProducerMessage, error) {
-	msg := NewSignContractReqMessage(req)
-	msgJson, err := json.Marshal(msg)
-	if err != nil {
-		return nil, err
-	}
-
-	return &sarama.ProducerMessage{
-		Topic: topic,
-		Value: sarama.StringEncoder(msgJson),
-	}, nil
+func NewSignRequestProducerMsg(ctx context.Context, topic string, req *sign_contract.SignContractRequest) (*sarama.ProducerMessage, error) {
+	return NewProducerMessage(ctx, topic, SignContractReqSchema, SignContractReqVersion, NewSignContractReqMessage(req))
 }
 
 func NewSignContractReqMessage(req *sign_contract.SignContractRequest) *SignContractReqMessage {
@@ -42,6 +34,7 @@ func NewSignContractReqMessage(req *sign_contract.SignContractRequest) *SignCont
 		ApplierID:   req.ApplierID,
 		ManagerID:   req.ManagerID,
 		Nickname:    req.Nickname,
+		Grade:       req.Grade,
 		Description: req.Description,
 	}
 }
@@ -57,6 +50,7 @@ func (msg *SignContractReqMessage) ToSignContractReq() *sign_contract.SignContra
 			ManagerID: msg.ManagerID,
 		},
 		Nickname:    msg.Nickname,
+		Grade:       msg.Grade,
 		Description: msg.Description,
 	}
 }
@@ -0,0 +1,259 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/rauzh/cd-core/requests/broker/tracing"
+	cdtime "github.com/rauzh/cd-core/time"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ConsumerConfig describes everything request type specific for Consumer,
+// the rest of the consume loop is shared
+type ConsumerConfig[T any] struct {
+	Topics []string
+
+	// Decode decodes message into DTO, json of the value by default
+	Decode func(*sarama.ConsumerMessage) (*T, error)
+
+	// Handle is the business step, its error is passed to RetryPolicy
+	Handle func(context.Context, *T) error
+
+	// OnTimeout is called instead of Handle for messages produced before TimeoutSince
+	OnTimeout    func(context.Context, *T) error
+	TimeoutSince func() time.Time
+
+	Retry RetryPolicy
+
+	// Blocking retries failed Handle in place instead of passing its error to RetryPolicy,
+	// the message is not marked consumed until it is handled or consumer is stopped
+	Blocking *BlockingBackoff
+
+	// Dedup makes duplicate deliveries of the same message no-ops,
+	// RequestID is required with it. Messages are not deduplicated if nil
+	Dedup     IDeduplicator
+	RequestID func(*T) uint64
+}
+
+// IDeduplicator runs fn at most once per request and message id,
+// fn gets context that must be used for all its side effects
+type IDeduplicator interface {
+	Once(ctx context.Context, requestID uint64, msg *sarama.ConsumerMessage, fn func(context.Context) error) error
+}
+
+type Consumer[T any] struct {
+	cfg ConsumerConfig[T]
+
+	// ready is closed by Setup and rearmed by Ready, they and WaitReady run in different goroutines
+	readyMu     sync.Mutex
+	ready       chan bool
+	readyClosed bool
+
+	logger *slog.Logger
+}
+
+func NewConsumer[T any](cfg ConsumerConfig[T], logger *slog.Logger) (*Consumer[T], error) {
+	if cfg.Dedup != nil && cfg.RequestID == nil {
+		return nil, ErrNoRequestID
+	}
+	if cfg.Decode == nil {
+		cfg.Decode = DecodeJSON[T]
+	}
+	if cfg.TimeoutSince == nil {
+		cfg.TimeoutSince = cdtime.RelevantPeriod
+	}
+	if cfg.Retry == nil {
+		cfg.Retry = NoRetry{}
+	}
+
+	return &Consumer[T]{
+		cfg:    cfg,
+		ready:  make(chan bool),
+		logger: logger,
+	}, nil
+}
+
+func DecodeJSON[T any](msg *sarama.ConsumerMessage) (*T, error) {
+	dto := new(T)
+	if err := json.Unmarshal(msg.Value, dto); err != nil {
+		return nil, err
+	}
+	return dto, nil
+}
+
+func (consumer *Consumer[T]) Topics() []string {
+	return consumer.cfg.Topics
+}
+
+// Ready rearms readiness before the next session, Setup closes it again after rebalance
+func (consumer *Consumer[T]) Ready() {
+	consumer.readyMu.Lock()
+	defer consumer.readyMu.Unlock()
+
+	if consumer.readyClosed {
+		consumer.ready = make(chan bool)
+		consumer.readyClosed = false
+	}
+}
+
+func (consumer *Consumer[T]) WaitReady() {
+	consumer.readyMu.Lock()
+	ready := consumer.ready
+	consumer.readyMu.Unlock()
+
+	<-ready
+}
+
+// Setup may be called again without Ready, e.g. when handler is added to several topics
+func (consumer *Consumer[T]) Setup(session sarama.ConsumerGroupSession) error {
+	consumer.readyMu.Lock()
+	defer consumer.readyMu.Unlock()
+
+	if !consumer.readyClosed {
+		close(consumer.ready)
+		consumer.readyClosed = true
+	}
+	return nil
+}
+
+func (consumer *Consumer[T]) Cleanup(session sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (consumer *Consumer[T]) ConsumeClaim(
+	session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+
+	for {
+		select {
+		case message, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+			if consumer.subscribed(message.Topic) {
+				err := consumer.Process(session.Context(), message)
+				if errors.Is(err, context.Canceled) || errors.Is(err, ErrForwardFailed) {
+					// don't mark message as consumed, it will be consumed after rebalance
+					return nil
+				}
+				if err != nil {
+					consumer.logger.ErrorContext(tracing.Extract(session.Context(), message),
+						"CONSUMER ConsumeClaim", "topic", message.Topic, slog.Any("error", err))
+				}
+			}
+			session.MarkMessage(message, "")
+
+		// Should return when `session.Context()` is done.
+		// If not, will raise `ErrRebalanceInProgress` or `read tcp <ip>:<port>: i/o timeout` when kafka rebalance. see:
+		// https://github.com/IBM/sarama/issues/1192
+		case <-session.Context().Done():
+			return nil
+		}
+	}
+}
+
+func (consumer *Consumer[T]) subscribed(topic string) bool {
+	for _, subscribedTopic := range consumer.cfg.Topics {
+		if subscribedTopic == topic {
+			return true
+		}
+	}
+	return false
+}
+
+// Process runs one message through decode, timeout check, business step and retry policy.
+// Correlation id and trace context of the producer are restored into ctx of the handler
+func (consumer *Consumer[T]) Process(ctx context.Context, msg *sarama.ConsumerMessage) (err error) {
+
+	ctx = tracing.Extract(ctx, msg)
+	ctx, span := tracing.Tracer().Start(ctx, OriginalTopic(msg)+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.destination.name", msg.Topic),
+			attribute.Int64("messaging.kafka.message.offset", msg.Offset),
+			attribute.Int("messaging.kafka.destination.partition", int(msg.Partition)),
+			attribute.Int("retry.count", RetryCount(msg))))
+	defer func() { tracing.End(span, err) }()
+
+	dto, err := consumer.cfg.Decode(msg)
+	if err != nil {
+		// there is no point to retry message that can't be decoded
+		decodeErr := fmt.Errorf("can't decode message with err %w", err)
+		return consumer.forward(ctx, msg, func() error { return consumer.cfg.Retry.DeadLetter(msg, decodeErr) })
+	}
+
+	handle := consumer.cfg.Handle
+	if msg.Timestamp.Before(consumer.cfg.TimeoutSince()) && consumer.cfg.OnTimeout != nil {
+		handle = consumer.cfg.OnTimeout
+	}
+
+	for attempt := 1; ; attempt++ {
+		if consumer.cfg.Dedup != nil {
+			err = consumer.cfg.Dedup.Once(ctx, consumer.cfg.RequestID(dto), msg, func(ctx context.Context) error {
+				return handle(ctx, dto)
+			})
+		} else {
+			err = handle(ctx, dto)
+		}
+
+		if err == nil || consumer.cfg.Blocking == nil || errors.Is(err, context.Canceled) {
+			break
+		}
+
+		span.RecordError(err)
+		consumer.logger.WarnContext(ctx, "CONSUMER Process retrying in place",
+			"topic", msg.Topic, "attempt", attempt, slog.Any("error", err))
+
+		select {
+		case <-time.After(consumer.cfg.Blocking.Delay(attempt)):
+		case <-ctx.Done():
+			// not handled, so it must not be marked consumed
+			return ctx.Err()
+		}
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		handleErr := err
+		return consumer.forward(ctx, msg, func() error { return consumer.cfg.Retry.Retry(msg, handleErr) })
+	}
+
+	return nil
+}
+
+// forward runs retry policy step until the failed message is moved to retry or dead letter topic,
+// otherwise it would be marked consumed and lost. Backoff is Blocking or the default one
+func (consumer *Consumer[T]) forward(ctx context.Context, msg *sarama.ConsumerMessage, step func() error) error {
+	blocking := consumer.cfg.Blocking
+	if blocking == nil {
+		blocking = DefaultBlockingBackoff()
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := step()
+		if !errors.Is(err, ErrForwardFailed) {
+			return err
+		}
+
+		consumer.logger.WarnContext(ctx, "CONSUMER Process retrying forward in place",
+			"topic", msg.Topic, "attempt", attempt, slog.Any("error", err))
+
+		select {
+		case <-time.After(blocking.Delay(attempt)):
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", err, ctx.Err())
+		}
+	}
+}
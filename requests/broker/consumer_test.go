@@ -0,0 +1,264 @@
+package broker_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/rauzh/cd-core/requests/broker"
+	broker_mocks "github.com/rauzh/cd-core/requests/broker/mocks"
+	cdtime "github.com/rauzh/cd-core/time"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type testMessage struct {
+	RequestID uint64 `json:"request_id"`
+}
+
+func producerHeader(msg *sarama.ProducerMessage, key string) string {
+	for _, header := range msg.Headers {
+		if string(header.Key) == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+func TestConsumer_Process(t *testing.T) {
+
+	errHandle := errors.New("handle err")
+
+	tests := []struct {
+		name       string
+		value      string
+		timestamp  time.Time
+		retryCount string
+		handleErr  error
+
+		handled, timedOut bool
+		sentTo            string
+		sentRetryCount    string
+	}{
+		{
+			name:      "OK",
+			value:     `{"request_id": 1}`,
+			timestamp: cdtime.GetToday(),
+			handled:   true,
+		},
+		{
+			name:      "TimedOut",
+			value:     `{"request_id": 1}`,
+			timestamp: cdtime.RelevantPeriod().AddDate(0, 0, -1),
+			timedOut:  true,
+		},
+		{
+			name:           "Retry",
+			value:          `{"request_id": 1}`,
+			timestamp:      cdtime.GetToday(),
+			handleErr:      errHandle,
+			handled:        true,
+			sentTo:         "topic_retry_1",
+			sentRetryCount: "1",
+		},
+		{
+			name:           "NextRetry",
+			value:          `{"request_id": 1}`,
+			timestamp:      cdtime.GetToday(),
+			retryCount:     "2",
+			handleErr:      errHandle,
+			handled:        true,
+			sentTo:         "topic_retry_3",
+			sentRetryCount: "3",
+		},
+		{
+			name:           "RetriesExhausted",
+			value:          `{"request_id": 1}`,
+			timestamp:      cdtime.GetToday(),
+			retryCount:     "5",
+			handleErr:      errHandle,
+			handled:        true,
+			sentTo:         "topic_dlt",
+			sentRetryCount: "5",
+		},
+		{
+			name:           "DecodeErr",
+			value:          `{"request_id":`,
+			timestamp:      cdtime.GetToday(),
+			sentTo:         "topic_dlt",
+			sentRetryCount: "0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			mockBroker := broker_mocks.NewIBroker(t)
+			if tt.sentTo != "" {
+				mockBroker.EXPECT().SendMessage(mock.MatchedBy(func(msg *sarama.ProducerMessage) bool {
+					return msg.Topic == tt.sentTo &&
+						msg.Timestamp.Equal(tt.timestamp) &&
+						producerHeader(msg, broker.HeaderRetryCount) == tt.sentRetryCount &&
+						producerHeader(msg, broker.HeaderOriginalTopic) == "topic" &&
+						producerHeader(msg, broker.HeaderFailureReason) != ""
+				})).Return(0, 0, nil).Once()
+			}
+
+			var handled, timedOut bool
+			consumer, _ := broker.NewConsumer(broker.ConsumerConfig[testMessage]{
+				Topics: []string{"topic"},
+				Handle: func(ctx context.Context, msg *testMessage) error {
+					assert.Equal(t, uint64(1), msg.RequestID)
+					handled = true
+					return tt.handleErr
+				},
+				OnTimeout: func(ctx context.Context, msg *testMessage) error {
+					timedOut = true
+					return nil
+				},
+				Retry: broker.NewBoundedRetry(mockBroker),
+			}, slog.Default())
+
+			msg := &sarama.ConsumerMessage{
+				Topic:     "topic",
+				Value:     []byte(tt.value),
+				Timestamp: tt.timestamp,
+			}
+			if tt.retryCount != "" {
+				msg.Headers = []*sarama.RecordHeader{
+					{Key: []byte(broker.HeaderRetryCount), Value: []byte(tt.retryCount)},
+				}
+			}
+
+			err := consumer.Process(context.Background(), msg)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.handled, handled)
+			assert.Equal(t, tt.timedOut, timedOut)
+		})
+	}
+}
+
+func TestBoundedRetry_Delay(t *testing.T) {
+
+	retry := broker.NewBoundedRetry(nil)
+	retry.BaseDelay = time.Minute
+	retry.MaxDelay = 5 * time.Minute
+
+	assert.Equal(t, time.Minute, retry.Delay(1))
+	assert.Equal(t, 2*time.Minute, retry.Delay(2))
+	assert.Equal(t, 4*time.Minute, retry.Delay(3))
+	assert.Equal(t, 5*time.Minute, retry.Delay(4))
+	assert.Equal(t, 5*time.Minute, retry.Delay(10))
+}
+
+type noopDeduplicator struct{}
+
+func (noopDeduplicator) Once(ctx context.Context, requestID uint64, msg *sarama.ConsumerMessage, fn func(context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestNewConsumer_DedupWithoutRequestID(t *testing.T) {
+
+	_, err := broker.NewConsumer(broker.ConsumerConfig[testMessage]{
+		Topics: []string{"topic"},
+		Handle: func(ctx context.Context, msg *testMessage) error { return nil },
+		Dedup:  noopDeduplicator{},
+	}, slog.Default())
+
+	assert.ErrorIs(t, err, broker.ErrNoRequestID)
+}
+
+func TestConsumer_Ready(t *testing.T) {
+
+	consumer, _ := broker.NewConsumer(broker.ConsumerConfig[testMessage]{
+		Topics: []string{"topic"},
+		Handle: func(ctx context.Context, msg *testMessage) error { return nil },
+	}, slog.Default())
+
+	waited := make(chan struct{})
+	go func() {
+		consumer.WaitReady()
+		close(waited)
+	}()
+
+	assert.Nil(t, consumer.Setup(nil))
+	// the same handler set up again without rearming
+	assert.Nil(t, consumer.Setup(nil))
+
+	select {
+	case <-waited:
+	case <-time.After(time.Second):
+		t.Fatal("consumer is not ready after setup")
+	}
+
+	// rebalance
+	consumer.Ready()
+	consumer.Ready()
+	assert.Nil(t, consumer.Setup(nil))
+	consumer.WaitReady()
+}
+
+type fakeSession struct {
+	sarama.ConsumerGroupSession
+	ctx    context.Context
+	marked []*sarama.ConsumerMessage
+}
+
+func (session *fakeSession) Context() context.Context {
+	return session.ctx
+}
+
+func (session *fakeSession) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	session.marked = append(session.marked, msg)
+}
+
+type fakeClaim struct {
+	sarama.ConsumerGroupClaim
+	messages chan *sarama.ConsumerMessage
+}
+
+func (claim *fakeClaim) Messages() <-chan *sarama.ConsumerMessage {
+	return claim.messages
+}
+
+func TestConsumer_ConsumeClaim_ForwardFailed(t *testing.T) {
+
+	var cancel context.CancelFunc
+
+	// failed message can be neither retried nor dead lettered until consumer is stopped
+	errSend := errors.New("send err")
+	mockBroker := broker_mocks.NewIBroker(t)
+	mockBroker.EXPECT().SendMessage(mock.Anything).Return(0, 0, errSend).Run(
+		func(msg *sarama.ProducerMessage) {
+			cancel()
+		}).Times(2)
+
+	consumer, _ := broker.NewConsumer(broker.ConsumerConfig[testMessage]{
+		Topics: []string{"topic"},
+		Handle: func(ctx context.Context, msg *testMessage) error {
+			return errors.New("handle err")
+		},
+		Retry: broker.NewBoundedRetry(mockBroker),
+	}, slog.Default())
+
+	for _, value := range []string{`{"request_id": 1}`, `{"request_id":`} {
+		t.Run(value, func(t *testing.T) {
+
+			var ctx context.Context
+			ctx, cancel = context.WithCancel(context.Background())
+			defer cancel()
+
+			claim := &fakeClaim{messages: make(chan *sarama.ConsumerMessage, 1)}
+			claim.messages <- &sarama.ConsumerMessage{Topic: "topic", Value: []byte(value), Timestamp: cdtime.GetToday()}
+			close(claim.messages)
+			session := &fakeSession{ctx: ctx}
+
+			assert.Nil(t, consumer.ConsumeClaim(session, claim))
+			assert.Empty(t, session.marked)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package dead_letter
+
+import (
+	"sort"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/rauzh/cd-core/requests/broker"
+	"github.com/rauzh/cd-core/requests/broker/broker_dto"
+)
+
+type DeadLetter struct {
+	DeadLetterID uint64
+	// RequestID is zero if message can't be decoded
+	RequestID     uint64
+	OriginalTopic string
+	Key           []byte
+	Value         []byte
+	Headers       map[string]string
+	Timestamp     time.Time
+	FailureReason string
+	RetryCount    int
+	FailedAt      time.Time
+	ReplayedAt    time.Time
+}
+
+func (deadLetter *DeadLetter) IsReplayed() bool {
+	return !deadLetter.ReplayedAt.IsZero()
+}
+
+// FromMessage builds dead letter from the message consumed from dead letter topic,
+// schema is the request message schema of the original topic
+func FromMessage(msg *sarama.ConsumerMessage, schema string) (*DeadLetter, error) {
+
+	deadLetter := &DeadLetter{
+		OriginalTopic: broker.OriginalTopic(msg),
+		Key:           msg.Key,
+		Value:         msg.Value,
+		Headers:       make(map[string]string, len(msg.Headers)),
+		Timestamp:     msg.Timestamp,
+		RetryCount:    broker.RetryCount(msg),
+	}
+
+	for _, header := range msg.Headers {
+		if header != nil {
+			deadLetter.Headers[string(header.Key)] = string(header.Value)
+		}
+	}
+
+	deadLetter.FailureReason = deadLetter.Headers[broker.HeaderFailureReason]
+
+	failedAt, err := time.Parse(time.RFC3339, deadLetter.Headers[broker.HeaderFailedAt])
+	if err != nil {
+		failedAt = msg.Timestamp
+	}
+	deadLetter.FailedAt = failedAt
+
+	// all request messages have request id, but value may be broken
+	if requestID, err := broker_dto.RequestID(schema, msg); err == nil {
+		deadLetter.RequestID = requestID
+	}
+
+	return deadLetter, nil
+}
+
+// DeadLetterTopics returns dead letter topics of the original topics of schemas
+func DeadLetterTopics(schemas map[string]string) []string {
+	deadLetterTopics := make([]string, 0, len(schemas))
+	for topic := range schemas {
+		deadLetterTopics = append(deadLetterTopics, broker.DeadLetterTopic(topic))
+	}
+	sort.Strings(deadLetterTopics)
+	return deadLetterTopics
+}
@@ -0,0 +1,7 @@
+package errors
+
+import "errors"
+
+var (
+	ErrAlreadyReplayed error = errors.New("dead letter is already replayed")
+)
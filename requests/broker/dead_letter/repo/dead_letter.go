@@ -0,0 +1,16 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/rauzh/cd-core/requests/broker/dead_letter"
+)
+
+//go:generate mockery --name DeadLetterRepo --with-expecter
+type DeadLetterRepo interface {
+	Create(context.Context, *dead_letter.DeadLetter) error
+	Get(ctx context.Context, id uint64) (*dead_letter.DeadLetter, error)
+	GetAll(context.Context) ([]dead_letter.DeadLetter, error)
+	GetAllByTopic(ctx context.Context, topic string) ([]dead_letter.DeadLetter, error)
+	Update(context.Context, *dead_letter.DeadLetter) error
+}
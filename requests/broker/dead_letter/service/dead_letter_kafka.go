@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/IBM/sarama"
+	"github.com/rauzh/cd-core/requests/broker"
+	"github.com/rauzh/cd-core/requests/broker/dead_letter"
+	deadLetterRepo "github.com/rauzh/cd-core/requests/broker/dead_letter/repo"
+)
+
+// DeadLetterConsumerHandler stores messages from dead letter topics,
+// so they can be listed and replayed
+type DeadLetterConsumerHandler struct {
+	*broker.Consumer[dead_letter.DeadLetter]
+
+	repo deadLetterRepo.DeadLetterRepo
+
+	logger *slog.Logger
+}
+
+// InitDeadLetterConsumerHandler consumes dead letter topics of the original topics of schemas,
+// schemas map original topics to schemas of their request messages
+func InitDeadLetterConsumerHandler(
+	r deadLetterRepo.DeadLetterRepo,
+	schemas map[string]string,
+	logger *slog.Logger,
+) (broker.IConsumerGroupHandler, error) {
+
+	handler := &DeadLetterConsumerHandler{repo: r, logger: logger}
+
+	consumer, err := broker.NewConsumer(broker.ConsumerConfig[dead_letter.DeadLetter]{
+		Topics: dead_letter.DeadLetterTopics(schemas),
+		Decode: func(msg *sarama.ConsumerMessage) (*dead_letter.DeadLetter, error) {
+			return dead_letter.FromMessage(msg, schemas[broker.OriginalTopic(msg)])
+		},
+		Handle: handler.store,
+		// dead letter is the last place failed message ends up in, it must not be lost
+		Blocking: broker.DefaultBlockingBackoff(),
+	}, logger)
+	if err != nil {
+		return nil, err
+	}
+	handler.Consumer = consumer
+
+	return handler, nil
+}
+
+func (handler *DeadLetterConsumerHandler) store(ctx context.Context, deadLetter *dead_letter.DeadLetter) error {
+	if err := handler.repo.Create(ctx, deadLetter); err != nil {
+		handler.logger.Error("DEAD_LETTER_HANDLER store", "topic", deadLetter.OriginalTopic, slog.Any("error", err))
+		return err
+	}
+
+	handler.logger.Warn("DEAD_LETTER_HANDLER store",
+		"topic", deadLetter.OriginalTopic,
+		"req", deadLetter.RequestID,
+		"reason", deadLetter.FailureReason)
+	return nil
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/rauzh/cd-core/requests/broker"
+	"github.com/rauzh/cd-core/requests/broker/dead_letter"
+	deadLetterErrors "github.com/rauzh/cd-core/requests/broker/dead_letter/errors"
+	deadLetterRepo "github.com/rauzh/cd-core/requests/broker/dead_letter/repo"
+	"github.com/rauzh/cd-core/transactor"
+)
+
+type IDeadLetterService interface {
+	GetAll() ([]dead_letter.DeadLetter, error)
+	GetAllByTopic(topic string) ([]dead_letter.DeadLetter, error)
+	Replay(id uint64) error
+}
+
+type DeadLetterService struct {
+	repo       deadLetterRepo.DeadLetterRepo
+	broker     broker.IBroker
+	transactor transactor.Transactor
+
+	logger *slog.Logger
+}
+
+func NewDeadLetterService(
+	r deadLetterRepo.DeadLetterRepo,
+	b broker.IBroker,
+	t transactor.Transactor,
+	logger *slog.Logger) IDeadLetterService {
+	return &DeadLetterService{repo: r, broker: b, transactor: t, logger: logger}
+}
+
+func (dlSvc *DeadLetterService) GetAll() ([]dead_letter.DeadLetter, error) {
+	deadLetters, err := dlSvc.repo.GetAll(context.Background())
+	if err != nil {
+		dlSvc.logger.Error("DEAD_LETTER_SERVICE GetAll", slog.Any("error", err))
+		return nil, fmt.Errorf("can't get dead letters with err %w", err)
+	}
+	return deadLetters, nil
+}
+
+func (dlSvc *DeadLetterService) GetAllByTopic(topic string) ([]dead_letter.DeadLetter, error) {
+	deadLetters, err := dlSvc.repo.GetAllByTopic(context.Background(), topic)
+	if err != nil {
+		dlSvc.logger.Error("DEAD_LETTER_SERVICE GetAllByTopic", "topic", topic, slog.Any("error", err))
+		return nil, fmt.Errorf("can't get dead letters with err %w", err)
+	}
+	return deadLetters, nil
+}
+
+// Replay sends dead letter back to its original topic with fresh retries.
+// The message gets new timestamp, so the request is not closed by timeout,
+// other headers are kept, so it is decoded, traced and deduplicated as the original one.
+// Dead letter is marked replayed before sending in the same transaction,
+// so failed send is rolled back and failed update never sends it twice
+func (dlSvc *DeadLetterService) Replay(id uint64) error {
+
+	err := dlSvc.transactor.WithinTransaction(context.Background(), func(ctx context.Context) error {
+
+		deadLetter, err := dlSvc.repo.Get(ctx, id)
+		if err != nil {
+			return fmt.Errorf("can't get dead letter with err %w", err)
+		}
+
+		if deadLetter.IsReplayed() {
+			return deadLetterErrors.ErrAlreadyReplayed
+		}
+
+		deadLetter.ReplayedAt = time.Now().UTC()
+		if err := dlSvc.repo.Update(ctx, deadLetter); err != nil {
+			return fmt.Errorf("can't update dead letter with err %w", err)
+		}
+
+		if _, _, err := dlSvc.broker.SendMessage(replayMessage(deadLetter)); err != nil {
+			return fmt.Errorf("can't replay dead letter with err %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		dlSvc.logger.Error("DEAD_LETTER_SERVICE Replay", "dead_letter_id", id, slog.Any("error", err))
+		return err
+	}
+
+	dlSvc.logger.Info("DEAD_LETTER_SERVICE Replay", "dead_letter_id", id)
+	return nil
+}
+
+func replayMessage(deadLetter *dead_letter.DeadLetter) *sarama.ProducerMessage {
+
+	msg := &sarama.ProducerMessage{
+		Topic:   deadLetter.OriginalTopic,
+		Value:   sarama.ByteEncoder(deadLetter.Value),
+		Headers: make([]sarama.RecordHeader, 0, len(deadLetter.Headers)),
+	}
+	if deadLetter.Key != nil {
+		msg.Key = sarama.ByteEncoder(deadLetter.Key)
+	}
+
+	for key, value := range deadLetter.Headers {
+		// replayed message starts retries over
+		if key == broker.HeaderRetryCount {
+			continue
+		}
+		msg.Headers = append(msg.Headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+	}
+
+	return msg
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/rauzh/cd-core/requests/broker"
+	"github.com/rauzh/cd-core/requests/broker/broker_dto"
+	"github.com/rauzh/cd-core/requests/broker/dead_letter"
+	deadLetterErrors "github.com/rauzh/cd-core/requests/broker/dead_letter/errors"
+	deadLetterRepoMocks "github.com/rauzh/cd-core/requests/broker/dead_letter/repo/mocks"
+	broker_mocks "github.com/rauzh/cd-core/requests/broker/mocks"
+	transacMock "github.com/rauzh/cd-core/transactor/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func header(msg *sarama.ProducerMessage, key string) (string, bool) {
+	for _, h := range msg.Headers {
+		if string(h.Key) == key {
+			return string(h.Value), true
+		}
+	}
+	return "", false
+}
+
+func protobufRequestMessage(t *testing.T, requestID uint64) ([]byte, []*sarama.RecordHeader) {
+
+	codec := broker_dto.ProtobufCodec{}
+
+	payload, err := codec.MarshalPayload(&broker_dto.PublishReqMessage{RequestID: requestID, ApplierID: 12, ReleaseID: 777})
+	assert.Nil(t, err)
+	env, err := broker_dto.NewEnvelope(broker_dto.PublishReqSchema, broker_dto.PublishReqVersion, payload)
+	assert.Nil(t, err)
+	value, err := codec.MarshalEnvelope(env)
+	assert.Nil(t, err)
+
+	return value, []*sarama.RecordHeader{{Key: []byte(broker.HeaderCodec), Value: []byte(codec.Name())}}
+}
+
+func TestDeadLetterConsumerHandler_Store(t *testing.T) {
+
+	value, headers := protobufRequestMessage(t, 7)
+
+	tests := []struct {
+		name      string
+		value     []byte
+		headers   []*sarama.RecordHeader
+		requestID uint64
+	}{
+		{name: "Protobuf", value: value, headers: headers, requestID: 7},
+		{name: "Legacy", value: []byte(`{"request_id": 8}`), requestID: 8},
+		{name: "Broken", value: []byte(`{"request_id":`), requestID: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			dlMockRepo := deadLetterRepoMocks.NewDeadLetterRepo(t)
+			dlMockRepo.EXPECT().Create(mock.Anything, mock.MatchedBy(func(deadLetter *dead_letter.DeadLetter) bool {
+				return deadLetter.OriginalTopic == "topic" &&
+					deadLetter.RequestID == tt.requestID &&
+					deadLetter.FailureReason == "handle err" &&
+					deadLetter.RetryCount == 5
+			})).Return(nil).Once()
+
+			consumerHandler, err := InitDeadLetterConsumerHandler(dlMockRepo,
+				map[string]string{"topic": broker_dto.PublishReqSchema}, slog.Default())
+			assert.Nil(t, err)
+			handler := consumerHandler.(*DeadLetterConsumerHandler)
+			assert.Equal(t, []string{"topic_dlt"}, handler.Topics())
+
+			err = handler.Process(context.Background(), &sarama.ConsumerMessage{
+				Topic: "topic_dlt",
+				Value: tt.value,
+				Headers: append([]*sarama.RecordHeader{
+					{Key: []byte(broker.HeaderOriginalTopic), Value: []byte("topic")},
+					{Key: []byte(broker.HeaderRetryCount), Value: []byte("5")},
+					{Key: []byte(broker.HeaderFailureReason), Value: []byte("handle err")},
+				}, tt.headers...),
+			})
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestDeadLetterConsumerHandler_StoreFailed(t *testing.T) {
+
+	dlMockRepo := deadLetterRepoMocks.NewDeadLetterRepo(t)
+	dlMockRepo.EXPECT().Create(mock.Anything, mock.Anything).Return(errors.New("db err"))
+
+	consumerHandler, err := InitDeadLetterConsumerHandler(dlMockRepo, map[string]string{"topic": broker_dto.PublishReqSchema}, slog.Default())
+	assert.Nil(t, err)
+	handler := consumerHandler.(*DeadLetterConsumerHandler)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	// dead letter is stored until consumer is stopped, so it is not marked consumed
+	err = handler.Process(ctx, &sarama.ConsumerMessage{Topic: "topic_dlt", Value: []byte(`{}`)})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestDeadLetterService_Replay(t *testing.T) {
+
+	errSend := errors.New("broker is down")
+
+	tests := []struct {
+		name      string
+		replayed  bool
+		sendErr   error
+		wantErr   error
+		updated   bool
+		wantSends bool
+	}{
+		{name: "OK", updated: true, wantSends: true},
+		{name: "AlreadyReplayed", replayed: true, wantErr: deadLetterErrors.ErrAlreadyReplayed},
+		{name: "SendFailed", sendErr: errSend, wantErr: errSend, updated: true, wantSends: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			dlMockRepo := deadLetterRepoMocks.NewDeadLetterRepo(t)
+			mockBroker := broker_mocks.NewIBroker(t)
+			transactionMock := transacMock.NewTransactor(t)
+
+			transactionMock.EXPECT().WithinTransaction(mock.Anything, mock.Anything).RunAndReturn(
+				func(ctx context.Context, fn func(context.Context) error) error {
+					return fn(ctx)
+				}).Once()
+
+			deadLetter := &dead_letter.DeadLetter{
+				DeadLetterID:  3,
+				OriginalTopic: "topic",
+				Value:         []byte{0x08, 0x01},
+				Headers: map[string]string{
+					broker.HeaderCodec:      "protobuf",
+					broker.HeaderMessageID:  "msg-1",
+					broker.HeaderRetryCount: "5",
+				},
+			}
+			if tt.replayed {
+				deadLetter.ReplayedAt = time.Now()
+			}
+			dlMockRepo.EXPECT().Get(mock.Anything, uint64(3)).Return(deadLetter, nil).Once()
+
+			if tt.updated {
+				dlMockRepo.EXPECT().Update(mock.Anything, mock.MatchedBy(func(deadLetter *dead_letter.DeadLetter) bool {
+					return deadLetter.IsReplayed()
+				})).Return(nil).Once()
+			}
+
+			if tt.wantSends {
+				mockBroker.EXPECT().SendMessage(mock.MatchedBy(func(msg *sarama.ProducerMessage) bool {
+					codec, _ := header(msg, broker.HeaderCodec)
+					messageID, _ := header(msg, broker.HeaderMessageID)
+					_, hasRetryCount := header(msg, broker.HeaderRetryCount)
+					return msg.Topic == "topic" && codec == "protobuf" && messageID == "msg-1" && !hasRetryCount
+				})).Return(0, 0, tt.sendErr).Once()
+			}
+
+			dlSvc := NewDeadLetterService(dlMockRepo, mockBroker, transactionMock, slog.Default())
+
+			err := dlSvc.Replay(3)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
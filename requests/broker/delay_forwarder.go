@@ -0,0 +1,73 @@
+package broker
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// DelayForwarder consumes delay topics and sends every message back
+// to its original topic when its not-before time comes
+type DelayForwarder struct {
+	*Consumer[sarama.ConsumerMessage]
+
+	broker IBroker
+	now    func() time.Time
+}
+
+func InitDelayForwarder(broker IBroker, topics []string, maxRetries int, logger *slog.Logger) (IConsumerGroupHandler, error) {
+
+	delayTopics := make([]string, 0)
+	for _, topic := range topics {
+		delayTopics = append(delayTopics, DelayTopics(topic, maxRetries)...)
+	}
+
+	forwarder := &DelayForwarder{broker: broker, now: time.Now}
+
+	consumer, err := NewConsumer(ConsumerConfig[sarama.ConsumerMessage]{
+		Topics: delayTopics,
+		Decode: func(msg *sarama.ConsumerMessage) (*sarama.ConsumerMessage, error) {
+			return msg, nil
+		},
+		Handle: forwarder.forward,
+		// forwarded message has nowhere else to go, so sending is retried until it succeeds
+		Blocking: DefaultBlockingBackoff(),
+	}, logger)
+	if err != nil {
+		return nil, err
+	}
+	forwarder.Consumer = consumer
+
+	return forwarder, nil
+}
+
+func (forwarder *DelayForwarder) forward(ctx context.Context, msg *sarama.ConsumerMessage) error {
+
+	if value, ok := GetHeader(msg, HeaderNotBefore); ok {
+		if notBeforeMs, err := strconv.ParseInt(value, 10, 64); err == nil {
+			// delay is the same for every message of the delay topic,
+			// so waiting for the head of partition doesn't block later messages
+			wait := time.UnixMilli(notBeforeMs).Sub(forwarder.now())
+			if wait > 0 {
+				select {
+				case <-time.After(wait):
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+	}
+
+	_, _, err := forwarder.broker.SendMessage(&sarama.ProducerMessage{
+		Topic:     OriginalTopic(msg),
+		Key:       keyEncoder(msg.Key),
+		Value:     sarama.ByteEncoder(msg.Value),
+		Timestamp: msg.Timestamp,
+		Headers:   WithHeaders(msg.Headers, nil),
+	})
+
+	return err
+}
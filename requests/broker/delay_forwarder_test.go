@@ -0,0 +1,65 @@
+package broker_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/rauzh/cd-core/requests/broker"
+	broker_mocks "github.com/rauzh/cd-core/requests/broker/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func delayedMessage(notBefore time.Time) *sarama.ConsumerMessage {
+	return &sarama.ConsumerMessage{
+		Topic: broker.DelayTopic("topic", 1),
+		Key:   []byte("1"),
+		Value: []byte(`{"request_id": 1}`),
+		Headers: []*sarama.RecordHeader{
+			{Key: []byte(broker.HeaderRetryCount), Value: []byte("1")},
+			{Key: []byte(broker.HeaderOriginalTopic), Value: []byte("topic")},
+			{Key: []byte(broker.HeaderNotBefore), Value: []byte(strconv.FormatInt(notBefore.UnixMilli(), 10))},
+			{Key: []byte(broker.HeaderCodec), Value: []byte("protobuf")},
+		},
+	}
+}
+
+func TestDelayForwarder_Forward(t *testing.T) {
+
+	mockBroker := broker_mocks.NewIBroker(t)
+	mockBroker.EXPECT().SendMessage(mock.MatchedBy(func(msg *sarama.ProducerMessage) bool {
+		return msg.Topic == "topic" &&
+			producerHeader(msg, broker.HeaderRetryCount) == "1" &&
+			producerHeader(msg, broker.HeaderCodec) == "protobuf"
+	})).Return(0, 0, nil).Once()
+
+	handler, err := broker.InitDelayForwarder(mockBroker, []string{"topic"}, 3, slog.Default())
+	assert.Nil(t, err)
+	forwarder := handler.(*broker.DelayForwarder)
+	assert.Equal(t, []string{"topic_retry_1", "topic_retry_2", "topic_retry_3"}, forwarder.Topics())
+
+	err = forwarder.Process(context.Background(), delayedMessage(time.Now().Add(-time.Second)))
+	assert.Nil(t, err)
+}
+
+func TestDelayForwarder_SendFailed(t *testing.T) {
+
+	mockBroker := broker_mocks.NewIBroker(t)
+	mockBroker.EXPECT().SendMessage(mock.Anything).Return(0, 0, errors.New("broker is down"))
+
+	handler, err := broker.InitDelayForwarder(mockBroker, []string{"topic"}, 3, slog.Default())
+	assert.Nil(t, err)
+	forwarder := handler.(*broker.DelayForwarder)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	// message is retried until consumer is stopped, so it is not marked consumed
+	err = forwarder.Process(ctx, delayedMessage(time.Now()))
+	assert.ErrorIs(t, err, context.Canceled)
+}
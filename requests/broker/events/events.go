@@ -0,0 +1,128 @@
+// Package events contains domain events of request lifecycle and statistics.
+// Every event type is published to its own topic as enveloped message
+// with the topic name as schema, so subscribers consume only what they need
+package events
+
+import (
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/rauzh/cd-core/requests/base"
+	"github.com/rauzh/cd-core/requests/broker"
+	"github.com/rauzh/cd-core/requests/broker/broker_dto"
+)
+
+const EventVersion = 1
+
+const (
+	// RequestAppliedTopic gets RequestApplied when request of any type is stored
+	RequestAppliedTopic = "request_applied"
+	// RequestRoutedToManagerTopic gets RequestRoutedToManager when request is graded and waits for manager
+	RequestRoutedToManagerTopic = "request_routed_to_manager"
+	// RequestAcceptedTopic gets RequestAccepted when manager accepted request
+	RequestAcceptedTopic = "request_accepted"
+	// RequestDeclinedTopic gets RequestDeclined when manager declined request,
+	// it was invalid or closed by timeout
+	RequestDeclinedTopic = "request_declined"
+	// PublicationCreatedTopic gets PublicationCreated when accepted publish request created publication
+	PublicationCreatedTopic = "publication_created"
+	// ArtistSignedTopic gets ArtistSigned when accepted sign contract request created artist
+	ArtistSignedTopic = "artist_signed"
+	// StatisticsAnomalyDetectedTopic gets StatisticsAnomalyDetected when stored stats look like bot streams
+	StatisticsAnomalyDetectedTopic = "statistics_anomaly_detected"
+)
+
+// Topics are all event topics
+var Topics = []string{
+	RequestAppliedTopic,
+	RequestRoutedToManagerTopic,
+	RequestAcceptedTopic,
+	RequestDeclinedTopic,
+	PublicationCreatedTopic,
+	ArtistSignedTopic,
+	StatisticsAnomalyDetectedTopic,
+}
+
+func init() {
+	for _, topic := range Topics {
+		broker_dto.Schemas.Register(topic, EventVersion)
+	}
+}
+
+type Event interface {
+	Topic() string
+}
+
+type RequestApplied struct {
+	RequestID  uint64           `json:"request_id"`
+	Type       base.RequestType `json:"type"`
+	ApplierID  uint64           `json:"applier_id"`
+	OccurredAt time.Time        `json:"occurred_at"`
+}
+
+type RequestRoutedToManager struct {
+	RequestID  uint64           `json:"request_id"`
+	Type       base.RequestType `json:"type"`
+	ManagerID  uint64           `json:"manager_id"`
+	Grade      int              `json:"grade"`
+	OccurredAt time.Time        `json:"occurred_at"`
+}
+
+type RequestAccepted struct {
+	RequestID  uint64           `json:"request_id"`
+	Type       base.RequestType `json:"type"`
+	ManagerID  uint64           `json:"manager_id"`
+	OccurredAt time.Time        `json:"occurred_at"`
+}
+
+type RequestDeclined struct {
+	RequestID  uint64           `json:"request_id"`
+	Type       base.RequestType `json:"type"`
+	ManagerID  uint64           `json:"manager_id"`
+	Reason     string           `json:"reason"`
+	TimedOut   bool             `json:"timed_out"`
+	OccurredAt time.Time        `json:"occurred_at"`
+}
+
+type PublicationCreated struct {
+	PublicationID uint64    `json:"publication_id"`
+	ReleaseID     uint64    `json:"release_id"`
+	ManagerID     uint64    `json:"manager_id"`
+	Date          time.Time `json:"date"`
+	OccurredAt    time.Time `json:"occurred_at"`
+}
+
+type ArtistSigned struct {
+	ArtistID     uint64    `json:"artist_id"`
+	UserID       uint64    `json:"user_id"`
+	Nickname     string    `json:"nickname"`
+	ManagerID    uint64    `json:"manager_id"`
+	ContractTerm time.Time `json:"contract_term"`
+	OccurredAt   time.Time `json:"occurred_at"`
+}
+
+// StatisticsAnomalyDetected asks manager to review a spike of streams
+type StatisticsAnomalyDetected struct {
+	TrackID  uint64    `json:"track_id"`
+	Platform string    `json:"platform"`
+	Country  string    `json:"country,omitempty"`
+	Date     time.Time `json:"date"`
+	Streams  uint64    `json:"streams"`
+	Median   float64   `json:"median"`
+	Score    float64   `json:"score"`
+
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func (*RequestApplied) Topic() string            { return RequestAppliedTopic }
+func (*RequestRoutedToManager) Topic() string    { return RequestRoutedToManagerTopic }
+func (*RequestAccepted) Topic() string           { return RequestAcceptedTopic }
+func (*RequestDeclined) Topic() string           { return RequestDeclinedTopic }
+func (*PublicationCreated) Topic() string        { return PublicationCreatedTopic }
+func (*ArtistSigned) Topic() string              { return ArtistSignedTopic }
+func (*StatisticsAnomalyDetected) Topic() string { return StatisticsAnomalyDetectedTopic }
+
+// Decode decodes event consumed from its topic, retried events keep original topic in header
+func Decode[T any](msg *sarama.ConsumerMessage) (*T, error) {
+	return broker_dto.Decode[T](broker_dto.Schemas, broker.OriginalTopic(msg), msg)
+}
@@ -0,0 +1,72 @@
+package events
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IBM/sarama"
+	"github.com/rauzh/cd-core/requests/broker"
+	"github.com/rauzh/cd-core/requests/broker/broker_dto"
+	"github.com/rauzh/cd-core/requests/broker/outbox"
+	outboxRepo "github.com/rauzh/cd-core/requests/broker/outbox/repo"
+)
+
+type IEventPublisher interface {
+	Publish(ctx context.Context, events ...Event) error
+}
+
+func NewProducerMessage(ctx context.Context, event Event) (*sarama.ProducerMessage, error) {
+	return broker_dto.NewProducerMessage(ctx, event.Topic(), event.Topic(), EventVersion, event)
+}
+
+// OutboxPublisher stores events in outbox, ctx should carry the transaction
+// of the change the events are about
+type OutboxPublisher struct {
+	repo outboxRepo.OutboxRepo
+}
+
+func NewOutboxPublisher(repo outboxRepo.OutboxRepo) IEventPublisher {
+	return &OutboxPublisher{repo: repo}
+}
+
+func (publisher *OutboxPublisher) Publish(ctx context.Context, events ...Event) error {
+	for _, event := range events {
+		msg, err := NewProducerMessage(ctx, event)
+		if err != nil {
+			return fmt.Errorf("can't encode %s event with err %w", event.Topic(), err)
+		}
+
+		outboxMsg, err := outbox.NewOutboxMessage(msg)
+		if err != nil {
+			return fmt.Errorf("can't encode %s event with err %w", event.Topic(), err)
+		}
+
+		if err := publisher.repo.Create(ctx, outboxMsg); err != nil {
+			return fmt.Errorf("can't store %s event with err %w", event.Topic(), err)
+		}
+	}
+	return nil
+}
+
+// BrokerPublisher sends events right away, it is used where there is no outbox
+type BrokerPublisher struct {
+	broker broker.IBroker
+}
+
+func NewBrokerPublisher(b broker.IBroker) IEventPublisher {
+	return &BrokerPublisher{broker: b}
+}
+
+func (publisher *BrokerPublisher) Publish(ctx context.Context, events ...Event) error {
+	for _, event := range events {
+		msg, err := NewProducerMessage(ctx, event)
+		if err != nil {
+			return fmt.Errorf("can't encode %s event with err %w", event.Topic(), err)
+		}
+
+		if _, _, err := publisher.broker.SendMessage(msg); err != nil {
+			return fmt.Errorf("can't send %s event with err %w", event.Topic(), err)
+		}
+	}
+	return nil
+}
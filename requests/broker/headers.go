@@ -0,0 +1,75 @@
+package broker
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/IBM/sarama"
+)
+
+const (
+	HeaderRetryCount    = "retry-count"
+	HeaderOriginalTopic = "original-topic"
+	HeaderNotBefore     = "not-before"
+	HeaderFailureReason = "failure-reason"
+	HeaderFailedAt      = "failed-at"
+	HeaderMessageID     = "message-id"
+	HeaderCodec         = "codec"
+)
+
+func GetHeader(msg *sarama.ConsumerMessage, key string) (string, bool) {
+	for _, header := range msg.Headers {
+		if header != nil && string(header.Key) == key {
+			return string(header.Value), true
+		}
+	}
+	return "", false
+}
+
+// RetryCount returns how many times message was already retried
+func RetryCount(msg *sarama.ConsumerMessage) int {
+	value, ok := GetHeader(msg, HeaderRetryCount)
+	if !ok {
+		return 0
+	}
+	count, err := strconv.Atoi(value)
+	if err != nil {
+		return 0
+	}
+	return count
+}
+
+// OriginalTopic returns the topic message was first produced to
+func OriginalTopic(msg *sarama.ConsumerMessage) string {
+	if topic, ok := GetHeader(msg, HeaderOriginalTopic); ok {
+		return topic
+	}
+	return msg.Topic
+}
+
+// MessageID returns id given to message by producer, messages produced
+// without it are identified by their position in the original topic
+func MessageID(msg *sarama.ConsumerMessage) string {
+	if id, ok := GetHeader(msg, HeaderMessageID); ok && id != "" {
+		return id
+	}
+	return fmt.Sprintf("%s/%d/%d", OriginalTopic(msg), msg.Partition, msg.Offset)
+}
+
+// WithHeaders copies consumed message headers replacing the given ones
+func WithHeaders(headers []*sarama.RecordHeader, replace map[string]string) []sarama.RecordHeader {
+	result := make([]sarama.RecordHeader, 0, len(headers)+len(replace))
+	for _, header := range headers {
+		if header == nil {
+			continue
+		}
+		if _, ok := replace[string(header.Key)]; ok {
+			continue
+		}
+		result = append(result, *header)
+	}
+	for key, value := range replace {
+		result = append(result, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+	}
+	return result
+}
@@ -0,0 +1,79 @@
+package dedup
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/rauzh/cd-core/requests/broker"
+	"github.com/rauzh/cd-core/requests/broker/idempotency"
+	"github.com/rauzh/cd-core/requests/broker/idempotency/repo"
+	"github.com/rauzh/cd-core/transactor"
+)
+
+// Deduplicator checks processed message, runs handler and records message
+// in one transaction, so handler effects and the record are committed together.
+// Create of the repo must fail for existing record, then concurrent duplicate
+// is rolled back and retried
+type Deduplicator struct {
+	repo       repo.ProcessedMessageRepo
+	transactor transactor.Transactor
+
+	now func() time.Time
+
+	logger *slog.Logger
+}
+
+func NewDeduplicator(
+	repo repo.ProcessedMessageRepo,
+	transactor transactor.Transactor,
+	logger *slog.Logger,
+) broker.IDeduplicator {
+	return &Deduplicator{
+		repo:       repo,
+		transactor: transactor,
+		now:        time.Now,
+		logger:     logger,
+	}
+}
+
+func (dedup *Deduplicator) Once(
+	ctx context.Context,
+	requestID uint64,
+	msg *sarama.ConsumerMessage,
+	fn func(context.Context) error,
+) error {
+
+	messageID := broker.MessageID(msg)
+
+	return dedup.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+
+		processed, err := dedup.repo.Exists(ctx, requestID, messageID)
+		if err != nil {
+			dedup.logger.ErrorContext(ctx, "DEDUPLICATOR Once", "req", requestID, slog.Any("error", err))
+			return fmt.Errorf("can't check processed message with err %w", err)
+		}
+		if processed {
+			dedup.logger.InfoContext(ctx, "DEDUPLICATOR skip duplicate", "req", requestID, "message", messageID)
+			return nil
+		}
+
+		if err := fn(ctx); err != nil {
+			return err
+		}
+
+		err = dedup.repo.Create(ctx, &idempotency.ProcessedMessage{
+			RequestID:   requestID,
+			MessageID:   messageID,
+			Topic:       broker.OriginalTopic(msg),
+			ProcessedAt: dedup.now().UTC(),
+		})
+		if err != nil {
+			dedup.logger.ErrorContext(ctx, "DEDUPLICATOR Once", "req", requestID, slog.Any("error", err))
+			return fmt.Errorf("can't record processed message with err %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,84 @@
+package dedup
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/rauzh/cd-core/requests/broker"
+	"github.com/rauzh/cd-core/requests/broker/idempotency"
+	processedRepoMocks "github.com/rauzh/cd-core/requests/broker/idempotency/repo/mocks"
+	transacMock "github.com/rauzh/cd-core/transactor/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDeduplicator_Once(t *testing.T) {
+
+	errHandle := errors.New("handle err")
+
+	msg := &sarama.ConsumerMessage{
+		Topic: "topic_retry_1",
+		Headers: []*sarama.RecordHeader{
+			{Key: []byte(broker.HeaderMessageID), Value: []byte("42")},
+			{Key: []byte(broker.HeaderOriginalTopic), Value: []byte("topic")},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		processed bool
+		handleErr error
+
+		handled, recorded bool
+		out               error
+	}{
+		{
+			name:     "OK",
+			handled:  true,
+			recorded: true,
+		},
+		{
+			name:      "Duplicate",
+			processed: true,
+		},
+		{
+			name:      "HandleFailed",
+			handleErr: errHandle,
+			handled:   true,
+			out:       errHandle,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			mockRepo := processedRepoMocks.NewProcessedMessageRepo(t)
+			mockTransactor := transacMock.NewTransactor(t)
+
+			mockTransactor.EXPECT().WithinTransaction(mock.Anything, mock.Anything).RunAndReturn(
+				func(ctx context.Context, fn func(context.Context) error) error {
+					return fn(ctx)
+				}).Once()
+
+			mockRepo.EXPECT().Exists(mock.Anything, uint64(7), "42").Return(tt.processed, nil).Once()
+			if tt.recorded {
+				mockRepo.EXPECT().Create(mock.Anything, mock.MatchedBy(func(pm *idempotency.ProcessedMessage) bool {
+					return pm.RequestID == 7 && pm.MessageID == "42" && pm.Topic == "topic"
+				})).Return(nil).Once()
+			}
+
+			handled := false
+			err := NewDeduplicator(mockRepo, mockTransactor, slog.Default()).Once(context.Background(), 7, msg,
+				func(ctx context.Context) error {
+					handled = true
+					return tt.handleErr
+				})
+
+			assert.ErrorIs(t, err, tt.out)
+			assert.Equal(t, tt.handled, handled)
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package idempotency
+
+import "time"
+
+// ProcessedMessage records that message was already handled for the request
+type ProcessedMessage struct {
+	RequestID   uint64
+	MessageID   string
+	Topic       string
+	ProcessedAt time.Time
+}
@@ -0,0 +1,14 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/rauzh/cd-core/requests/broker/idempotency"
+)
+
+//go:generate mockery --name ProcessedMessageRepo --with-expecter
+type ProcessedMessageRepo interface {
+	Exists(ctx context.Context, requestID uint64, messageID string) (bool, error)
+	// Create fails if message is already recorded for the request
+	Create(context.Context, *idempotency.ProcessedMessage) error
+}
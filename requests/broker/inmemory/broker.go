@@ -0,0 +1,365 @@
+package inmemory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/rauzh/cd-core/requests/broker"
+)
+
+const (
+	DefaultGroup = "cd-core"
+
+	// every topic has one partition, so messages of a topic are totally ordered
+	partition int32 = 0
+)
+
+var ErrClosed = errors.New("in-memory broker is closed")
+
+// topicLog is an append only log of one topic
+type topicLog struct {
+	messages []*sarama.ConsumerMessage
+}
+
+// Broker is a channel based IBroker keeping messages in memory.
+// It mirrors kafka semantics needed by handlers: messages get offsets and timestamps,
+// every consumer group receives every message of subscribed topics and commits
+// its own offsets. As topics have one partition, a topic is consumed by the first
+// handler of the group that subscribed to it, the others stay idle like extra kafka members
+type Broker struct {
+	mu sync.Mutex
+
+	topics map[string]*topicLog
+	// offsets are next offsets to consume per group and topic
+	offsets map[string]map[string]int64
+	owners  map[string]map[string]broker.IConsumerGroupHandler
+
+	// changed is closed and replaced on every append or commit to wake up waiters
+	changed chan struct{}
+
+	group      string
+	generation int32
+	closed     bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	now func() time.Time
+
+	logger *slog.Logger
+}
+
+func NewBroker(group string, logger *slog.Logger) *Broker {
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Broker{
+		topics:  make(map[string]*topicLog),
+		offsets: make(map[string]map[string]int64),
+		owners:  make(map[string]map[string]broker.IConsumerGroupHandler),
+		changed: make(chan struct{}),
+		group:   group,
+		ctx:     ctx,
+		cancel:  cancel,
+		now:     time.Now,
+		logger:  logger,
+	}
+}
+
+func (b *Broker) SendMessage(msg *sarama.ProducerMessage) (int32, int64, error) {
+
+	key, err := encode(msg.Key)
+	if err != nil {
+		return 0, 0, fmt.Errorf("can't encode message key with err %w", err)
+	}
+	value, err := encode(msg.Value)
+	if err != nil {
+		return 0, 0, fmt.Errorf("can't encode message value with err %w", err)
+	}
+
+	timestamp := msg.Timestamp
+	if timestamp.IsZero() {
+		timestamp = b.now().UTC()
+	}
+
+	headers := make([]*sarama.RecordHeader, 0, len(msg.Headers))
+	for _, header := range msg.Headers {
+		headers = append(headers, &sarama.RecordHeader{Key: header.Key, Value: header.Value})
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return 0, 0, ErrClosed
+	}
+
+	log := b.topic(msg.Topic)
+	offset := int64(len(log.messages))
+
+	log.messages = append(log.messages, &sarama.ConsumerMessage{
+		Topic:     msg.Topic,
+		Partition: partition,
+		Offset:    offset,
+		Key:       key,
+		Value:     value,
+		Headers:   headers,
+		Timestamp: timestamp,
+	})
+	b.notify()
+
+	msg.Partition, msg.Offset = partition, offset
+
+	return partition, offset, nil
+}
+
+// AddHandler subscribes handler to topics in the broker group
+func (b *Broker) AddHandler(topics []string, handler broker.IConsumerGroupHandler) error {
+	return b.AddGroupHandler(b.group, topics, handler)
+}
+
+// AddGroupHandler subscribes handler to topics in the given consumer group,
+// the group continues from its committed offsets
+func (b *Broker) AddGroupHandler(group string, topics []string, handler broker.IConsumerGroupHandler) error {
+
+	b.mu.Lock()
+
+	if b.closed {
+		b.mu.Unlock()
+		return ErrClosed
+	}
+
+	if b.owners[group] == nil {
+		b.owners[group] = make(map[string]broker.IConsumerGroupHandler)
+	}
+
+	claims := make(map[string][]int32)
+	initial := make(map[string]int64)
+	for _, topic := range topics {
+		if _, ok := b.owners[group][topic]; ok {
+			b.logger.Warn("IN_MEMORY_BROKER topic is already consumed by the group", "group", group, "topic", topic)
+			continue
+		}
+		b.owners[group][topic] = handler
+		b.topic(topic)
+		claims[topic] = []int32{partition}
+		initial[topic] = b.offset(group, topic)
+	}
+
+	b.generation++
+	sess := &session{
+		ctx:        b.ctx,
+		broker:     b,
+		group:      group,
+		memberID:   fmt.Sprintf("%s-%d", group, b.generation),
+		generation: b.generation,
+		claims:     claims,
+	}
+
+	b.mu.Unlock()
+
+	if len(claims) == 0 {
+		return nil
+	}
+
+	if err := handler.Setup(sess); err != nil {
+		return fmt.Errorf("can't setup handler with err %w", err)
+	}
+
+	var claimsWG sync.WaitGroup
+	for topic := range claims {
+		c := &claim{
+			topic:         topic,
+			initialOffset: initial[topic],
+			broker:        b,
+			messages:      make(chan *sarama.ConsumerMessage),
+		}
+
+		b.wg.Add(2)
+		claimsWG.Add(1)
+		go func() {
+			defer b.wg.Done()
+			b.feed(b.ctx, c)
+		}()
+		go func() {
+			defer b.wg.Done()
+			defer claimsWG.Done()
+			if err := handler.ConsumeClaim(sess, c); err != nil {
+				b.logger.Error("IN_MEMORY_BROKER ConsumeClaim", "group", group, "topic", c.topic, slog.Any("error", err))
+			}
+		}()
+	}
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		claimsWG.Wait()
+		if err := handler.Cleanup(sess); err != nil {
+			b.logger.Error("IN_MEMORY_BROKER Cleanup", "group", group, slog.Any("error", err))
+		}
+	}()
+
+	return nil
+}
+
+// Close stops all handlers and waits for them to return
+func (b *Broker) Close() error {
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	b.mu.Unlock()
+
+	b.cancel()
+	b.wg.Wait()
+
+	return nil
+}
+
+// Messages returns all messages ever sent to topic
+func (b *Broker) Messages(topic string) []*sarama.ConsumerMessage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	log, ok := b.topics[topic]
+	if !ok {
+		return nil
+	}
+	return append([]*sarama.ConsumerMessage(nil), log.messages...)
+}
+
+// HighWaterMark returns offset the next message of topic will get
+func (b *Broker) HighWaterMark(topic string) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.highWaterMark(topic)
+}
+
+// Offset returns committed offset of group for topic
+func (b *Broker) Offset(group, topic string) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.offset(group, topic)
+}
+
+// WaitConsumed waits until every subscribed group committed all messages of its topics,
+// including messages sent by handlers while waiting
+func (b *Broker) WaitConsumed(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		consumed := b.consumed()
+		changed := b.changed
+		b.mu.Unlock()
+
+		if consumed {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (b *Broker) consumed() bool {
+	for group, topics := range b.owners {
+		for topic := range topics {
+			if b.offset(group, topic) < b.highWaterMark(topic) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// feed sends messages of the claim topic starting from its initial offset
+func (b *Broker) feed(ctx context.Context, c *claim) {
+	defer close(c.messages)
+
+	offset := c.initialOffset
+	for {
+		b.mu.Lock()
+		var msg *sarama.ConsumerMessage
+		if offset < b.highWaterMark(c.topic) {
+			msg = b.topics[c.topic].messages[offset]
+		}
+		changed := b.changed
+		b.mu.Unlock()
+
+		if msg == nil {
+			select {
+			case <-changed:
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		select {
+		case c.messages <- msg:
+			offset++
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (b *Broker) commit(group, topic string, offset int64, reset bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.offsets[group] == nil {
+		b.offsets[group] = make(map[string]int64)
+	}
+	if !reset && offset <= b.offsets[group][topic] {
+		return
+	}
+	b.offsets[group][topic] = offset
+	b.notify()
+}
+
+func (b *Broker) topic(name string) *topicLog {
+	log, ok := b.topics[name]
+	if !ok {
+		log = &topicLog{}
+		b.topics[name] = log
+	}
+	return log
+}
+
+func (b *Broker) highWaterMark(topic string) int64 {
+	log, ok := b.topics[topic]
+	if !ok {
+		return 0
+	}
+	return int64(len(log.messages))
+}
+
+func (b *Broker) offset(group, topic string) int64 {
+	return b.offsets[group][topic]
+}
+
+func (b *Broker) notify() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+func encode(encoder sarama.Encoder) ([]byte, error) {
+	if encoder == nil {
+		return nil, nil
+	}
+	return encoder.Encode()
+}
@@ -0,0 +1,132 @@
+package inmemory
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingHandler remembers values of consumed messages
+type recordingHandler struct {
+	mu       sync.Mutex
+	consumed []string
+
+	ready chan bool
+}
+
+func newRecordingHandler() *recordingHandler {
+	return &recordingHandler{ready: make(chan bool)}
+}
+
+func (h *recordingHandler) Ready() {}
+
+func (h *recordingHandler) WaitReady() {
+	<-h.ready
+}
+
+func (h *recordingHandler) Setup(sarama.ConsumerGroupSession) error {
+	close(h.ready)
+	return nil
+}
+
+func (h *recordingHandler) Cleanup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (h *recordingHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for {
+		select {
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+			h.mu.Lock()
+			h.consumed = append(h.consumed, string(msg.Value))
+			h.mu.Unlock()
+			session.MarkMessage(msg, "")
+		case <-session.Context().Done():
+			return nil
+		}
+	}
+}
+
+func (h *recordingHandler) values() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.consumed...)
+}
+
+func send(t *testing.T, b *Broker, topic, value string) {
+	_, _, err := b.SendMessage(&sarama.ProducerMessage{Topic: topic, Value: sarama.StringEncoder(value)})
+	assert.Nil(t, err)
+}
+
+func waitConsumed(t *testing.T, b *Broker) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.Nil(t, b.WaitConsumed(ctx))
+}
+
+func TestBroker_SendMessage(t *testing.T) {
+
+	b := NewBroker(DefaultGroup, slog.Default())
+	defer b.Close()
+
+	sentAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	_, offset, err := b.SendMessage(&sarama.ProducerMessage{Topic: "a", Value: sarama.StringEncoder("1"), Timestamp: sentAt})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), offset)
+
+	_, offset, err = b.SendMessage(&sarama.ProducerMessage{Topic: "a", Value: sarama.StringEncoder("2")})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), offset)
+
+	messages := b.Messages("a")
+	assert.Equal(t, 2, len(messages))
+	assert.Equal(t, sentAt, messages[0].Timestamp)
+	assert.False(t, messages[1].Timestamp.IsZero())
+	assert.Equal(t, int64(2), b.HighWaterMark("a"))
+}
+
+func TestBroker_Groups(t *testing.T) {
+
+	b := NewBroker(DefaultGroup, slog.Default())
+	defer b.Close()
+
+	send(t, b, "a", "1")
+
+	first := newRecordingHandler()
+	assert.Nil(t, b.AddHandler([]string{"a", "b"}, first))
+
+	other := newRecordingHandler()
+	assert.Nil(t, b.AddGroupHandler("other", []string{"a"}, other))
+
+	send(t, b, "b", "2")
+	send(t, b, "a", "3")
+	waitConsumed(t, b)
+
+	assert.ElementsMatch(t, []string{"1", "2", "3"}, first.values())
+	assert.Equal(t, []string{"1", "3"}, other.values())
+	assert.Equal(t, int64(2), b.Offset(DefaultGroup, "a"))
+	assert.Equal(t, int64(2), b.Offset("other", "a"))
+}
+
+func TestBroker_Close(t *testing.T) {
+
+	b := NewBroker(DefaultGroup, slog.Default())
+
+	handler := newRecordingHandler()
+	assert.Nil(t, b.AddHandler([]string{"a"}, handler))
+	handler.WaitReady()
+
+	assert.Nil(t, b.Close())
+
+	_, _, err := b.SendMessage(&sarama.ProducerMessage{Topic: "a", Value: sarama.StringEncoder("1")})
+	assert.ErrorIs(t, err, ErrClosed)
+}
@@ -0,0 +1,282 @@
+package inmemory_test
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rauzh/cd-core/models"
+	"github.com/rauzh/cd-core/repo/mocks"
+	"github.com/rauzh/cd-core/requests/base"
+	"github.com/rauzh/cd-core/requests/broker/events"
+	"github.com/rauzh/cd-core/requests/broker/inmemory"
+	"github.com/rauzh/cd-core/requests/broker/outbox"
+	"github.com/rauzh/cd-core/requests/broker/outbox/relay"
+	outboxRepoMocks "github.com/rauzh/cd-core/requests/broker/outbox/repo/mocks"
+	publishBroker "github.com/rauzh/cd-core/requests/broker/publish"
+	signContractBroker "github.com/rauzh/cd-core/requests/broker/sign_contract"
+	criteria "github.com/rauzh/cd-core/requests/criteria_controller"
+	"github.com/rauzh/cd-core/requests/publish"
+	publishReqRepoMocks "github.com/rauzh/cd-core/requests/publish/repo/mocks"
+	publishUseCase "github.com/rauzh/cd-core/requests/publish/usecase"
+	"github.com/rauzh/cd-core/requests/sign_contract"
+	signReqRepoMocks "github.com/rauzh/cd-core/requests/sign_contract/repo/mocks"
+	"github.com/rauzh/cd-core/requests/sign_contract/usecase"
+	cdtime "github.com/rauzh/cd-core/time"
+	transacMock "github.com/rauzh/cd-core/transactor/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// memOutbox keeps outbox messages of the mocked repo, the relay and consumers
+// run in other goroutines, so messages are read under the lock only
+type memOutbox struct {
+	mu      sync.Mutex
+	pending []outbox.OutboxMessage
+}
+
+func newMemOutbox(outboxMockRepo *outboxRepoMocks.OutboxRepo, relays, sent int) *memOutbox {
+
+	memOutbox := &memOutbox{}
+
+	outboxMockRepo.EXPECT().Create(mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, msg *outbox.OutboxMessage) error {
+			memOutbox.mu.Lock()
+			defer memOutbox.mu.Unlock()
+			msg.OutboxID = uint64(len(memOutbox.pending) + 1)
+			memOutbox.pending = append(memOutbox.pending, *msg)
+			return nil
+		})
+	outboxMockRepo.EXPECT().GetPending(mock.Anything, relay.DefaultBatchSize).RunAndReturn(
+		func(ctx context.Context, limit int) ([]outbox.OutboxMessage, error) {
+			memOutbox.mu.Lock()
+			defer memOutbox.mu.Unlock()
+			var notSent []outbox.OutboxMessage
+			for _, msg := range memOutbox.pending {
+				if !msg.IsSent() {
+					notSent = append(notSent, msg)
+				}
+			}
+			return notSent, nil
+		}).Times(relays)
+	outboxMockRepo.EXPECT().MarkSent(mock.Anything, mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, id uint64, sentAt time.Time) error {
+			memOutbox.mu.Lock()
+			defer memOutbox.mu.Unlock()
+			memOutbox.pending[id-1].SentAt = sentAt
+			return nil
+		}).Times(sent)
+
+	return memOutbox
+}
+
+func (memOutbox *memOutbox) topics() []string {
+	memOutbox.mu.Lock()
+	defer memOutbox.mu.Unlock()
+
+	topics := make([]string, 0, len(memOutbox.pending))
+	for _, msg := range memOutbox.pending {
+		topics = append(topics, msg.Topic)
+	}
+	return topics
+}
+
+func inTransaction(transactionMock *transacMock.Transactor) {
+	transactionMock.EXPECT().WithinTransaction(mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+}
+
+func TestSignContractFlow(t *testing.T) {
+
+	transactionMock := transacMock.NewTransactor(t)
+	outboxMockRepo := outboxRepoMocks.NewOutboxRepo(t)
+	signReqMockRepo := signReqRepoMocks.NewSignContractRequestRepo(t)
+	mngMockRepo := mocks.NewManagerRepo(t)
+	usrMockRepo := mocks.NewUserRepo(t)
+	artMockRepo := mocks.NewArtistRepo(t)
+
+	inTransaction(transactionMock)
+
+	// outbox and requests are kept in memory by mocks
+	memOutbox := newMemOutbox(outboxMockRepo, 2, 3)
+
+	var mu sync.Mutex
+	stored := make(map[uint64]sign_contract.SignContractRequest)
+
+	store := func(ctx context.Context, req *sign_contract.SignContractRequest) error {
+		mu.Lock()
+		defer mu.Unlock()
+		stored[req.RequestID] = *req
+		return nil
+	}
+
+	signReqMockRepo.EXPECT().Create(mock.Anything, mock.Anything).RunAndReturn(store).Once()
+	signReqMockRepo.EXPECT().Update(mock.Anything, mock.Anything).RunAndReturn(store).Twice()
+
+	mngMockRepo.EXPECT().GetRandManagerID(mock.Anything).Return(uint64(9), nil).Once()
+
+	usrMockRepo.EXPECT().UpdateType(mock.Anything, uint64(12), models.ArtistUser).Return(nil).Once()
+	artMockRepo.EXPECT().Create(mock.Anything, mock.MatchedBy(func(artist *models.Artist) bool {
+		return artist.UserID == 12 && artist.ManagerID == 9
+	})).Return(nil).Once()
+
+	b := inmemory.NewBroker(inmemory.DefaultGroup, slog.Default())
+	defer b.Close()
+
+	criterias, _ := criteria.BuildCollection()
+	handler, _ := signContractBroker.InitSignContractProceedToManagerHandler(
+		b, signReqMockRepo, mngMockRepo, criterias, transactionMock, outboxMockRepo, nil, slog.Default())
+	assert.Nil(t, b.AddHandler([]string{signContractBroker.SignRequestProceedToManager}, handler))
+
+	signReqUseCase, _ := usecase.NewSignContractRequestUseCase(usrMockRepo, artMockRepo, transactionMock, outboxMockRepo, signReqMockRepo, slog.Default())
+
+	// apply
+	err := signReqUseCase.Apply(&sign_contract.SignContractRequest{
+		Request:  base.Request{RequestID: 1, Type: sign_contract.SignRequest, ApplierID: 12},
+		Nickname: "skibidi",
+	})
+	assert.Nil(t, err)
+
+	sent, err := relay.NewRelay(outboxMockRepo, b, slog.Default()).RelayPending(context.Background())
+	assert.Nil(t, err)
+	// proceed to manager message and RequestApplied event
+	assert.Equal(t, 2, sent)
+
+	// proceed to manager
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.Nil(t, b.WaitConsumed(ctx))
+
+	mu.Lock()
+	signReq := stored[1]
+	mu.Unlock()
+	assert.Equal(t, base.OnApprovalRequest, signReq.Status)
+	assert.Equal(t, uint64(9), signReq.ManagerID)
+
+	// RequestRoutedToManager event is stored in outbox with the request update
+	sent, err = relay.NewRelay(outboxMockRepo, b, slog.Default()).RelayPending(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, sent)
+
+	// approve
+	assert.Nil(t, signReqUseCase.Accept(&signReq))
+
+	mu.Lock()
+	assert.Equal(t, base.ClosedRequest, stored[1].Status)
+	mu.Unlock()
+
+	assert.Equal(t, 1, len(b.Messages(events.RequestAppliedTopic)))
+	assert.Equal(t, 1, len(b.Messages(events.RequestRoutedToManagerTopic)))
+
+	routed, err := events.Decode[events.RequestRoutedToManager](b.Messages(events.RequestRoutedToManagerTopic)[0])
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(9), routed.ManagerID)
+
+	// events of accept are stored in outbox with the artist
+	topics := memOutbox.topics()
+	if assert.Len(t, topics, 5) {
+		assert.Equal(t, []string{events.ArtistSignedTopic, events.RequestAcceptedTopic}, topics[3:])
+	}
+}
+
+func TestPublishFlow(t *testing.T) {
+
+	transactionMock := transacMock.NewTransactor(t)
+	outboxMockRepo := outboxRepoMocks.NewOutboxRepo(t)
+	publishMockRepo := publishReqRepoMocks.NewPublishRequestRepo(t)
+	pbcMockRepo := mocks.NewPublicationRepo(t)
+	rlsMockRepo := mocks.NewReleaseRepo(t)
+	artMockRepo := mocks.NewArtistRepo(t)
+
+	inTransaction(transactionMock)
+
+	// outbox and requests are kept in memory by mocks
+	memOutbox := newMemOutbox(outboxMockRepo, 2, 3)
+
+	var mu sync.Mutex
+	stored := make(map[uint64]publish.PublishRequest)
+
+	store := func(ctx context.Context, req *publish.PublishRequest) error {
+		mu.Lock()
+		defer mu.Unlock()
+		stored[req.RequestID] = *req
+		return nil
+	}
+
+	publishMockRepo.EXPECT().Create(mock.Anything, mock.Anything).RunAndReturn(store).Once()
+	// processing, on approval and closed
+	publishMockRepo.EXPECT().Update(mock.Anything, mock.Anything).RunAndReturn(store).Times(3)
+
+	expectedDate := cdtime.GetToday().AddDate(0, 1, 0)
+
+	rlsMockRepo.EXPECT().Get(mock.Anything, uint64(777)).Return(
+		&models.Release{ReleaseID: 777, ArtistID: 199, Status: models.UnpublishedRelease}, nil).Once()
+	// checked on apply and routed on proceed to manager
+	artMockRepo.EXPECT().GetByUserID(mock.Anything, uint64(12)).Return(
+		&models.Artist{ArtistID: 199, ManagerID: 9, ContractTerm: expectedDate.AddDate(1, 0, 0)}, nil).Twice()
+
+	pbcMockRepo.EXPECT().Create(mock.Anything, mock.MatchedBy(func(publication *models.Publication) bool {
+		return publication.ReleaseID == 777 && publication.ManagerID == 9 && publication.Date.Equal(expectedDate)
+	})).Return(nil).Once()
+	rlsMockRepo.EXPECT().UpdateStatus(mock.Anything, uint64(777), models.PublishedRelease).Return(nil).Once()
+
+	b := inmemory.NewBroker(inmemory.DefaultGroup, slog.Default())
+	defer b.Close()
+
+	criterias, _ := criteria.BuildCollection()
+	handler, _ := publishBroker.InitPublishProceedToManagerConsumerHandler(
+		b, publishMockRepo, artMockRepo, criterias, transactionMock, outboxMockRepo, nil, slog.Default())
+	assert.Nil(t, b.AddHandler([]string{publishBroker.PublishRequestProceedToManager}, handler))
+
+	pubReqUseCase, _ := publishUseCase.NewPublishRequestUseCase(nil, pbcMockRepo, rlsMockRepo, artMockRepo,
+		transactionMock, outboxMockRepo, publishMockRepo, slog.Default())
+
+	// apply
+	err := pubReqUseCase.Apply(&publish.PublishRequest{
+		Request:      base.Request{RequestID: 1, Type: publish.PubReq, ApplierID: 12},
+		ReleaseID:    777,
+		ExpectedDate: expectedDate,
+	})
+	assert.Nil(t, err)
+
+	sent, err := relay.NewRelay(outboxMockRepo, b, slog.Default()).RelayPending(context.Background())
+	assert.Nil(t, err)
+	// proceed to manager message and RequestApplied event
+	assert.Equal(t, 2, sent)
+
+	// proceed to manager
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.Nil(t, b.WaitConsumed(ctx))
+
+	mu.Lock()
+	pubReq := stored[1]
+	mu.Unlock()
+	assert.Equal(t, base.OnApprovalRequest, pubReq.Status)
+	assert.Equal(t, uint64(9), pubReq.ManagerID)
+
+	// RequestRoutedToManager event is stored in outbox with the request update
+	sent, err = relay.NewRelay(outboxMockRepo, b, slog.Default()).RelayPending(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, sent)
+
+	// approve
+	assert.Nil(t, pubReqUseCase.Accept(&pubReq))
+
+	mu.Lock()
+	assert.Equal(t, base.ClosedRequest, stored[1].Status)
+	mu.Unlock()
+
+	assert.Equal(t, 1, len(b.Messages(events.RequestAppliedTopic)))
+	assert.Equal(t, 1, len(b.Messages(events.RequestRoutedToManagerTopic)))
+
+	// events of accept are stored in outbox with the publication
+	topics := memOutbox.topics()
+	if assert.Len(t, topics, 5) {
+		assert.Equal(t, []string{events.PublicationCreatedTopic, events.RequestAcceptedTopic}, topics[3:])
+	}
+}
@@ -0,0 +1,78 @@
+package inmemory
+
+import (
+	"context"
+
+	"github.com/IBM/sarama"
+)
+
+// session is sarama.ConsumerGroupSession of one handler,
+// offsets are committed right when they are marked
+type session struct {
+	ctx    context.Context
+	broker *Broker
+
+	group      string
+	memberID   string
+	generation int32
+	claims     map[string][]int32
+}
+
+func (sess *session) Claims() map[string][]int32 {
+	return sess.claims
+}
+
+func (sess *session) MemberID() string {
+	return sess.memberID
+}
+
+func (sess *session) GenerationID() int32 {
+	return sess.generation
+}
+
+func (sess *session) MarkOffset(topic string, partition int32, offset int64, metadata string) {
+	sess.broker.commit(sess.group, topic, offset, false)
+}
+
+func (sess *session) Commit() {}
+
+func (sess *session) ResetOffset(topic string, partition int32, offset int64, metadata string) {
+	sess.broker.commit(sess.group, topic, offset, true)
+}
+
+func (sess *session) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	sess.MarkOffset(msg.Topic, msg.Partition, msg.Offset+1, metadata)
+}
+
+func (sess *session) Context() context.Context {
+	return sess.ctx
+}
+
+// claim is sarama.ConsumerGroupClaim of the only topic partition
+type claim struct {
+	topic         string
+	initialOffset int64
+	broker        *Broker
+
+	messages chan *sarama.ConsumerMessage
+}
+
+func (c *claim) Topic() string {
+	return c.topic
+}
+
+func (c *claim) Partition() int32 {
+	return partition
+}
+
+func (c *claim) InitialOffset() int64 {
+	return c.initialOffset
+}
+
+func (c *claim) HighWaterMarkOffset() int64 {
+	return c.broker.HighWaterMark(c.topic)
+}
+
+func (c *claim) Messages() <-chan *sarama.ConsumerMessage {
+	return c.messages
+}
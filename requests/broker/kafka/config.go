@@ -0,0 +1,214 @@
+package kafka
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+const (
+	RebalanceRange      = "range"
+	RebalanceRoundRobin = "roundrobin"
+	RebalanceSticky     = "sticky"
+)
+
+var (
+	ErrNoBrokers        = errors.New("no kafka brokers in config")
+	ErrNoGroup          = errors.New("no consumer group id in config")
+	ErrUnknownRebalance = errors.New("unknown rebalance strategy")
+	ErrUnknownSASL      = errors.New("unknown sasl mechanism")
+	ErrNoSCRAMClient    = errors.New("scram mechanism needs scram client generator")
+)
+
+type Config struct {
+	Brokers  []string
+	ClientID string
+	// Version of kafka protocol, sarama default if empty
+	Version string
+
+	// GroupID is the consumer group of AddHandler
+	GroupID string
+	// Rebalance is one of RebalanceRange, RebalanceRoundRobin, RebalanceSticky, range if empty
+	Rebalance string
+	// OldestOffset makes new groups consume topics from the beginning instead of new messages only
+	OldestOffset bool
+
+	// RetryMax and RetryBackoff are used by producer, metadata and rebalance retries
+	RetryMax     int
+	RetryBackoff time.Duration
+
+	SASL SASLConfig
+	TLS  TLSConfig
+}
+
+type SASLConfig struct {
+	Enable bool
+	// Mechanism is sarama.SASLTypePlaintext (default), sarama.SASLTypeSCRAMSHA256 or sarama.SASLTypeSCRAMSHA512
+	Mechanism string
+	User      string
+	Password  string
+	// SCRAMClient creates scram client, required for SCRAM mechanisms
+	SCRAMClient func() sarama.SCRAMClient
+}
+
+type TLSConfig struct {
+	Enable             bool
+	CAFile             string
+	CertFile           string
+	KeyFile            string
+	InsecureSkipVerify bool
+}
+
+func DefaultConfig() Config {
+	return Config{
+		ClientID:     "cd-core",
+		GroupID:      "cd-core",
+		Rebalance:    RebalanceRange,
+		OldestOffset: true,
+		RetryMax:     5,
+		RetryBackoff: 100 * time.Millisecond,
+	}
+}
+
+// SaramaConfig builds sarama config for sync producer and consumer groups
+func (cfg Config) SaramaConfig() (*sarama.Config, error) {
+
+	if len(cfg.Brokers) == 0 {
+		return nil, ErrNoBrokers
+	}
+
+	sc := sarama.NewConfig()
+
+	if cfg.ClientID != "" {
+		sc.ClientID = cfg.ClientID
+	}
+
+	if cfg.Version != "" {
+		version, err := sarama.ParseKafkaVersion(cfg.Version)
+		if err != nil {
+			return nil, fmt.Errorf("can't parse kafka version with err %w", err)
+		}
+		sc.Version = version
+	}
+
+	// sync producer requires both
+	sc.Producer.Return.Successes = true
+	sc.Producer.Return.Errors = true
+	sc.Producer.RequiredAcks = sarama.WaitForAll
+
+	if cfg.RetryMax > 0 {
+		sc.Producer.Retry.Max = cfg.RetryMax
+		sc.Metadata.Retry.Max = cfg.RetryMax
+		sc.Consumer.Group.Rebalance.Retry.Max = cfg.RetryMax
+	}
+	if cfg.RetryBackoff > 0 {
+		sc.Producer.Retry.Backoff = cfg.RetryBackoff
+		sc.Metadata.Retry.Backoff = cfg.RetryBackoff
+		sc.Consumer.Retry.Backoff = cfg.RetryBackoff
+		sc.Consumer.Group.Rebalance.Retry.Backoff = cfg.RetryBackoff
+	}
+
+	strategy, err := rebalanceStrategy(cfg.Rebalance)
+	if err != nil {
+		return nil, err
+	}
+	sc.Consumer.Group.Rebalance.GroupStrategies = []sarama.BalanceStrategy{strategy}
+
+	if cfg.OldestOffset {
+		sc.Consumer.Offsets.Initial = sarama.OffsetOldest
+	} else {
+		sc.Consumer.Offsets.Initial = sarama.OffsetNewest
+	}
+	sc.Consumer.Return.Errors = true
+
+	if err := cfg.SASL.apply(sc); err != nil {
+		return nil, err
+	}
+	if err := cfg.TLS.apply(sc); err != nil {
+		return nil, err
+	}
+
+	if err := sc.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid kafka config with err %w", err)
+	}
+
+	return sc, nil
+}
+
+func rebalanceStrategy(name string) (sarama.BalanceStrategy, error) {
+	switch name {
+	case "", RebalanceRange:
+		return sarama.NewBalanceStrategyRange(), nil
+	case RebalanceRoundRobin:
+		return sarama.NewBalanceStrategyRoundRobin(), nil
+	case RebalanceSticky:
+		return sarama.NewBalanceStrategySticky(), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownRebalance, name)
+	}
+}
+
+func (cfg SASLConfig) apply(sc *sarama.Config) error {
+	if !cfg.Enable {
+		return nil
+	}
+
+	sc.Net.SASL.Enable = true
+	sc.Net.SASL.User = cfg.User
+	sc.Net.SASL.Password = cfg.Password
+
+	switch cfg.Mechanism {
+	case "", sarama.SASLTypePlaintext:
+		sc.Net.SASL.Mechanism = sarama.SASLTypePlaintext
+	case sarama.SASLTypeSCRAMSHA256, sarama.SASLTypeSCRAMSHA512:
+		if cfg.SCRAMClient == nil {
+			return ErrNoSCRAMClient
+		}
+		sc.Net.SASL.Mechanism = sarama.SASLMechanism(cfg.Mechanism)
+		sc.Net.SASL.SCRAMClientGeneratorFunc = cfg.SCRAMClient
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownSASL, cfg.Mechanism)
+	}
+
+	return nil
+}
+
+func (cfg TLSConfig) apply(sc *sarama.Config) error {
+	if !cfg.Enable {
+		return nil
+	}
+
+	tlsCfg := &tls.Config{
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+
+	if cfg.CAFile != "" {
+		ca, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return fmt.Errorf("can't read kafka CA with err %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return fmt.Errorf("can't parse kafka CA %s", cfg.CAFile)
+		}
+		tlsCfg.RootCAs = pool
+	}
+
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return fmt.Errorf("can't load kafka client certificate with err %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+
+	sc.Net.TLS.Enable = true
+	sc.Net.TLS.Config = tlsCfg
+
+	return nil
+}
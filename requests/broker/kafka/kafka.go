@@ -0,0 +1,152 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/rauzh/cd-core/requests/broker"
+)
+
+var ErrClosed = errors.New("kafka broker is closed")
+
+// Broker is IBroker over sarama sync producer and consumer groups.
+// Every AddHandler call joins its own consumer group client and consumes
+// until Close, rejoining the group after every rebalance
+type Broker struct {
+	cfg       Config
+	saramaCfg *sarama.Config
+
+	producer sarama.SyncProducer
+
+	mu     sync.Mutex
+	groups []sarama.ConsumerGroup
+	closed bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	logger *slog.Logger
+}
+
+func NewBroker(cfg Config, logger *slog.Logger) (*Broker, error) {
+
+	saramaCfg, err := cfg.SaramaConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	producer, err := sarama.NewSyncProducer(cfg.Brokers, saramaCfg)
+	if err != nil {
+		return nil, fmt.Errorf("can't create kafka producer with err %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Broker{
+		cfg:       cfg,
+		saramaCfg: saramaCfg,
+		producer:  producer,
+		ctx:       ctx,
+		cancel:    cancel,
+		logger:    logger,
+	}, nil
+}
+
+func (b *Broker) SendMessage(msg *sarama.ProducerMessage) (int32, int64, error) {
+	return b.producer.SendMessage(msg)
+}
+
+// AddHandler consumes topics in the config consumer group
+func (b *Broker) AddHandler(topics []string, handler broker.IConsumerGroupHandler) error {
+	if b.cfg.GroupID == "" {
+		return ErrNoGroup
+	}
+	return b.AddGroupHandler(b.cfg.GroupID, topics, handler)
+}
+
+// AddGroupHandler consumes topics in the given consumer group
+func (b *Broker) AddGroupHandler(groupID string, topics []string, handler broker.IConsumerGroupHandler) error {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return ErrClosed
+	}
+
+	group, err := sarama.NewConsumerGroup(b.cfg.Brokers, groupID, b.saramaCfg)
+	if err != nil {
+		return fmt.Errorf("can't create consumer group %s with err %w", groupID, err)
+	}
+	b.groups = append(b.groups, group)
+
+	b.wg.Add(2)
+	go func() {
+		defer b.wg.Done()
+		b.consume(group, groupID, topics, handler)
+	}()
+	go func() {
+		defer b.wg.Done()
+		for err := range group.Errors() {
+			b.logger.Error("KAFKA_BROKER consumer group", "group", groupID, slog.Any("error", err))
+		}
+	}()
+
+	return nil
+}
+
+// consume runs sessions until broker is closed, Consume returns on every rebalance
+func (b *Broker) consume(group sarama.ConsumerGroup, groupID string, topics []string, handler broker.IConsumerGroupHandler) {
+	for {
+		err := group.Consume(b.ctx, topics, handler)
+		if errors.Is(err, sarama.ErrClosedConsumerGroup) {
+			return
+		}
+		if err != nil {
+			b.logger.Error("KAFKA_BROKER consume", "group", groupID, slog.Any("error", err))
+			select {
+			case <-time.After(b.saramaCfg.Consumer.Group.Rebalance.Retry.Backoff):
+			case <-b.ctx.Done():
+			}
+		}
+		if b.ctx.Err() != nil {
+			return
+		}
+		handler.Ready()
+	}
+}
+
+// Close stops consume loops letting handlers finish current messages, then closes clients
+func (b *Broker) Close() error {
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	groups := b.groups
+	b.mu.Unlock()
+
+	b.cancel()
+
+	var errs []error
+	for _, group := range groups {
+		if err := group.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	b.wg.Wait()
+
+	if err := b.producer.Close(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,135 @@
+package kafka
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/rauzh/cd-core/requests/broker"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testTopic = "topic"
+	testGroup = "group"
+)
+
+type testMessage struct {
+	RequestID uint64 `json:"request_id"`
+}
+
+func newMockBroker(t *testing.T) *sarama.MockBroker {
+
+	mockBroker := sarama.NewMockBroker(t, 0)
+
+	mockBroker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(mockBroker.Addr(), mockBroker.BrokerID()).
+			SetLeader(testTopic, 0, mockBroker.BrokerID()),
+		"ProduceRequest": sarama.NewMockProduceResponse(t),
+		"OffsetRequest": sarama.NewMockOffsetResponse(t).
+			SetOffset(testTopic, 0, sarama.OffsetOldest, 0).
+			SetOffset(testTopic, 0, sarama.OffsetNewest, 1),
+		"FindCoordinatorRequest": sarama.NewMockFindCoordinatorResponse(t).
+			SetCoordinator(sarama.CoordinatorGroup, testGroup, mockBroker),
+		"HeartbeatRequest": sarama.NewMockHeartbeatResponse(t),
+		"JoinGroupRequest": sarama.NewMockJoinGroupResponse(t).SetGroupProtocol(sarama.RangeBalanceStrategyName),
+		"SyncGroupRequest": sarama.NewMockSyncGroupResponse(t).SetMemberAssignment(
+			&sarama.ConsumerGroupMemberAssignment{
+				Version: 0,
+				Topics:  map[string][]int32{testTopic: {0}},
+			}),
+		"OffsetFetchRequest": sarama.NewMockOffsetFetchResponse(t).
+			SetOffset(testGroup, testTopic, 0, 0, "", sarama.ErrNoError).SetError(sarama.ErrNoError),
+		"FetchRequest": sarama.NewMockSequence(
+			sarama.NewMockFetchResponse(t, 1).
+				SetMessage(testTopic, 0, 0, sarama.StringEncoder(`{"request_id": 7}`)),
+			sarama.NewMockFetchResponse(t, 1),
+		),
+		"OffsetCommitRequest": sarama.NewMockOffsetCommitResponse(t),
+		"LeaveGroupRequest":   sarama.NewMockLeaveGroupResponse(t),
+	})
+
+	return mockBroker
+}
+
+func newTestConfig(addr string) Config {
+	cfg := DefaultConfig()
+	cfg.Brokers = []string{addr}
+	cfg.GroupID = testGroup
+	cfg.Version = "2.0.0"
+	cfg.RetryBackoff = 10 * time.Millisecond
+	return cfg
+}
+
+func TestBroker_SendMessage(t *testing.T) {
+
+	mockBroker := newMockBroker(t)
+	defer mockBroker.Close()
+
+	b, err := NewBroker(newTestConfig(mockBroker.Addr()), slog.Default())
+	assert.Nil(t, err)
+
+	_, _, err = b.SendMessage(&sarama.ProducerMessage{Topic: testTopic, Value: sarama.StringEncoder("1")})
+	assert.Nil(t, err)
+
+	assert.Nil(t, b.Close())
+}
+
+func TestBroker_AddHandler(t *testing.T) {
+
+	mockBroker := newMockBroker(t)
+	defer mockBroker.Close()
+
+	b, err := NewBroker(newTestConfig(mockBroker.Addr()), slog.Default())
+	assert.Nil(t, err)
+
+	handled := make(chan uint64, 1)
+	handler, _ := broker.NewConsumer(broker.ConsumerConfig[testMessage]{
+		Topics: []string{testTopic},
+		Handle: func(ctx context.Context, msg *testMessage) error {
+			handled <- msg.RequestID
+			return nil
+		},
+	}, slog.Default())
+
+	assert.Nil(t, b.AddHandler([]string{testTopic}, handler))
+
+	select {
+	case requestID := <-handled:
+		assert.Equal(t, uint64(7), requestID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("message is not consumed")
+	}
+
+	assert.Nil(t, b.Close())
+
+	assert.ErrorIs(t, b.AddHandler([]string{testTopic}, handler), ErrClosed)
+}
+
+func TestConfig_SaramaConfig(t *testing.T) {
+
+	_, err := Config{}.SaramaConfig()
+	assert.ErrorIs(t, err, ErrNoBrokers)
+
+	cfg := DefaultConfig()
+	cfg.Brokers = []string{"localhost:9092"}
+
+	cfg.Rebalance = "unknown"
+	_, err = cfg.SaramaConfig()
+	assert.ErrorIs(t, err, ErrUnknownRebalance)
+
+	cfg.Rebalance = RebalanceSticky
+	cfg.SASL = SASLConfig{Enable: true, Mechanism: sarama.SASLTypeSCRAMSHA512, User: "u", Password: "p"}
+	_, err = cfg.SaramaConfig()
+	assert.ErrorIs(t, err, ErrNoSCRAMClient)
+
+	cfg.SASL.Mechanism = sarama.SASLTypePlaintext
+	sc, err := cfg.SaramaConfig()
+	assert.Nil(t, err)
+	assert.True(t, sc.Net.SASL.Enable)
+	assert.Equal(t, sarama.OffsetOldest, sc.Consumer.Offsets.Initial)
+	assert.Equal(t, sarama.StickyBalanceStrategyName, sc.Consumer.Group.Rebalance.GroupStrategies[0].Name())
+}
@@ -0,0 +1,85 @@
+package outbox
+
+import (
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// OutboxMessage is a broker message stored in the same transaction
+// as the data it is about and sent later by relay
+type OutboxMessage struct {
+	OutboxID  uint64
+	Topic     string
+	Key       []byte
+	Value     []byte
+	Headers   map[string]string
+	CreatedAt time.Time
+	SentAt    time.Time
+	Attempts  int
+	LastError string
+	// ParkedAt is set when relay gave up on message, parked messages are not pending anymore
+	ParkedAt time.Time
+}
+
+func NewOutboxMessage(msg *sarama.ProducerMessage) (*OutboxMessage, error) {
+
+	outboxMsg := &OutboxMessage{
+		Topic:     msg.Topic,
+		Headers:   make(map[string]string, len(msg.Headers)),
+		CreatedAt: msg.Timestamp,
+	}
+
+	if outboxMsg.CreatedAt.IsZero() {
+		outboxMsg.CreatedAt = time.Now().UTC()
+	}
+
+	if msg.Key != nil {
+		key, err := msg.Key.Encode()
+		if err != nil {
+			return nil, err
+		}
+		outboxMsg.Key = key
+	}
+
+	if msg.Value != nil {
+		value, err := msg.Value.Encode()
+		if err != nil {
+			return nil, err
+		}
+		outboxMsg.Value = value
+	}
+
+	for _, header := range msg.Headers {
+		outboxMsg.Headers[string(header.Key)] = string(header.Value)
+	}
+
+	return outboxMsg, nil
+}
+
+func (outboxMsg *OutboxMessage) IsSent() bool {
+	return !outboxMsg.SentAt.IsZero()
+}
+
+func (outboxMsg *OutboxMessage) IsParked() bool {
+	return !outboxMsg.ParkedAt.IsZero()
+}
+
+func (outboxMsg *OutboxMessage) ToProducerMessage() *sarama.ProducerMessage {
+
+	msg := &sarama.ProducerMessage{
+		Topic:     outboxMsg.Topic,
+		Value:     sarama.ByteEncoder(outboxMsg.Value),
+		Timestamp: outboxMsg.CreatedAt,
+	}
+
+	if outboxMsg.Key != nil {
+		msg.Key = sarama.ByteEncoder(outboxMsg.Key)
+	}
+
+	for key, value := range outboxMsg.Headers {
+		msg.Headers = append(msg.Headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+	}
+
+	return msg
+}
@@ -0,0 +1,123 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/rauzh/cd-core/requests/broker"
+	"github.com/rauzh/cd-core/requests/broker/outbox"
+	outboxRepo "github.com/rauzh/cd-core/requests/broker/outbox/repo"
+)
+
+const (
+	DefaultInterval    = time.Second
+	DefaultBatchSize   = 100
+	DefaultMaxAttempts = 10
+)
+
+// Relay publishes pending outbox messages through broker.
+// Message is marked sent only after broker accepted it, so delivery is at-least-once:
+// if marking fails, the message will be sent again by the next relay pass.
+// Message that failed MaxAttempts times is parked, so it doesn't block the outbox
+type Relay struct {
+	repo   outboxRepo.OutboxRepo
+	broker broker.IBroker
+
+	Interval    time.Duration
+	BatchSize   int
+	MaxAttempts int
+
+	logger *slog.Logger
+}
+
+func NewRelay(r outboxRepo.OutboxRepo, b broker.IBroker, logger *slog.Logger) *Relay {
+	return &Relay{
+		repo:        r,
+		broker:      b,
+		Interval:    DefaultInterval,
+		BatchSize:   DefaultBatchSize,
+		MaxAttempts: DefaultMaxAttempts,
+		logger:      logger,
+	}
+}
+
+// Run relays pending messages every Interval until ctx is done
+func (relay *Relay) Run(ctx context.Context) {
+
+	ticker := time.NewTicker(relay.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := relay.RelayPending(ctx); err != nil {
+			relay.logger.Error("OUTBOX_RELAY Run", slog.Any("error", err))
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// RelayPending sends one batch of pending messages in order and returns the number of sent ones.
+// Failed message is skipped with the rest of messages of its topic and key,
+// so messages of one key are not reordered and the other keys are not blocked
+func (relay *Relay) RelayPending(ctx context.Context) (int, error) {
+
+	pending, err := relay.repo.GetPending(ctx, relay.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("can't get pending outbox messages with err %w", err)
+	}
+
+	sent := 0
+	failed := make(map[string]struct{})
+	var errs []error
+	for _, outboxMsg := range pending {
+
+		orderKey := outboxMsg.Topic + "/" + string(outboxMsg.Key)
+		if _, ok := failed[orderKey]; ok && outboxMsg.Key != nil {
+			continue
+		}
+
+		// message id header is stored with the message, so resent message keeps it
+		if _, _, err := relay.broker.SendMessage(outboxMsg.ToProducerMessage()); err != nil {
+			failed[orderKey] = struct{}{}
+			relay.markFailed(ctx, &outboxMsg, err)
+			errs = append(errs, fmt.Errorf("can't send outbox message %d with err %w", outboxMsg.OutboxID, err))
+			continue
+		}
+
+		if err := relay.repo.MarkSent(ctx, outboxMsg.OutboxID, time.Now().UTC()); err != nil {
+			return sent, fmt.Errorf("can't mark outbox message %d sent with err %w", outboxMsg.OutboxID, err)
+		}
+
+		sent++
+	}
+
+	if sent > 0 {
+		relay.logger.Debug("OUTBOX_RELAY RelayPending", "sent", sent)
+	}
+
+	return sent, errors.Join(errs...)
+}
+
+// markFailed counts the failed attempt and parks message after MaxAttempts
+func (relay *Relay) markFailed(ctx context.Context, outboxMsg *outbox.OutboxMessage, sendErr error) {
+
+	var err error
+	if outboxMsg.Attempts+1 >= relay.MaxAttempts {
+		relay.logger.Error("OUTBOX_RELAY parked message",
+			"outbox_id", outboxMsg.OutboxID, "topic", outboxMsg.Topic, "attempts", outboxMsg.Attempts+1, slog.Any("error", sendErr))
+		err = relay.repo.MarkParked(ctx, outboxMsg.OutboxID, time.Now().UTC(), sendErr.Error())
+	} else {
+		err = relay.repo.MarkFailed(ctx, outboxMsg.OutboxID, sendErr.Error())
+	}
+
+	if err != nil {
+		relay.logger.Error("OUTBOX_RELAY RelayPending", "outbox_id", outboxMsg.OutboxID, slog.Any("error", err))
+	}
+}
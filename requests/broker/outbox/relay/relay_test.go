@@ -0,0 +1,76 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/rauzh/cd-core/requests/broker"
+	broker_mocks "github.com/rauzh/cd-core/requests/broker/mocks"
+	"github.com/rauzh/cd-core/requests/broker/outbox"
+	outboxRepoMocks "github.com/rauzh/cd-core/requests/broker/outbox/repo/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func hasMessageID(id string) func(msg *sarama.ProducerMessage) bool {
+	return func(msg *sarama.ProducerMessage) bool {
+		for _, header := range msg.Headers {
+			if string(header.Key) == broker.HeaderMessageID {
+				return string(header.Value) == id
+			}
+		}
+		return false
+	}
+}
+
+func messageWithID(outboxID uint64, key, id string) outbox.OutboxMessage {
+	return outbox.OutboxMessage{
+		OutboxID: outboxID,
+		Topic:    "topic",
+		Key:      []byte(key),
+		Value:    []byte(id),
+		Headers:  map[string]string{broker.HeaderMessageID: id},
+	}
+}
+
+func TestRelay_RelayPending(t *testing.T) {
+
+	errBroker := errors.New("broker err")
+
+	mockRepo := outboxRepoMocks.NewOutboxRepo(t)
+	mockBroker := broker_mocks.NewIBroker(t)
+
+	mockRepo.EXPECT().GetPending(mock.Anything, DefaultBatchSize).Return([]outbox.OutboxMessage{
+		messageWithID(1, "a", "msg-1"),
+		messageWithID(2, "b", "msg-2"),
+		messageWithID(3, "b", "msg-3"),
+		messageWithID(4, "a", "msg-4"),
+		{OutboxID: 5, Topic: "topic", Key: []byte("c"), Value: []byte("5"), Attempts: DefaultMaxAttempts - 1,
+			Headers: map[string]string{broker.HeaderMessageID: "msg-5"}},
+	}, nil).Once()
+
+	// message id given by producer is sent as is
+	mockBroker.EXPECT().SendMessage(mock.MatchedBy(hasMessageID("msg-1"))).Return(0, 0, nil).Once()
+	mockRepo.EXPECT().MarkSent(mock.Anything, uint64(1), mock.Anything).Return(nil).Once()
+
+	mockBroker.EXPECT().SendMessage(mock.MatchedBy(hasMessageID("msg-2"))).Return(0, 0, errBroker).Once()
+	mockRepo.EXPECT().MarkFailed(mock.Anything, uint64(2), errBroker.Error()).Return(nil).Once()
+
+	mockBroker.EXPECT().SendMessage(mock.MatchedBy(hasMessageID("msg-4"))).Return(0, 0, nil).Once()
+	mockRepo.EXPECT().MarkSent(mock.Anything, uint64(4), mock.Anything).Return(nil).Once()
+
+	mockBroker.EXPECT().SendMessage(mock.MatchedBy(hasMessageID("msg-5"))).Return(0, 0, errBroker).Once()
+	mockRepo.EXPECT().MarkParked(mock.Anything, uint64(5), mock.Anything, errBroker.Error()).Return(nil).Once()
+
+	relay := NewRelay(mockRepo, mockBroker, slog.Default())
+
+	sent, err := relay.RelayPending(context.Background())
+
+	// the third message is not sent to keep the order of key "b",
+	// the failed ones don't block the other keys
+	assert.ErrorIs(t, err, errBroker)
+	assert.Equal(t, 2, sent)
+}
@@ -0,0 +1,20 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/rauzh/cd-core/requests/broker/outbox"
+)
+
+//go:generate mockery --name OutboxRepo --with-expecter
+type OutboxRepo interface {
+	Create(context.Context, *outbox.OutboxMessage) error
+	// GetPending returns not sent and not parked messages ordered by OutboxID
+	GetPending(ctx context.Context, limit int) ([]outbox.OutboxMessage, error)
+	MarkSent(ctx context.Context, id uint64, sentAt time.Time) error
+	// MarkFailed increments Attempts and stores reason as LastError
+	MarkFailed(ctx context.Context, id uint64, reason string) error
+	// MarkParked is MarkFailed that also excludes message from pending ones
+	MarkParked(ctx context.Context, id uint64, parkedAt time.Time, reason string) error
+}
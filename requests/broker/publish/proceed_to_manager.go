@@ -4,50 +4,70 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/rauzh/cd-core/requests/base"
+	"github.com/rauzh/cd-core/requests/broker/events"
+	"github.com/rauzh/cd-core/requests/broker/tracing"
 	criteria "github.com/rauzh/cd-core/requests/criteria_controller"
 	"github.com/rauzh/cd-core/requests/publish"
+	"go.opentelemetry.io/otel/attribute"
 )
 
-func (handler *PublishProceedToManagerConsumerHandler) proceedToManager(pubReq *publish.PublishRequest) error {
-
-	ctx := context.Background()
+func (handler *PublishProceedToManagerConsumerHandler) proceedToManager(ctx context.Context, pubReq *publish.PublishRequest) error {
 
 	pubReq.Status = base.ProcessingRequest
 
 	err := handler.publishRepo.Update(ctx, pubReq)
 	if err != nil {
-		handler.logger.Error("PUBLISH_HANDLER proceedToManager", slog.Any("error", err))
+		handler.logger.ErrorContext(ctx, "PUBLISH_HANDLER proceedToManager", slog.Any("error", err))
 		return fmt.Errorf("cant proceed publish request to manager: update repo with err %w", err)
 	}
 
-	handler.computeDegree(pubReq)
+	handler.computeDegree(ctx, pubReq)
 
 	artist, err := handler.artistRepo.GetByUserID(ctx, pubReq.ApplierID)
 	if err != nil {
-		handler.logger.Error("PUBLISH_HANDLER proceedToManager", slog.Any("error", err))
+		handler.logger.ErrorContext(ctx, "PUBLISH_HANDLER proceedToManager", slog.Any("error", err))
 		return fmt.Errorf("cant proceed publish request to manager: get artist with err %w", err)
 	}
 
 	pubReq.ManagerID = artist.ManagerID
 	pubReq.Status = base.OnApprovalRequest
 
-	err = handler.publishRepo.Update(ctx, pubReq)
+	err = handler.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := handler.publishRepo.Update(ctx, pubReq); err != nil {
+			return err
+		}
+
+		return handler.eventPublisher.Publish(ctx, &events.RequestRoutedToManager{
+			RequestID:  pubReq.RequestID,
+			Type:       pubReq.Type,
+			ManagerID:  pubReq.ManagerID,
+			Grade:      pubReq.Grade,
+			OccurredAt: time.Now().UTC(),
+		})
+	})
 	if err != nil {
-		handler.logger.Error("PUBLISH_HANDLER proceedToManager", slog.Any("error", err))
+		handler.logger.ErrorContext(ctx, "PUBLISH_HANDLER proceedToManager", slog.Any("error", err))
 		return err
 	}
-	handler.logger.Info("PUBLISH_HANDLER proceedToManager", "pubreq_manager", pubReq.ManagerID)
+	handler.logger.InfoContext(ctx, "PUBLISH_HANDLER proceedToManager", "pubreq_manager", pubReq.ManagerID)
+
 	return nil
 }
 
-func (handler *PublishProceedToManagerConsumerHandler) computeDegree(pubReq *publish.PublishRequest) {
+func (handler *PublishProceedToManagerConsumerHandler) computeDegree(ctx context.Context, pubReq *publish.PublishRequest) {
+
+	_, span := tracing.Start(ctx, "criteria.Apply", attribute.Int64("request.id", int64(pubReq.RequestID)))
+	defer span.End()
 
 	summaryDiff := handler.criterias.Apply(pubReq)
 
 	pubReq.Grade = summaryDiff.ResultDiff
-	for criteriaName, criteriaDiff := range summaryDiff.ResultExplanation {
+	span.SetAttributes(attribute.Int("request.grade", pubReq.Grade))
+	for _, criteriaName := range summaryDiff.ResultOrder {
+		criteriaDiff := summaryDiff.ResultExplanation[criteriaName]
 		pubReq.Description += criteria.DiffToString(criteriaName, criteriaDiff.Explanation, criteriaDiff.Diff)
 	}
 }
@@ -1,18 +1,23 @@
 package publish
 
 import (
+	"context"
 	"errors"
 	"log/slog"
 	"testing"
 
 	cdtime "github.com/rauzh/cd-core/time"
 
+	"github.com/rauzh/cd-core/genre"
 	"github.com/rauzh/cd-core/models"
 
 	rlsService "github.com/rauzh/cd-core/release/service"
 	"github.com/rauzh/cd-core/repo/mocks"
 	"github.com/rauzh/cd-core/requests/base"
+	"github.com/rauzh/cd-core/requests/broker/events"
 	broker_mocks "github.com/rauzh/cd-core/requests/broker/mocks"
+	"github.com/rauzh/cd-core/requests/broker/outbox"
+	outboxRepoMocks "github.com/rauzh/cd-core/requests/broker/outbox/repo/mocks"
 	criteria "github.com/rauzh/cd-core/requests/criteria_controller"
 	publish_criteria "github.com/rauzh/cd-core/requests/criteria_controller/publish"
 	"github.com/rauzh/cd-core/requests/publish"
@@ -34,8 +39,10 @@ type _depFields struct {
 	releaseRepo     *mocks.ReleaseRepo
 	artistRepo      *mocks.ArtistRepo
 
-	pbBroker  *broker_mocks.IBroker
-	criterias criteria.ICriteriaCollection
+	transactor *transacMock.Transactor
+	outboxRepo *outboxRepoMocks.OutboxRepo
+	pbBroker   *broker_mocks.IBroker
+	criterias  criteria.ICriteriaCollection
 
 	publishRepo *publishReqRepoMocks.PublishRequestRepo
 }
@@ -51,9 +58,10 @@ func _newMockPublishReqDepFields(t *testing.T) *_depFields {
 
 	statMockFetcher := statFetcher.NewStatFetcher(t)
 
-	trkSvc := trackService.NewTrackService(trkMockRepo, slog.Default())
-	rlsSvc := rlsService.NewReleaseService(trkSvc, transactionMock, rlsMockRepo, slog.Default())
-	statSvc := statService.NewStatisticsService(trkSvc, statMockFetcher, statMockRepo, rlsSvc, slog.Default())
+	taxonomy, _ := genre.DefaultTaxonomy()
+	trkSvc := trackService.NewTrackService(trkMockRepo, taxonomy, slog.Default())
+	rlsSvc := rlsService.NewReleaseService(trkSvc, transactionMock, rlsMockRepo, taxonomy, slog.Default())
+	statSvc := statService.NewStatisticsService(trkSvc, statMockFetcher, statMockRepo, rlsSvc, taxonomy, slog.Default())
 
 	critCollection, _ := criteria.BuildCollection(
 		&publish_criteria.ArtistReleaseLimitPerSeasonCriteriaFabric{PublicationRepo: pbcMockRepo, ArtistRepo: mockArtRepo},
@@ -69,6 +77,8 @@ func _newMockPublishReqDepFields(t *testing.T) *_depFields {
 		publicationRepo: pbcMockRepo,
 		releaseRepo:     rlsMockRepo,
 		artistRepo:      mockArtRepo,
+		transactor:      transactionMock,
+		outboxRepo:      outboxRepoMocks.NewOutboxRepo(t),
 		pbBroker:        mockBroker,
 		criterias:       critCollection,
 		publishRepo:     publishMockRepo,
@@ -127,7 +137,7 @@ func TestPublishRequestUseCase_proceedToManager(t *testing.T) {
 				}).Return(nil).Once()
 
 				df.artistRepo.EXPECT().GetByUserID(mock.AnythingOfType("context.backgroundCtx"), uint64(12)).Return(
-					&models.Artist{ManagerID: 9, ArtistID: 199}, nil).Once()
+					&models.Artist{ManagerID: 9, ArtistID: 199}, nil).Twice()
 
 				df.publicationRepo.EXPECT().GetAllByArtistSinceDate(mock.AnythingOfType("context.backgroundCtx"),
 					cdtime.RelevantPeriod(), uint64(199)).Return([]models.Publication{{}, {}, {}, {}}, nil).Once()
@@ -147,9 +157,6 @@ func TestPublishRequestUseCase_proceedToManager(t *testing.T) {
 				df._statRepo.EXPECT().GetAllGroupByTracksSince(mock.AnythingOfType("context.backgroundCtx"),
 					cdtime.RelevantPeriod()).Return(nil, pubReqErrors.ErrInvalidDate).Once()
 
-				df.artistRepo.EXPECT().Get(mock.AnythingOfType("context.backgroundCtx"), uint64(12)).Return(
-					&models.Artist{ManagerID: 9}, nil).Once()
-
 				df.publishRepo.EXPECT().Update(mock.AnythingOfType("context.backgroundCtx"), &publish.PublishRequest{
 					Request: base.Request{
 						RequestID: 1,
@@ -164,6 +171,15 @@ func TestPublishRequestUseCase_proceedToManager(t *testing.T) {
 					ExpectedDate: cdtime.GetToday().AddDate(1, 0, 0),
 					Description:  "**No releases from artist more than limit** diff: -1\n**No releases from artist more than limit** reason: More than limit releases per season**Genre should be relevant** diff: 0\n**Genre should be relevant** reason: Can't apply criteria**No releases that day** diff: 0\n**No releases that day** reason: OK",
 				}).Return(nil).Once()
+
+				df.transactor.EXPECT().WithinTransaction(mock.AnythingOfType("context.backgroundCtx"), mock.Anything).RunAndReturn(
+					func(ctx context.Context, fn func(context.Context) error) error {
+						return fn(ctx)
+					}).Once()
+
+				df.outboxRepo.EXPECT().Create(mock.AnythingOfType("context.backgroundCtx"), mock.MatchedBy(func(msg *outbox.OutboxMessage) bool {
+					return msg.Topic == events.RequestRoutedToManagerTopic
+				})).Return(nil).Once()
 			},
 			assert: func(t *testing.T, df *_depFields) {
 
@@ -179,10 +195,11 @@ func TestPublishRequestUseCase_proceedToManager(t *testing.T) {
 				tt.dependencies(f)
 			}
 
-			publishReqHandler := InitPublishProceedToManagerConsumerHandler(f.pbBroker, f.publishRepo, f.artistRepo, f.criterias, slog.Default())
+			publishReqHandler, _ := InitPublishProceedToManagerConsumerHandler(
+				f.pbBroker, f.publishRepo, f.artistRepo, f.criterias, f.transactor, f.outboxRepo, nil, slog.Default())
 
 			// act
-			err := publishReqHandler.(*PublishProceedToManagerConsumerHandler).proceedToManager(tt.in.pubReq)
+			err := publishReqHandler.(*PublishProceedToManagerConsumerHandler).proceedToManager(context.Background(), tt.in.pubReq)
 
 			// assert
 			if !errors.Is(err, tt.out) {
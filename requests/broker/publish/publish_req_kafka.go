@@ -2,20 +2,19 @@ package publish
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"log/slog"
+	"time"
 
-	cdtime "github.com/rauzh/cd-core/time"
-
-	"github.com/IBM/sarama"
 	"github.com/rauzh/cd-core/repo"
 	"github.com/rauzh/cd-core/requests/base"
 	"github.com/rauzh/cd-core/requests/broker"
 	"github.com/rauzh/cd-core/requests/broker/broker_dto"
+	"github.com/rauzh/cd-core/requests/broker/events"
+	outboxRepo "github.com/rauzh/cd-core/requests/broker/outbox/repo"
 	criteria "github.com/rauzh/cd-core/requests/criteria_controller"
 	"github.com/rauzh/cd-core/requests/publish"
 	publishReqRepo "github.com/rauzh/cd-core/requests/publish/repo"
+	"github.com/rauzh/cd-core/transactor"
 )
 
 const (
@@ -24,137 +23,97 @@ const (
 )
 
 type PublishProceedToManagerConsumerHandler struct {
-	broker broker.IBroker
+	*broker.Consumer[broker_dto.PublishReqMessage]
 
 	publishRepo publishReqRepo.PublishRequestRepo
 	artistRepo  repo.ArtistRepo
 
 	criterias criteria.ICriteriaCollection
 
-	ready chan bool
+	// events are stored in outbox in the transaction of the request update
+	transactor     transactor.Transactor
+	eventPublisher events.IEventPublisher
 
 	logger *slog.Logger
 }
 
 func InitPublishProceedToManagerConsumerHandler(
-	broker broker.IBroker,
+	pbBroker broker.IBroker,
 	publishRepo publishReqRepo.PublishRequestRepo,
 	artistRepo repo.ArtistRepo,
 	criterias criteria.ICriteriaCollection,
+	transactor transactor.Transactor,
+	outboxRepo outboxRepo.OutboxRepo,
+	dedup broker.IDeduplicator,
 	logger *slog.Logger,
-) broker.IConsumerGroupHandler {
-	return &PublishProceedToManagerConsumerHandler{
-		broker:      broker,
-		publishRepo: publishRepo,
-		artistRepo:  artistRepo,
-		criterias:   criterias,
-		ready:       make(chan bool),
-		logger:      logger,
+) (broker.IConsumerGroupHandler, error) {
+
+	handler := &PublishProceedToManagerConsumerHandler{
+		publishRepo:    publishRepo,
+		artistRepo:     artistRepo,
+		criterias:      criterias,
+		transactor:     transactor,
+		eventPublisher: events.NewOutboxPublisher(outboxRepo),
+		logger:         logger,
 	}
-}
-
-func (handler *PublishProceedToManagerConsumerHandler) Ready() {
-	handler.ready = make(chan bool)
-	handler.ready <- true
-}
-
-func (handler *PublishProceedToManagerConsumerHandler) WaitReady() {
-	<-handler.ready
-}
 
-func (handler *PublishProceedToManagerConsumerHandler) Setup(session sarama.ConsumerGroupSession) error {
-	close(handler.ready)
-	return nil
-}
-
-func (handler *PublishProceedToManagerConsumerHandler) Cleanup(session sarama.ConsumerGroupSession) error {
-	return nil
-}
-
-func (handler *PublishProceedToManagerConsumerHandler) ConsumeClaim(
-	session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
-
-	for {
-		select {
-		case message := <-claim.Messages():
-
-			if message.Topic == PublishRequestProceedToManager {
-				err := handler.processProceedToManagerMsg(message)
-				if err != nil {
-					// don't mark message as consumed and return
-				}
-			}
-
-			session.MarkMessage(message, "")
-
-		// Should return when `session.Context()` is done.
-		// If not, will raise `ErrRebalanceInProgress` or `read tcp <ip>:<port>: i/o timeout` when kafka rebalance. see:
-		// https://github.com/IBM/sarama/issues/1192
-		case <-session.Context().Done():
-			return nil
-		}
+	consumer, err := broker.NewConsumer(broker.ConsumerConfig[broker_dto.PublishReqMessage]{
+		Topics:    []string{PublishRequestProceedToManager},
+		Decode:    broker_dto.DecodePublishReqMessage,
+		Handle:    handler.processProceedToManagerMsg,
+		OnTimeout: handler.closeTimedOutReq,
+		Dedup:     dedup,
+		RequestID: func(msg *broker_dto.PublishReqMessage) uint64 { return msg.RequestID },
+		Retry:     broker.NewBoundedRetry(pbBroker),
+	}, logger)
+	if err != nil {
+		return nil, err
 	}
-}
+	handler.Consumer = consumer
 
-func (handler *PublishProceedToManagerConsumerHandler) processProceedToManagerMsg(msg *sarama.ConsumerMessage) error {
-	var err error
+	return handler, nil
+}
 
-	pubReqMessage := broker_dto.PublishReqMessage{}
-	if err := json.Unmarshal(msg.Value, &pubReqMessage); err != nil {
-		return err
-	}
+func (handler *PublishProceedToManagerConsumerHandler) processProceedToManagerMsg(ctx context.Context, msg *broker_dto.PublishReqMessage) error {
 
-	fmt.Println("processing pubreq message\n", pubReqMessage)
+	pubReq := msg.ToPublishReq()
 
-	pubReq := pubReqMessage.ToPublishReq()
+	handler.logger.DebugContext(ctx, "PUBLISH_HANDLER processing pubreq message", "req", pubReq.RequestID)
 
 	if err := pubReq.Validate(publish.PubReq); err != nil {
-		return handler.closeProceedToManagerReq(pubReq, err.Error())
-	}
-
-	if msg.Timestamp.Before(cdtime.RelevantPeriod()) {
-		return handler.closeProceedToManagerReq(pubReq, RequestTimeOutExplanation)
+		return handler.closeProceedToManagerReq(ctx, pubReq, err.Error())
 	}
 
-	if err := handler.proceedToManager(pubReq); err != nil {
-
-		fmt.Println("ERROR", err)
-
-		retryProducerMsg := &sarama.ProducerMessage{
-			Topic:     PublishRequestProceedToManager,
-			Value:     sarama.StringEncoder(msg.Value),
-			Timestamp: msg.Timestamp, // setting OLD timestamp (first one) for TIMEOUT mechanism
-		}
-
-		_, _, err = handler.broker.SendMessage(retryProducerMsg)
-	}
-
-	return err
+	return handler.proceedToManager(ctx, pubReq)
 }
 
-func (handler *PublishProceedToManagerConsumerHandler) sendProceedToManagerMSG(pubReq *publish.PublishRequest) error {
-
-	msg, err := broker_dto.NewPublishRequestProducerMsg(PublishRequestProceedToManager, pubReq)
-	if err != nil {
-		return fmt.Errorf("can't apply publish request: gen msg: can't proceed to manager with err %w", err)
-	}
-
-	_, _, err = handler.broker.SendMessage(msg)
-	if err != nil {
-		return fmt.Errorf("can't apply publish request: send msg: can't proceed to manager with err %w", err)
-	}
-
-	return nil
+func (handler *PublishProceedToManagerConsumerHandler) closeTimedOutReq(ctx context.Context, msg *broker_dto.PublishReqMessage) error {
+	return handler.closeProceedToManagerReq(ctx, msg.ToPublishReq(), RequestTimeOutExplanation)
 }
 
 func (handler *PublishProceedToManagerConsumerHandler) closeProceedToManagerReq(
-	pubReq *publish.PublishRequest, explanation string) error {
+	ctx context.Context, pubReq *publish.PublishRequest, explanation string) error {
 
 	pubReq.Description = base.DescrDeclinedRequest + ".\n" + explanation
 	pubReq.Status = base.ClosedRequest
 
-	if err := handler.publishRepo.Update(context.Background(), pubReq); err != nil {
-		return handler.sendProceedToManagerMSG(pubReq) // if db can't update, resend msg
+	err := handler.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := handler.publishRepo.Update(ctx, pubReq); err != nil {
+			return err
+		}
+
+		return handler.eventPublisher.Publish(ctx, &events.RequestDeclined{
+			RequestID:  pubReq.RequestID,
+			Type:       pubReq.Type,
+			ManagerID:  pubReq.ManagerID,
+			Reason:     explanation,
+			TimedOut:   explanation == RequestTimeOutExplanation,
+			OccurredAt: time.Now().UTC(),
+		})
+	})
+	if err != nil {
+		handler.logger.ErrorContext(ctx, "PUBLISH_HANDLER closeProceedToManagerReq", "req", pubReq.RequestID, slog.Any("error", err))
+		return err
 	}
 
 	return nil
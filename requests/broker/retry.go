@@ -0,0 +1,166 @@
+package broker
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// Failed messages of topic "T" go through delay topics "T_retry_1" ... "T_retry_N"
+// with exponentially growing delays and after N retries end up in "T_dlt"
+// with the failure reason in headers.
+
+type RetryPolicy interface {
+	// Retry schedules failed message again or returns the error
+	Retry(msg *sarama.ConsumerMessage, err error) error
+	// DeadLetter gives up on message that can't be processed at all
+	DeadLetter(msg *sarama.ConsumerMessage, err error) error
+}
+
+type NoRetry struct{}
+
+func (NoRetry) Retry(msg *sarama.ConsumerMessage, err error) error {
+	return err
+}
+
+func (NoRetry) DeadLetter(msg *sarama.ConsumerMessage, err error) error {
+	return err
+}
+
+const (
+	DefaultMaxRetries = 5
+	DefaultBaseDelay  = 30 * time.Second
+	DefaultMaxDelay   = time.Hour
+)
+
+type BoundedRetry struct {
+	Broker IBroker
+
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+
+	now func() time.Time
+}
+
+func NewBoundedRetry(broker IBroker) *BoundedRetry {
+	return &BoundedRetry{
+		Broker:     broker,
+		MaxRetries: DefaultMaxRetries,
+		BaseDelay:  DefaultBaseDelay,
+		MaxDelay:   DefaultMaxDelay,
+		now:        time.Now,
+	}
+}
+
+func DelayTopic(topic string, attempt int) string {
+	return fmt.Sprintf("%s_retry_%d", topic, attempt)
+}
+
+func DelayTopics(topic string, maxRetries int) []string {
+	topics := make([]string, 0, maxRetries)
+	for attempt := 1; attempt <= maxRetries; attempt++ {
+		topics = append(topics, DelayTopic(topic, attempt))
+	}
+	return topics
+}
+
+func DeadLetterTopic(topic string) string {
+	return topic + "_dlt"
+}
+
+// Delay returns backoff before the attempt: BaseDelay, 2*BaseDelay, 4*BaseDelay ... up to MaxDelay
+func (retry *BoundedRetry) Delay(attempt int) time.Duration {
+	return backoff(retry.BaseDelay, retry.MaxDelay, attempt)
+}
+
+func (retry *BoundedRetry) Retry(msg *sarama.ConsumerMessage, err error) error {
+
+	attempt := RetryCount(msg) + 1
+	if attempt > retry.MaxRetries {
+		return retry.DeadLetter(msg, err)
+	}
+
+	topic := OriginalTopic(msg)
+	notBefore := retry.now().Add(retry.Delay(attempt))
+
+	retryProducerMsg := &sarama.ProducerMessage{
+		Topic:     DelayTopic(topic, attempt),
+		Key:       keyEncoder(msg.Key),
+		Value:     sarama.ByteEncoder(msg.Value),
+		Timestamp: msg.Timestamp, // setting OLD timestamp (first one) for TIMEOUT mechanism
+		Headers: WithHeaders(msg.Headers, map[string]string{
+			HeaderRetryCount:    strconv.Itoa(attempt),
+			HeaderOriginalTopic: topic,
+			HeaderNotBefore:     strconv.FormatInt(notBefore.UnixMilli(), 10),
+			HeaderFailureReason: err.Error(),
+		}),
+	}
+
+	if _, _, err := retry.Broker.SendMessage(retryProducerMsg); err != nil {
+		return fmt.Errorf("%w to %s with err %w", ErrForwardFailed, retryProducerMsg.Topic, err)
+	}
+
+	return nil
+}
+
+func (retry *BoundedRetry) DeadLetter(msg *sarama.ConsumerMessage, err error) error {
+
+	topic := OriginalTopic(msg)
+
+	deadLetterMsg := &sarama.ProducerMessage{
+		Topic:     DeadLetterTopic(topic),
+		Key:       keyEncoder(msg.Key),
+		Value:     sarama.ByteEncoder(msg.Value),
+		Timestamp: msg.Timestamp,
+		Headers: WithHeaders(msg.Headers, map[string]string{
+			HeaderRetryCount:    strconv.Itoa(RetryCount(msg)),
+			HeaderOriginalTopic: topic,
+			HeaderFailureReason: err.Error(),
+			HeaderFailedAt:      retry.now().UTC().Format(time.RFC3339),
+		}),
+	}
+
+	if _, _, err := retry.Broker.SendMessage(deadLetterMsg); err != nil {
+		return fmt.Errorf("%w to %s with err %w", ErrForwardFailed, deadLetterMsg.Topic, err)
+	}
+
+	return nil
+}
+
+// BlockingBackoff retries failed message in place until it is handled,
+// for consumers that have no topic to move failed message to,
+// e.g. delay forwarder and dead letter store
+type BlockingBackoff struct {
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+func DefaultBlockingBackoff() *BlockingBackoff {
+	return &BlockingBackoff{BaseDelay: time.Second, MaxDelay: time.Minute}
+}
+
+// Delay returns backoff before the attempt the same way BoundedRetry does
+func (blocking *BlockingBackoff) Delay(attempt int) time.Duration {
+	return backoff(blocking.BaseDelay, blocking.MaxDelay, attempt)
+}
+
+func backoff(base, max time.Duration, attempt int) time.Duration {
+	delay := base
+	for i := 1; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+	return delay
+}
+
+func keyEncoder(key []byte) sarama.Encoder {
+	if key == nil {
+		return nil
+	}
+	return sarama.ByteEncoder(key)
+}
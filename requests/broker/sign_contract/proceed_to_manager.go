@@ -3,30 +3,64 @@ package sign_contract
 import (
 	"context"
 	"log/slog"
+	"time"
 
 	"github.com/rauzh/cd-core/requests/base"
+	"github.com/rauzh/cd-core/requests/broker/events"
+	"github.com/rauzh/cd-core/requests/broker/tracing"
+	criteria "github.com/rauzh/cd-core/requests/criteria_controller"
 	"github.com/rauzh/cd-core/requests/sign_contract"
 	"github.com/rauzh/cd-core/requests/sign_contract/errors"
+	"go.opentelemetry.io/otel/attribute"
 )
 
-func (handler *SignContractProceedToManagerHandler) proceedToManager(signReq *sign_contract.SignContractRequest) error {
+func (handler *SignContractProceedToManagerHandler) proceedToManager(ctx context.Context, signReq *sign_contract.SignContractRequest) error {
 	signReq.Status = base.OnApprovalRequest
 
-	ctx := context.Background()
-
 	managerID, err := handler.mngRepo.GetRandManagerID(ctx)
 	if err != nil {
-		handler.logger.Error("SIGN_HANDLER proceedToManager", slog.Any("error", err))
+		handler.logger.ErrorContext(ctx, "SIGN_HANDLER proceedToManager", slog.Any("error", err))
 		return errors.ErrCantFindManager
 	}
 
 	signReq.ManagerID = managerID
 
-	err = handler.signReqRepo.Update(ctx, signReq)
+	handler.computeDegree(ctx, signReq)
+
+	err = handler.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := handler.signReqRepo.Update(ctx, signReq); err != nil {
+			return err
+		}
+
+		return handler.eventPublisher.Publish(ctx, &events.RequestRoutedToManager{
+			RequestID:  signReq.RequestID,
+			Type:       signReq.Type,
+			ManagerID:  signReq.ManagerID,
+			Grade:      signReq.Grade,
+			OccurredAt: time.Now().UTC(),
+		})
+	})
 	if err != nil {
-		handler.logger.Error("SIGN_HANDLER proceedToManager", slog.Any("error", err))
+		handler.logger.ErrorContext(ctx, "SIGN_HANDLER proceedToManager", slog.Any("error", err))
 		return err
 	}
-	handler.logger.Info("SIGN_HANDLER proceedToManager", "signreq_manager", signReq.ManagerID)
+	handler.logger.InfoContext(ctx, "SIGN_HANDLER proceedToManager", "signreq_manager", signReq.ManagerID)
+
 	return nil
 }
+
+func (handler *SignContractProceedToManagerHandler) computeDegree(ctx context.Context, signReq *sign_contract.SignContractRequest) {
+
+	_, span := tracing.Start(ctx, "criteria.Apply", attribute.Int64("request.id", int64(signReq.RequestID)))
+	defer span.End()
+
+	summaryDiff := handler.criterias.Apply(signReq)
+
+	signReq.Grade = summaryDiff.ResultDiff
+	span.SetAttributes(attribute.Int("request.grade", signReq.Grade))
+
+	for _, criteriaName := range summaryDiff.ResultOrder {
+		criteriaDiff := summaryDiff.ResultExplanation[criteriaName]
+		signReq.Description += criteria.DiffToString(criteriaName, criteriaDiff.Explanation, criteriaDiff.Diff)
+	}
+}
@@ -1,6 +1,7 @@
 package sign_contract
 
 import (
+	"context"
 	"errors"
 	"log/slog"
 	"testing"
@@ -9,7 +10,12 @@ import (
 
 	"github.com/rauzh/cd-core/repo/mocks"
 	"github.com/rauzh/cd-core/requests/base"
+	"github.com/rauzh/cd-core/requests/broker/events"
 	broker_mocks "github.com/rauzh/cd-core/requests/broker/mocks"
+	"github.com/rauzh/cd-core/requests/broker/outbox"
+	outboxRepoMocks "github.com/rauzh/cd-core/requests/broker/outbox/repo/mocks"
+	criteria "github.com/rauzh/cd-core/requests/criteria_controller"
+	sign_contract_criteria "github.com/rauzh/cd-core/requests/criteria_controller/sign_contract"
 	"github.com/rauzh/cd-core/requests/sign_contract"
 	sctErrors "github.com/rauzh/cd-core/requests/sign_contract/errors"
 	signReqRepoMocks "github.com/rauzh/cd-core/requests/sign_contract/repo/mocks"
@@ -23,9 +29,12 @@ type _depFields struct {
 	userRepo    *mocks.UserRepo
 
 	transactor *transacMock.Transactor
+	outboxRepo *outboxRepoMocks.OutboxRepo
 	scBroker   *broker_mocks.IBroker
 
 	signReqRepo *signReqRepoMocks.SignContractRequestRepo
+
+	criterias criteria.ICriteriaCollection
 }
 
 var dberr = errors.New("db err")
@@ -41,13 +50,18 @@ func _newMockSignReqDepFields(t *testing.T) *_depFields {
 
 	mockSignReqRepo := signReqRepoMocks.NewSignContractRequestRepo(t)
 
+	critCollection, _ := criteria.BuildCollection(
+		&sign_contract_criteria.NoDuplicateOpenRequestsCriteriaFabric{SignReqRepo: mockSignReqRepo})
+
 	f := &_depFields{
 		artistRepo:  mockArtRepo,
 		managerRepo: mockManagerRepo,
 		userRepo:    mockUserRepo,
 		transactor:  transactionMock,
+		outboxRepo:  outboxRepoMocks.NewOutboxRepo(t),
 		scBroker:    mockBroker,
 		signReqRepo: mockSignReqRepo,
+		criterias:   critCollection,
 	}
 
 	return f
@@ -89,6 +103,9 @@ func TestPublishRequestUseCase_proceedToManager(t *testing.T) {
 				df.managerRepo.EXPECT().GetRandManagerID(mock.AnythingOfType("context.backgroundCtx")).Return(
 					uint64(9), nil).Once()
 
+				df.signReqRepo.EXPECT().GetAllByApplierID(mock.AnythingOfType("context.backgroundCtx"), uint64(12)).Return(
+					[]sign_contract.SignContractRequest{{Request: base.Request{RequestID: 1}}}, nil).Once()
+
 				df.signReqRepo.EXPECT().Update(mock.AnythingOfType("context.backgroundCtx"), &sign_contract.SignContractRequest{
 					Request: base.Request{
 						RequestID: 1,
@@ -99,14 +116,77 @@ func TestPublishRequestUseCase_proceedToManager(t *testing.T) {
 						ManagerID: 9,
 					},
 					Nickname:    "skibidi",
-					Description: "",
+					Grade:       0,
+					Description: "**No other open sign requests** diff: 0\n**No other open sign requests** reason: OK",
 				}).Return(nil).Once()
 
+				df.transactor.EXPECT().WithinTransaction(mock.AnythingOfType("context.backgroundCtx"), mock.Anything).RunAndReturn(
+					func(ctx context.Context, fn func(context.Context) error) error {
+						return fn(ctx)
+					}).Once()
+
+				df.outboxRepo.EXPECT().Create(mock.AnythingOfType("context.backgroundCtx"), mock.MatchedBy(func(msg *outbox.OutboxMessage) bool {
+					return msg.Topic == events.RequestRoutedToManagerTopic
+				})).Return(nil).Once()
+
 			},
 			assert: func(t *testing.T, df *_depFields) {
 
 			},
 		},
+		{
+			name: "DuplicateOpen",
+			in: &args{
+				signReq: &sign_contract.SignContractRequest{
+					Request: base.Request{
+						RequestID: 2,
+						Type:      sign_contract.SignRequest,
+						Status:    base.NewRequest,
+						Date:      cdtime.GetToday(),
+						ApplierID: 12,
+						ManagerID: 0,
+					},
+					Nickname:    "skibidi",
+					Description: "",
+				},
+			},
+			out: nil,
+			dependencies: func(df *_depFields) {
+				df.managerRepo.EXPECT().GetRandManagerID(mock.AnythingOfType("context.backgroundCtx")).Return(
+					uint64(9), nil).Once()
+
+				df.signReqRepo.EXPECT().GetAllByApplierID(mock.AnythingOfType("context.backgroundCtx"), uint64(12)).Return(
+					[]sign_contract.SignContractRequest{
+						{Request: base.Request{RequestID: 1, Status: base.OnApprovalRequest}},
+						{Request: base.Request{RequestID: 2, Status: base.NewRequest}},
+					}, nil).Once()
+
+				df.signReqRepo.EXPECT().Update(mock.AnythingOfType("context.backgroundCtx"), &sign_contract.SignContractRequest{
+					Request: base.Request{
+						RequestID: 2,
+						Type:      sign_contract.SignRequest,
+						Status:    base.OnApprovalRequest,
+						Date:      cdtime.GetToday(),
+						ApplierID: 12,
+						ManagerID: 9,
+					},
+					Nickname:    "skibidi",
+					Grade:       -1,
+					Description: "**No other open sign requests** diff: -1\n**No other open sign requests** reason: Applier has another open sign request",
+				}).Return(nil).Once()
+
+				df.transactor.EXPECT().WithinTransaction(mock.AnythingOfType("context.backgroundCtx"), mock.Anything).RunAndReturn(
+					func(ctx context.Context, fn func(context.Context) error) error {
+						return fn(ctx)
+					}).Once()
+
+				df.outboxRepo.EXPECT().Create(mock.AnythingOfType("context.backgroundCtx"), mock.MatchedBy(func(msg *outbox.OutboxMessage) bool {
+					return msg.Topic == events.RequestRoutedToManagerTopic
+				})).Return(nil).Once()
+			},
+			assert: func(t *testing.T, df *_depFields) {
+			},
+		},
 		{
 			name: "NoManager",
 			in: &args{
@@ -144,10 +224,11 @@ func TestPublishRequestUseCase_proceedToManager(t *testing.T) {
 				tt.dependencies(f)
 			}
 
-			signReqHandler := InitSignContractProceedToManagerHandler(f.scBroker, f.signReqRepo, f.managerRepo, slog.Default())
+			signReqHandler, _ := InitSignContractProceedToManagerHandler(
+				f.scBroker, f.signReqRepo, f.managerRepo, f.criterias, f.transactor, f.outboxRepo, nil, slog.Default())
 
 			// act
-			err := signReqHandler.(*SignContractProceedToManagerHandler).proceedToManager(tt.in.signReq)
+			err := signReqHandler.(*SignContractProceedToManagerHandler).proceedToManager(context.Background(), tt.in.signReq)
 
 			// assert
 			if !errors.Is(err, tt.out) {
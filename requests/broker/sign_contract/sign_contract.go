@@ -2,19 +2,19 @@ package sign_contract
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"log/slog"
+	"time"
 
-	cdtime "github.com/rauzh/cd-core/time"
-
-	"github.com/IBM/sarama"
 	"github.com/rauzh/cd-core/repo"
 	"github.com/rauzh/cd-core/requests/base"
 	"github.com/rauzh/cd-core/requests/broker"
 	"github.com/rauzh/cd-core/requests/broker/broker_dto"
+	"github.com/rauzh/cd-core/requests/broker/events"
+	outboxRepo "github.com/rauzh/cd-core/requests/broker/outbox/repo"
+	criteria "github.com/rauzh/cd-core/requests/criteria_controller"
 	"github.com/rauzh/cd-core/requests/sign_contract"
 	signRepo "github.com/rauzh/cd-core/requests/sign_contract/repo"
+	"github.com/rauzh/cd-core/transactor"
 )
 
 const (
@@ -23,137 +23,95 @@ const (
 )
 
 type SignContractProceedToManagerHandler struct {
-	broker broker.IBroker
+	*broker.Consumer[broker_dto.SignContractReqMessage]
 
 	signReqRepo signRepo.SignContractRequestRepo
 	mngRepo     repo.ManagerRepo
 
-	ready chan bool
+	criterias criteria.ICriteriaCollection
+
+	// events are stored in outbox in the transaction of the request update
+	transactor     transactor.Transactor
+	eventPublisher events.IEventPublisher
 
 	logger *slog.Logger
 }
 
 func InitSignContractProceedToManagerHandler(
-	broker broker.IBroker,
+	scBroker broker.IBroker,
 	signReqRepo signRepo.SignContractRequestRepo,
 	mngRepo repo.ManagerRepo,
+	criterias criteria.ICriteriaCollection,
+	transactor transactor.Transactor,
+	outboxRepo outboxRepo.OutboxRepo,
+	dedup broker.IDeduplicator,
 	logger *slog.Logger,
-) broker.IConsumerGroupHandler {
-	return &SignContractProceedToManagerHandler{
-		broker:      broker,
-		signReqRepo: signReqRepo,
-		mngRepo:     mngRepo,
-		ready:       make(chan bool),
-		logger:      logger,
+) (broker.IConsumerGroupHandler, error) {
+
+	handler := &SignContractProceedToManagerHandler{
+		signReqRepo:    signReqRepo,
+		mngRepo:        mngRepo,
+		criterias:      criterias,
+		transactor:     transactor,
+		eventPublisher: events.NewOutboxPublisher(outboxRepo),
+		logger:         logger,
 	}
-}
-
-func (handler *SignContractProceedToManagerHandler) Ready() {
-	handler.ready = make(chan bool)
-	handler.ready <- true
-}
-
-func (handler *SignContractProceedToManagerHandler) WaitReady() {
-	<-handler.ready
-}
-
-func (handler *SignContractProceedToManagerHandler) Setup(session sarama.ConsumerGroupSession) error {
-	close(handler.ready)
-	return nil
-}
 
-func (handler *SignContractProceedToManagerHandler) Cleanup(session sarama.ConsumerGroupSession) error {
-	return nil
-}
-
-func (handler *SignContractProceedToManagerHandler) ConsumeClaim(
-	session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
-
-	for {
-		select {
-		case message := <-claim.Messages():
-
-			if message.Topic == SignRequestProceedToManager {
-				err := handler.processProceedToManagerMsg(message)
-				if err != nil {
-					// don't mark message as consumed and return
-				}
-			}
-
-			session.MarkMessage(message, "")
-
-		// Should return when `session.Context()` is done.
-		// If not, will raise `ErrRebalanceInProgress` or `read tcp <ip>:<port>: i/o timeout` when kafka rebalance. see:
-		// https://github.com/IBM/sarama/issues/1192
-		case <-session.Context().Done():
-			return nil
-		}
+	consumer, err := broker.NewConsumer(broker.ConsumerConfig[broker_dto.SignContractReqMessage]{
+		Topics:    []string{SignRequestProceedToManager},
+		Decode:    broker_dto.DecodeSignContractReqMessage,
+		Handle:    handler.processProceedToManagerMsg,
+		OnTimeout: handler.closeTimedOutReq,
+		Dedup:     dedup,
+		RequestID: func(msg *broker_dto.SignContractReqMessage) uint64 { return msg.RequestID },
+		Retry:     broker.NewBoundedRetry(scBroker),
+	}, logger)
+	if err != nil {
+		return nil, err
 	}
-}
-
-func (handler *SignContractProceedToManagerHandler) processProceedToManagerMsg(msg *sarama.ConsumerMessage) error {
-	var err error
+	handler.Consumer = consumer
 
-	signContractReqMsg := broker_dto.SignContractReqMessage{}
-	if err := json.Unmarshal(msg.Value, &signContractReqMsg); err != nil {
-		return err
-	}
+	return handler, nil
+}
 
-	signReq := signContractReqMsg.ToSignContractReq()
+func (handler *SignContractProceedToManagerHandler) processProceedToManagerMsg(ctx context.Context, msg *broker_dto.SignContractReqMessage) error {
 
-	//fmt.Println("!!!!", signReq.Nickname)
+	signReq := msg.ToSignContractReq()
 
 	if err := signReq.Validate(sign_contract.SignRequest); err != nil {
-		return handler.closeProceedToManagerReq(signReq, err.Error())
-	}
-
-	//fmt.Println("VALIDATED !!! ", signReq.Nickname)
-
-	if msg.Timestamp.Before(cdtime.RelevantPeriod()) {
-		return handler.closeProceedToManagerReq(signReq, RequestTimeOutExplanation)
-	}
-
-	//fmt.Println("TIME OK !!! ", signReq.Nickname)
-
-	if err := handler.proceedToManager(signReq); err != nil {
-
-		//fmt.Println("EERR PROCEED !!! ", signReq.Nickname, err)
-
-		retryProducerMsg := &sarama.ProducerMessage{
-			Topic:     SignRequestProceedToManager,
-			Value:     sarama.StringEncoder(msg.Value),
-			Timestamp: msg.Timestamp, // setting OLD timestamp (first one) for TIMEOUT mechanism
-		}
-
-		_, _, err = handler.broker.SendMessage(retryProducerMsg)
+		return handler.closeProceedToManagerReq(ctx, signReq, err.Error())
 	}
 
-	return err
+	return handler.proceedToManager(ctx, signReq)
 }
 
-func (handler *SignContractProceedToManagerHandler) sendProceedToManagerMSG(signReq *sign_contract.SignContractRequest) error {
-
-	msg, err := broker_dto.NewSignRequestProducerMsg(SignRequestProceedToManager, signReq)
-	if err != nil {
-		return fmt.Errorf("can't apply sign contract request: can't proceed to manager with err %w", err)
-	}
-
-	_, _, err = handler.broker.SendMessage(msg)
-	if err != nil {
-		return fmt.Errorf("can't apply sign contract request: can't proceed to manager with err %w", err)
-	}
-
-	return nil
+func (handler *SignContractProceedToManagerHandler) closeTimedOutReq(ctx context.Context, msg *broker_dto.SignContractReqMessage) error {
+	return handler.closeProceedToManagerReq(ctx, msg.ToSignContractReq(), RequestTimeOutExplanation)
 }
 
 func (handler *SignContractProceedToManagerHandler) closeProceedToManagerReq(
-	signReq *sign_contract.SignContractRequest, explanation string) error {
+	ctx context.Context, signReq *sign_contract.SignContractRequest, explanation string) error {
 
 	signReq.Description = base.DescrDeclinedRequest + ".\n" + explanation
 	signReq.Status = base.ClosedRequest
 
-	if err := handler.signReqRepo.Update(context.Background(), signReq); err != nil {
-		return handler.sendProceedToManagerMSG(signReq) // if db can't update, resend msg
+	err := handler.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := handler.signReqRepo.Update(ctx, signReq); err != nil {
+			return err
+		}
+
+		return handler.eventPublisher.Publish(ctx, &events.RequestDeclined{
+			RequestID:  signReq.RequestID,
+			Type:       signReq.Type,
+			ManagerID:  signReq.ManagerID,
+			Reason:     explanation,
+			TimedOut:   explanation == RequestTimeOutExplanation,
+			OccurredAt: time.Now().UTC(),
+		})
+	})
+	if err != nil {
+		handler.logger.ErrorContext(ctx, "SIGN_HANDLER closeProceedToManagerReq", "req", signReq.RequestID, slog.Any("error", err))
+		return err
 	}
 
 	return nil
@@ -0,0 +1,38 @@
+package tracing
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LogHandler adds correlation and trace ids of the context to records,
+// so logs of *Context calls on both sides of broker can be tied together
+type LogHandler struct {
+	slog.Handler
+}
+
+func NewLogHandler(handler slog.Handler) *LogHandler {
+	return &LogHandler{Handler: handler}
+}
+
+func (handler *LogHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := CorrelationID(ctx); id != "" {
+		record.AddAttrs(slog.String("correlation_id", id))
+	}
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.IsValid() {
+		record.AddAttrs(
+			slog.String("trace_id", spanCtx.TraceID().String()),
+			slog.String("span_id", spanCtx.SpanID().String()))
+	}
+	return handler.Handler.Handle(ctx, record)
+}
+
+func (handler *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &LogHandler{Handler: handler.Handler.WithAttrs(attrs)}
+}
+
+func (handler *LogHandler) WithGroup(name string) slog.Handler {
+	return &LogHandler{Handler: handler.Handler.WithGroup(name)}
+}
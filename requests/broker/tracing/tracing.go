@@ -0,0 +1,144 @@
+// Package tracing propagates correlation id and W3C trace context
+// through broker message headers and adds them to logs
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	HeaderCorrelationID = "correlation-id"
+
+	instrumentationName = "github.com/rauzh/cd-core/requests"
+)
+
+// Propagator writes traceparent, tracestate and baggage headers
+var Propagator propagation.TextMapPropagator = propagation.NewCompositeTextMapPropagator(
+	propagation.TraceContext{}, propagation.Baggage{})
+
+type correlationKey struct{}
+
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
+
+// EnsureCorrelationID starts new correlation if ctx has none
+func EnsureCorrelationID(ctx context.Context) context.Context {
+	if CorrelationID(ctx) != "" {
+		return ctx
+	}
+	return WithCorrelationID(ctx, NewID())
+}
+
+func NewID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// Inject writes correlation id and trace context of ctx into message headers
+func Inject(ctx context.Context, msg *sarama.ProducerMessage) {
+	carrier := producerCarrier{msg: msg}
+	if id := CorrelationID(ctx); id != "" {
+		carrier.Set(HeaderCorrelationID, id)
+	}
+	Propagator.Inject(ctx, carrier)
+}
+
+// Extract restores correlation id and remote span context from message headers
+func Extract(ctx context.Context, msg *sarama.ConsumerMessage) context.Context {
+	carrier := consumerCarrier{msg: msg}
+	if id := carrier.Get(HeaderCorrelationID); id != "" {
+		ctx = WithCorrelationID(ctx, id)
+	}
+	return Propagator.Extract(ctx, carrier)
+}
+
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start starts span of the global tracer provider
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err in span and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+type producerCarrier struct {
+	msg *sarama.ProducerMessage
+}
+
+func (carrier producerCarrier) Get(key string) string {
+	for _, header := range carrier.msg.Headers {
+		if string(header.Key) == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+func (carrier producerCarrier) Set(key, value string) {
+	for i, header := range carrier.msg.Headers {
+		if string(header.Key) == key {
+			carrier.msg.Headers[i].Value = []byte(value)
+			return
+		}
+	}
+	carrier.msg.Headers = append(carrier.msg.Headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+}
+
+func (carrier producerCarrier) Keys() []string {
+	keys := make([]string, 0, len(carrier.msg.Headers))
+	for _, header := range carrier.msg.Headers {
+		keys = append(keys, string(header.Key))
+	}
+	return keys
+}
+
+type consumerCarrier struct {
+	msg *sarama.ConsumerMessage
+}
+
+func (carrier consumerCarrier) Get(key string) string {
+	for _, header := range carrier.msg.Headers {
+		if header != nil && string(header.Key) == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+// Set is not used for consumed messages
+func (carrier consumerCarrier) Set(key, value string) {}
+
+func (carrier consumerCarrier) Keys() []string {
+	keys := make([]string, 0, len(carrier.msg.Headers))
+	for _, header := range carrier.msg.Headers {
+		if header != nil {
+			keys = append(keys, string(header.Key))
+		}
+	}
+	return keys
+}
@@ -0,0 +1,86 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func toConsumerMessage(msg *sarama.ProducerMessage) *sarama.ConsumerMessage {
+	consumed := &sarama.ConsumerMessage{Topic: msg.Topic}
+	for i := range msg.Headers {
+		consumed.Headers = append(consumed.Headers, &msg.Headers[i])
+	}
+	return consumed
+}
+
+func TestInjectExtract(t *testing.T) {
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	ctx, span := provider.Tracer("test").Start(WithCorrelationID(context.Background(), "corr"), "produce")
+	defer span.End()
+
+	msg := &sarama.ProducerMessage{Topic: "topic"}
+	Inject(ctx, msg)
+
+	extracted := Extract(context.Background(), toConsumerMessage(msg))
+
+	assert.Equal(t, "corr", CorrelationID(extracted))
+
+	remote := trace.SpanContextFromContext(extracted)
+	assert.True(t, remote.IsRemote())
+	assert.Equal(t, span.SpanContext().TraceID(), remote.TraceID())
+	assert.Equal(t, span.SpanContext().SpanID(), remote.SpanID())
+
+	_, child := provider.Tracer("test").Start(extracted, "consume")
+	child.End()
+
+	ended := recorder.Ended()
+	if assert.Len(t, ended, 1) {
+		assert.Equal(t, span.SpanContext().TraceID(), ended[0].SpanContext().TraceID())
+		assert.Equal(t, span.SpanContext().SpanID(), ended[0].Parent().SpanID())
+	}
+}
+
+func TestExtract_NoHeaders(t *testing.T) {
+
+	extracted := Extract(context.Background(), &sarama.ConsumerMessage{})
+
+	assert.Empty(t, CorrelationID(extracted))
+	assert.False(t, trace.SpanContextFromContext(extracted).IsValid())
+}
+
+func TestEnsureCorrelationID(t *testing.T) {
+
+	ctx := EnsureCorrelationID(context.Background())
+	id := CorrelationID(ctx)
+
+	assert.NotEmpty(t, id)
+	assert.Equal(t, id, CorrelationID(EnsureCorrelationID(ctx)))
+}
+
+func TestLogHandler(t *testing.T) {
+
+	provider := sdktrace.NewTracerProvider()
+	ctx, span := provider.Tracer("test").Start(WithCorrelationID(context.Background(), "corr"), "op")
+	defer span.End()
+
+	var buf bytes.Buffer
+	logger := slog.New(NewLogHandler(slog.NewTextHandler(&buf, nil))).With("component", "test")
+
+	logger.InfoContext(ctx, "msg")
+
+	assert.Contains(t, buf.String(), "correlation_id=corr")
+	assert.Contains(t, buf.String(), "trace_id="+span.SpanContext().TraceID().String())
+	assert.Contains(t, buf.String(), "span_id="+span.SpanContext().SpanID().String())
+	assert.Contains(t, buf.String(), "component=test")
+}
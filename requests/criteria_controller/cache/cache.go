@@ -0,0 +1,187 @@
+package criteria_cache
+
+import (
+	"sync"
+	"time"
+)
+
+// Tag groups cache entries by the data they were computed from,
+// so entries can be invalidated when that data changes
+type Tag string
+
+const (
+	StatisticsTag   Tag = "statistics"
+	PublicationsTag Tag = "publications"
+)
+
+const DefaultTTL = time.Hour
+
+type ICriteriaCache interface {
+	Get(key string) (any, bool)
+	Set(key string, value any, tags ...Tag)
+	// Generation changes every time entries of any of tags are invalidated
+	Generation(tags ...Tag) uint64
+	// SetIfGeneration sets value only if nothing of tags was invalidated since gen was taken
+	SetIfGeneration(key string, value any, gen uint64, tags ...Tag) bool
+	Invalidate(tags ...Tag)
+	InvalidateAll()
+}
+
+type entry struct {
+	value     any
+	expiresAt time.Time
+	tags      []Tag
+}
+
+type TTLCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.RWMutex
+	entries map[string]entry
+
+	// generations are bumped on invalidation, allGeneration on InvalidateAll
+	generations   map[Tag]uint64
+	allGeneration uint64
+
+	// expired entries are evicted on Set at most once per ttl
+	evictedAt time.Time
+}
+
+func NewTTLCache(ttl time.Duration) ICriteriaCache {
+	return &TTLCache{
+		ttl:         ttl,
+		now:         time.Now,
+		entries:     make(map[string]entry),
+		generations: make(map[Tag]uint64),
+	}
+}
+
+func (cache *TTLCache) Get(key string) (any, bool) {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+
+	e, ok := cache.entries[key]
+	if !ok || cache.now().After(e.expiresAt) {
+		return nil, false
+	}
+
+	return e.value, true
+}
+
+func (cache *TTLCache) Set(key string, value any, tags ...Tag) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.set(key, value, tags)
+}
+
+func (cache *TTLCache) Generation(tags ...Tag) uint64 {
+	cache.mu.RLock()
+	defer cache.mu.RUnlock()
+
+	return cache.generation(tags)
+}
+
+func (cache *TTLCache) SetIfGeneration(key string, value any, gen uint64, tags ...Tag) bool {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if cache.generation(tags) != gen {
+		return false
+	}
+
+	cache.set(key, value, tags)
+	return true
+}
+
+func (cache *TTLCache) Invalidate(tags ...Tag) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	for _, tag := range tags {
+		cache.generations[tag]++
+	}
+
+	for key, e := range cache.entries {
+		if hasAnyTag(e.tags, tags) {
+			delete(cache.entries, key)
+		}
+	}
+}
+
+func (cache *TTLCache) InvalidateAll() {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.allGeneration++
+	cache.entries = make(map[string]entry)
+}
+
+func (cache *TTLCache) set(key string, value any, tags []Tag) {
+	now := cache.now()
+	if !now.Before(cache.evictedAt.Add(cache.ttl)) {
+		cache.evictExpired(now)
+	}
+
+	cache.entries[key] = entry{
+		value:     value,
+		expiresAt: now.Add(cache.ttl),
+		tags:      tags,
+	}
+}
+
+func (cache *TTLCache) evictExpired(now time.Time) {
+	for key, e := range cache.entries {
+		if now.After(e.expiresAt) {
+			delete(cache.entries, key)
+		}
+	}
+	cache.evictedAt = now
+}
+
+// generation only grows, so the sum changes whenever any of tags is invalidated
+func (cache *TTLCache) generation(tags []Tag) uint64 {
+	gen := cache.allGeneration
+	for _, tag := range tags {
+		gen += cache.generations[tag]
+	}
+	return gen
+}
+
+func hasAnyTag(entryTags []Tag, tags []Tag) bool {
+	for _, entryTag := range entryTags {
+		for _, tag := range tags {
+			if entryTag == tag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Remember returns cached value by key or computes and caches it.
+// Nil cache means no caching, so criteria can work without it
+func Remember[T any](cache ICriteriaCache, key string, tags []Tag, compute func() (T, error)) (T, error) {
+	if cache == nil {
+		return compute()
+	}
+
+	if cached, ok := cache.Get(key); ok {
+		if value, ok := cached.(T); ok {
+			return value, nil
+		}
+	}
+
+	// invalidation that happens while computing means value may be stale already
+	gen := cache.Generation(tags...)
+
+	value, err := compute()
+	if err != nil {
+		return value, err
+	}
+
+	cache.SetIfGeneration(key, value, gen, tags...)
+
+	return value, nil
+}
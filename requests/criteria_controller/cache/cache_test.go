@@ -0,0 +1,160 @@
+package criteria_cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rauzh/cd-core/models"
+	"github.com/rauzh/cd-core/repo/mocks"
+	"github.com/rauzh/cd-core/transactor"
+	transacMock "github.com/rauzh/cd-core/transactor/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTTLCache_Remember(t *testing.T) {
+
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	cache := NewTTLCache(time.Hour)
+	cache.(*TTLCache).now = func() time.Time { return now }
+
+	calls := 0
+	compute := func() (string, error) {
+		calls++
+		return "rock", nil
+	}
+
+	genre, err := Remember(cache, "relevant_genre", []Tag{StatisticsTag}, compute)
+	assert.Nil(t, err)
+	assert.Equal(t, "rock", genre)
+
+	genre, _ = Remember(cache, "relevant_genre", []Tag{StatisticsTag}, compute)
+	assert.Equal(t, "rock", genre)
+	assert.Equal(t, 1, calls)
+
+	// other tags don't touch entry
+	cache.Invalidate(PublicationsTag)
+	_, _ = Remember(cache, "relevant_genre", []Tag{StatisticsTag}, compute)
+	assert.Equal(t, 1, calls)
+
+	cache.Invalidate(StatisticsTag)
+	_, _ = Remember(cache, "relevant_genre", []Tag{StatisticsTag}, compute)
+	assert.Equal(t, 2, calls)
+
+	now = now.Add(2 * time.Hour)
+	_, _ = Remember(cache, "relevant_genre", []Tag{StatisticsTag}, compute)
+	assert.Equal(t, 3, calls)
+}
+
+func TestTTLCache_RememberError(t *testing.T) {
+
+	cache := NewTTLCache(time.Hour)
+	errCompute := errors.New("db err")
+
+	_, err := Remember(cache, "relevant_genre", nil, func() (string, error) {
+		return "", errCompute
+	})
+	assert.ErrorIs(t, err, errCompute)
+
+	_, ok := cache.Get("relevant_genre")
+	assert.False(t, ok)
+}
+
+func TestTTLCache_RememberInvalidatedWhileComputing(t *testing.T) {
+
+	cache := NewTTLCache(time.Hour)
+
+	genre, err := Remember(cache, "relevant_genre", []Tag{StatisticsTag}, func() (string, error) {
+		// new statistics are stored while the old ones are aggregated
+		cache.Invalidate(StatisticsTag)
+		return "rock", nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "rock", genre)
+
+	_, ok := cache.Get("relevant_genre")
+	assert.False(t, ok)
+}
+
+func TestTTLCache_EvictsExpired(t *testing.T) {
+
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	cache := NewTTLCache(time.Hour)
+	ttlCache := cache.(*TTLCache)
+	ttlCache.now = func() time.Time { return now }
+
+	cache.Set("artist_momentum:1", 1)
+	cache.Set("artist_momentum:2", 2)
+
+	now = now.Add(2 * time.Hour)
+	cache.Set("artist_momentum:3", 3)
+
+	assert.Equal(t, 1, len(ttlCache.entries))
+}
+
+func TestInvalidatingRepos(t *testing.T) {
+
+	statRepo := mocks.NewStatisticsRepo(t)
+	pbcRepo := mocks.NewPublicationRepo(t)
+
+	statRepo.EXPECT().CreateMany(mock.Anything, mock.Anything).Return(nil).Once()
+	pbcRepo.EXPECT().Create(mock.Anything, mock.Anything).Return(nil).Once()
+
+	cache := NewTTLCache(time.Hour)
+	invalidatingStatRepo := NewInvalidatingStatisticsRepo(statRepo, cache)
+	invalidatingPbcRepo := NewInvalidatingPublicationRepo(pbcRepo, cache)
+
+	cache.Set("relevant_genre", "rock", StatisticsTag)
+	cache.Set("release_limit", 1, PublicationsTag)
+
+	assert.Nil(t, invalidatingStatRepo.CreateMany(context.Background(), []models.Statistics{{TrackID: 1}}))
+	_, ok := cache.Get("relevant_genre")
+	assert.False(t, ok)
+	_, ok = cache.Get("release_limit")
+	assert.True(t, ok)
+
+	assert.Nil(t, invalidatingPbcRepo.Create(context.Background(), &models.Publication{}))
+	_, ok = cache.Get("release_limit")
+	assert.False(t, ok)
+}
+
+func TestInvalidatingStatisticsRepo_WithinTransaction(t *testing.T) {
+
+	statRepo := mocks.NewStatisticsRepo(t)
+	statRepo.EXPECT().CreateMany(mock.Anything, mock.Anything).Return(nil).Twice()
+
+	transactionMock := transacMock.NewTransactor(t)
+	transactionMock.EXPECT().WithinTransaction(mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}).Twice()
+	tx := transactor.WithAfterCommit(transactionMock)
+
+	cache := NewTTLCache(time.Hour)
+	invalidatingStatRepo := NewInvalidatingStatisticsRepo(statRepo, cache)
+
+	// entries stay until commit
+	cache.Set("relevant_genre", "rock", StatisticsTag)
+	errRollback := errors.New("rollback")
+	err := tx.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		assert.Nil(t, invalidatingStatRepo.CreateMany(ctx, []models.Statistics{{TrackID: 1}}))
+		return errRollback
+	})
+	assert.ErrorIs(t, err, errRollback)
+	_, ok := cache.Get("relevant_genre")
+	assert.True(t, ok)
+
+	err = tx.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		assert.Nil(t, invalidatingStatRepo.CreateMany(ctx, []models.Statistics{{TrackID: 1}}))
+		_, ok := cache.Get("relevant_genre")
+		assert.True(t, ok)
+		return nil
+	})
+	assert.Nil(t, err)
+	_, ok = cache.Get("relevant_genre")
+	assert.False(t, ok)
+}
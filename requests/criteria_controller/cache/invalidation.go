@@ -0,0 +1,65 @@
+package criteria_cache
+
+import (
+	"context"
+
+	"github.com/rauzh/cd-core/models"
+	"github.com/rauzh/cd-core/repo"
+	"github.com/rauzh/cd-core/transactor"
+)
+
+// InvalidatingStatisticsRepo drops statistics based entries
+// after new statistics are stored. Statistics service, scheduler and importer
+// all store statistics through the repo, so wrapping it covers every writer.
+// Writes within a transaction invalidate after commit, otherwise an entry computed
+// from not yet committed data would be cached under the new generation,
+// so their transactor must be wrapped by transactor.WithAfterCommit as NewATtrm does
+type InvalidatingStatisticsRepo struct {
+	repo.StatisticsRepo
+	cache ICriteriaCache
+}
+
+func NewInvalidatingStatisticsRepo(statRepo repo.StatisticsRepo, cache ICriteriaCache) repo.StatisticsRepo {
+	return &InvalidatingStatisticsRepo{StatisticsRepo: statRepo, cache: cache}
+}
+
+func (statRepo *InvalidatingStatisticsRepo) Create(ctx context.Context, stat *models.Statistics) error {
+	if err := statRepo.StatisticsRepo.Create(ctx, stat); err != nil {
+		return err
+	}
+	statRepo.invalidate(ctx)
+	return nil
+}
+
+func (statRepo *InvalidatingStatisticsRepo) CreateMany(ctx context.Context, stats []models.Statistics) error {
+	if err := statRepo.StatisticsRepo.CreateMany(ctx, stats); err != nil {
+		return err
+	}
+	if len(stats) > 0 {
+		statRepo.invalidate(ctx)
+	}
+	return nil
+}
+
+func (statRepo *InvalidatingStatisticsRepo) invalidate(ctx context.Context) {
+	transactor.AfterCommit(ctx, func() { statRepo.cache.Invalidate(StatisticsTag) })
+}
+
+// InvalidatingPublicationRepo drops publications based entries
+// after new publication is stored, within a transaction after commit
+type InvalidatingPublicationRepo struct {
+	repo.PublicationRepo
+	cache ICriteriaCache
+}
+
+func NewInvalidatingPublicationRepo(pbcRepo repo.PublicationRepo, cache ICriteriaCache) repo.PublicationRepo {
+	return &InvalidatingPublicationRepo{PublicationRepo: pbcRepo, cache: cache}
+}
+
+func (pbcRepo *InvalidatingPublicationRepo) Create(ctx context.Context, publication *models.Publication) error {
+	if err := pbcRepo.PublicationRepo.Create(ctx, publication); err != nil {
+		return err
+	}
+	transactor.AfterCommit(ctx, func() { pbcRepo.cache.Invalidate(PublicationsTag) })
+	return nil
+}
@@ -9,6 +9,8 @@ import (
 type CriteriaCollectionDiff struct {
 	ResultDiff        int
 	ResultExplanation map[CriteriaName]CriteriaDiff
+	// ResultOrder keeps criterias in the order of collection
+	ResultOrder []CriteriaName
 }
 
 type ICriteriaCollection interface {
@@ -29,6 +31,7 @@ func (cc *CriteriaCollection) Apply(request base.IRequest) (result CriteriaColle
 
 		result.ResultDiff += critRes.Diff
 		result.ResultExplanation[crit.Name()] = critRes
+		result.ResultOrder = append(result.ResultOrder, crit.Name())
 	}
 
 	return
@@ -0,0 +1,159 @@
+package publish_criteria
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rauzh/cd-core/models"
+	"github.com/rauzh/cd-core/repo"
+	"github.com/rauzh/cd-core/requests/base"
+	criteria "github.com/rauzh/cd-core/requests/criteria_controller"
+	criteria_cache "github.com/rauzh/cd-core/requests/criteria_controller/cache"
+	"github.com/rauzh/cd-core/requests/publish"
+	"github.com/rauzh/cd-core/statistics/convert"
+	cdtime "github.com/rauzh/cd-core/time"
+)
+
+const (
+	ArtistMomentum             criteria.CriteriaName = "Artist should be rising"
+	ExplanationArtistRising                          = "Artist is rising"
+	ExplanationArtistDeclining                       = "Artist is declining"
+	ExplanationNoMomentumStats                       = "Not enough statistics for previous season"
+	DiffArtistRising                                 = 1
+	DiffArtistDeclining                              = -1
+)
+
+// MomentumThreshold is a growth in percents (both for streams and likes)
+// season to season that makes artist rising or declining
+var MomentumThreshold = 10.0
+
+type ArtistMomentumCriteria struct {
+	artistRepo  repo.ArtistRepo
+	releaseRepo repo.ReleaseRepo
+	statRepo    repo.StatisticsRepo
+	cache       criteria_cache.ICriteriaCache
+}
+
+type seasonsTotals struct {
+	curStreams, curLikes   uint64
+	prevStreams, prevLikes uint64
+}
+
+func artistMomentumCacheKey(artistID uint64) string {
+	return fmt.Sprintf("artist_momentum:%d", artistID)
+}
+
+func (amc *ArtistMomentumCriteria) Name() criteria.CriteriaName {
+	return ArtistMomentum
+}
+
+func (amc *ArtistMomentumCriteria) Apply(request base.IRequest) (result criteria.CriteriaDiff) {
+
+	if err := request.Validate(publish.PubReq); err != nil {
+		result.Explanation = criteria.ExplanationCantApply
+		return
+	}
+	pubReq := request.(*publish.PublishRequest)
+
+	ctx := context.Background()
+
+	artist, err := amc.artistRepo.GetByUserID(ctx, pubReq.ApplierID)
+	if err != nil {
+		result.Explanation = criteria.ExplanationCantApply
+		return
+	}
+
+	totals, err := criteria_cache.Remember(amc.cache, artistMomentumCacheKey(artist.ArtistID),
+		[]criteria_cache.Tag{criteria_cache.StatisticsTag, criteria_cache.PublicationsTag},
+		func() (seasonsTotals, error) {
+			return amc.computeSeasonsTotals(ctx, artist.ArtistID)
+		})
+	if err != nil {
+		result.Explanation = criteria.ExplanationCantApply
+		return
+	}
+
+	if totals.prevStreams == 0 || totals.prevLikes == 0 {
+		result.Explanation = ExplanationNoMomentumStats
+		return
+	}
+
+	streamsGrowth, likesGrowth := growth(totals.prevStreams, totals.curStreams),
+		growth(totals.prevLikes, totals.curLikes)
+
+	switch {
+	case streamsGrowth >= MomentumThreshold && likesGrowth >= MomentumThreshold:
+		result.Diff = DiffArtistRising
+		result.Explanation = momentumExplanation(ExplanationArtistRising, streamsGrowth, likesGrowth)
+	case streamsGrowth <= -MomentumThreshold && likesGrowth <= -MomentumThreshold:
+		result.Diff = DiffArtistDeclining
+		result.Explanation = momentumExplanation(ExplanationArtistDeclining, streamsGrowth, likesGrowth)
+	default:
+		result.Explanation = momentumExplanation(criteria.ExplanationOK, streamsGrowth, likesGrowth)
+	}
+
+	return
+}
+
+func (amc *ArtistMomentumCriteria) computeSeasonsTotals(ctx context.Context, artistID uint64) (seasonsTotals, error) {
+
+	var totals seasonsTotals
+
+	releases, err := amc.releaseRepo.GetAllByArtist(ctx, artistID)
+	if err != nil {
+		return totals, err
+	}
+
+	var trackIDs []uint64
+	for _, release := range releases {
+		if release.Status == models.PublishedRelease {
+			trackIDs = append(trackIDs, release.Tracks...)
+		}
+	}
+
+	curSeasonStart, prevSeasonStart := cdtime.RelevantPeriod(), cdtime.PreviousRelevantPeriod()
+
+	// totals grow with time, so seasons are compared by gained streams
+	deltas, err := convert.DeltasBetween(ctx, amc.statRepo, trackIDs, prevSeasonStart, cdtime.GetToday())
+	if err != nil {
+		return totals, err
+	}
+
+	for _, stat := range deltas {
+		if !stat.Date.Before(curSeasonStart) {
+			totals.curStreams += stat.Streams
+			totals.curLikes += stat.Likes
+		} else {
+			totals.prevStreams += stat.Streams
+			totals.prevLikes += stat.Likes
+		}
+	}
+
+	return totals, nil
+}
+
+func growth(prev, cur uint64) float64 {
+	return (float64(cur) - float64(prev)) / float64(prev) * 100
+}
+
+func momentumExplanation(explanation string, streamsGrowth, likesGrowth float64) string {
+	return fmt.Sprintf("%s: streams %+.1f%%, likes %+.1f%% since %s",
+		explanation, streamsGrowth, likesGrowth, cdtime.RelevantPeriod().Format(time.DateOnly))
+}
+
+type ArtistMomentumCriteriaFabric struct {
+	ArtistRepo  repo.ArtistRepo
+	ReleaseRepo repo.ReleaseRepo
+	StatRepo    repo.StatisticsRepo
+	Cache       criteria_cache.ICriteriaCache
+}
+
+func (fabric *ArtistMomentumCriteriaFabric) Create() (criteria.Criteria, error) {
+	return &ArtistMomentumCriteria{
+		artistRepo:  fabric.ArtistRepo,
+		releaseRepo: fabric.ReleaseRepo,
+		statRepo:    fabric.StatRepo,
+		cache:       fabric.Cache,
+	}, nil
+}
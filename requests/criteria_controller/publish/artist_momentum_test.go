@@ -0,0 +1,130 @@
+package publish_criteria
+
+import (
+	"testing"
+
+	"github.com/rauzh/cd-core/models"
+	"github.com/rauzh/cd-core/repo/mocks"
+	"github.com/rauzh/cd-core/requests/base"
+	criteria "github.com/rauzh/cd-core/requests/criteria_controller"
+	"github.com/rauzh/cd-core/requests/publish"
+	cdtime "github.com/rauzh/cd-core/time"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestArtistMomentumCriteria_Apply(t *testing.T) {
+
+	pubReq := &publish.PublishRequest{
+		Request: base.Request{
+			RequestID: 1,
+			Type:      publish.PubReq,
+			Status:    base.ProcessingRequest,
+			Date:      cdtime.GetToday(),
+			ApplierID: 12,
+		},
+		ReleaseID:    777,
+		ExpectedDate: cdtime.GetToday().AddDate(1, 0, 0),
+	}
+
+	curDate := cdtime.GetToday()
+	prevDate := cdtime.RelevantPeriod().AddDate(0, 0, -1)
+	tooOldDate := cdtime.PreviousRelevantPeriod().AddDate(0, 0, -1)
+
+	tests := []struct {
+		name     string
+		baseline []models.Statistics
+		stats    []models.Statistics
+		diff     int
+	}{
+		{
+			name:     "Rising",
+			baseline: []models.Statistics{{Date: tooOldDate, Streams: 100000, Likes: 10000, Kind: models.DeltaStatistics}},
+			stats: []models.Statistics{
+				{Date: prevDate, Streams: 100, Likes: 10, Kind: models.DeltaStatistics},
+				{Date: curDate, Streams: 200, Likes: 20, Kind: models.DeltaStatistics},
+			},
+			diff: DiffArtistRising,
+		},
+		{
+			name: "Declining",
+			stats: []models.Statistics{
+				{Date: prevDate, Streams: 200, Likes: 20, Kind: models.DeltaStatistics},
+				{Date: curDate, Streams: 100, Likes: 10, Kind: models.DeltaStatistics},
+			},
+			diff: DiffArtistDeclining,
+		},
+		{
+			name: "Stable",
+			stats: []models.Statistics{
+				{Date: prevDate, Streams: 200, Likes: 20, Kind: models.DeltaStatistics},
+				{Date: curDate, Streams: 300, Likes: 20, Kind: models.DeltaStatistics},
+			},
+			diff: 0,
+		},
+		{
+			// totals before the previous season are the baseline, not gained streams
+			name:     "Cumulative",
+			baseline: []models.Statistics{{Date: tooOldDate, Streams: 1000, Likes: 100}},
+			stats: []models.Statistics{
+				{Date: prevDate, Streams: 1100, Likes: 110},
+				{Date: curDate, Streams: 1300, Likes: 132},
+			},
+			diff: DiffArtistRising,
+		},
+		{
+			// worldwide row overlaps per country ones, summed up it would hide the growth
+			name: "WorldwideAndPerCountry",
+			stats: []models.Statistics{
+				{Date: prevDate, Platform: "spotify", Streams: 1000, Likes: 100, Kind: models.DeltaStatistics},
+				{Date: prevDate, Platform: "spotify", Country: "US", Streams: 60, Likes: 6, Kind: models.DeltaStatistics},
+				{Date: prevDate, Platform: "spotify", Country: "DE", Streams: 40, Likes: 4, Kind: models.DeltaStatistics},
+				{Date: curDate, Platform: "spotify", Streams: 1000, Likes: 100, Kind: models.DeltaStatistics},
+				{Date: curDate, Platform: "spotify", Country: "US", Streams: 120, Likes: 12, Kind: models.DeltaStatistics},
+				{Date: curDate, Platform: "spotify", Country: "DE", Streams: 80, Likes: 8, Kind: models.DeltaStatistics},
+			},
+			diff: DiffArtistRising,
+		},
+		{
+			name: "NoPreviousSeason",
+			stats: []models.Statistics{
+				{Date: curDate, Streams: 300, Likes: 20, Kind: models.DeltaStatistics},
+			},
+			diff: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			artistRepo := mocks.NewArtistRepo(t)
+			releaseRepo := mocks.NewReleaseRepo(t)
+			statRepo := mocks.NewStatisticsRepo(t)
+
+			artistRepo.EXPECT().GetByUserID(mock.AnythingOfType("context.backgroundCtx"), uint64(12)).Return(
+				&models.Artist{ArtistID: 199}, nil).Once()
+
+			releaseRepo.EXPECT().GetAllByArtist(mock.AnythingOfType("context.backgroundCtx"), uint64(199)).Return(
+				[]models.Release{
+					{ReleaseID: 1, Status: models.PublishedRelease, Tracks: []uint64{11}},
+					{ReleaseID: 777, Status: models.UnpublishedRelease, Tracks: []uint64{71}},
+				}, nil).Once()
+
+			statRepo.EXPECT().GetLastForTracksBefore(mock.AnythingOfType("context.backgroundCtx"), []uint64{11},
+				cdtime.PreviousRelevantPeriod()).Return(tt.baseline, nil).Once()
+			statRepo.EXPECT().GetForTracksBetween(mock.AnythingOfType("context.backgroundCtx"), []uint64{11},
+				cdtime.PreviousRelevantPeriod(), curDate).Return(tt.stats, nil).Once()
+
+			crit, _ := (&ArtistMomentumCriteriaFabric{
+				ArtistRepo:  artistRepo,
+				ReleaseRepo: releaseRepo,
+				StatRepo:    statRepo,
+			}).Create()
+
+			res := crit.Apply(pubReq)
+
+			assert.Equal(t, tt.diff, res.Diff)
+			assert.NotEqual(t, criteria.ExplanationCantApply, res.Explanation)
+		})
+	}
+}
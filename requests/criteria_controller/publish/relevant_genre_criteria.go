@@ -1,11 +1,11 @@
 package publish_criteria
 
 import (
-	"strings"
-
+	"github.com/rauzh/cd-core/genre"
 	releaseService "github.com/rauzh/cd-core/release/service"
 	"github.com/rauzh/cd-core/requests/base"
 	criteria "github.com/rauzh/cd-core/requests/criteria_controller"
+	criteria_cache "github.com/rauzh/cd-core/requests/criteria_controller/cache"
 	"github.com/rauzh/cd-core/requests/publish"
 	statService "github.com/rauzh/cd-core/statistics/service"
 )
@@ -14,11 +14,15 @@ const (
 	RelevantGenre            criteria.CriteriaName = "Genre should be relevant"
 	ExplanationRelevantGenre                       = "Genre is irrelevant"
 	DiffRelevantGenre                              = -1
+
+	relevantGenreCacheKey = "relevant_genre"
 )
 
 type RelevantGenreCriteria struct {
 	releaseService releaseService.IReleaseService
 	statService    statService.IStatisticsService
+	taxonomy       genre.ITaxonomy
+	cache          criteria_cache.ICriteriaCache
 }
 
 func (rgc *RelevantGenreCriteria) Name() criteria.CriteriaName {
@@ -39,13 +43,15 @@ func (rgc *RelevantGenreCriteria) Apply(request base.IRequest) (result criteria.
 		return
 	}
 
-	relevantGenre, err := rgc.statService.GetRelevantGenre()
+	relevantGenre, err := criteria_cache.Remember(rgc.cache, relevantGenreCacheKey,
+		[]criteria_cache.Tag{criteria_cache.StatisticsTag}, rgc.statService.GetRelevantGenre)
 	if err != nil {
 		result.Explanation = criteria.ExplanationCantApply
 		return
 	}
 
-	if strings.ToLower(releaseGenre) != strings.ToLower(relevantGenre) {
+	// release genre is relevant if it is in the same family, e.g. "trap" for "hip-hop"
+	if !rgc.taxonomy.SameFamily(rgc.taxonomy.Canonical(releaseGenre), rgc.taxonomy.Canonical(relevantGenre)) {
 		result.Diff = DiffRelevantGenre
 		result.Explanation = ExplanationRelevantGenre
 		return
@@ -59,8 +65,24 @@ func (rgc *RelevantGenreCriteria) Apply(request base.IRequest) (result criteria.
 type RelevantGenreCriteriaFabric struct {
 	ReleaseService releaseService.IReleaseService
 	StatService    statService.IStatisticsService
+	Taxonomy       genre.ITaxonomy
+	Cache          criteria_cache.ICriteriaCache
 }
 
 func (fabric *RelevantGenreCriteriaFabric) Create() (criteria.Criteria, error) {
-	return &RelevantGenreCriteria{releaseService: fabric.ReleaseService, statService: fabric.StatService}, nil
+	taxonomy := fabric.Taxonomy
+	if taxonomy == nil {
+		defaultTaxonomy, err := genre.DefaultTaxonomy()
+		if err != nil {
+			return nil, err
+		}
+		taxonomy = defaultTaxonomy
+	}
+
+	return &RelevantGenreCriteria{
+		releaseService: fabric.ReleaseService,
+		statService:    fabric.StatService,
+		taxonomy:       taxonomy,
+		cache:          fabric.Cache,
+	}, nil
 }
@@ -0,0 +1,60 @@
+package sign_contract_criteria
+
+import (
+	"context"
+
+	"github.com/rauzh/cd-core/repo"
+	"github.com/rauzh/cd-core/requests/base"
+	criteria "github.com/rauzh/cd-core/requests/criteria_controller"
+	"github.com/rauzh/cd-core/requests/sign_contract"
+	cdtime "github.com/rauzh/cd-core/time"
+)
+
+const (
+	ApplierAccountAge     criteria.CriteriaName = "Applier account should not be new"
+	ExplanationNewAccount                       = "Applier account is too new"
+	DiffApplierAccountAge                       = -1
+)
+
+var MinAccountAgeDays = 30
+
+type ApplierAccountAgeCriteria struct {
+	userRepo repo.UserRepo
+}
+
+func (aaac *ApplierAccountAgeCriteria) Name() criteria.CriteriaName {
+	return ApplierAccountAge
+}
+
+func (aaac *ApplierAccountAgeCriteria) Apply(request base.IRequest) (result criteria.CriteriaDiff) {
+
+	if err := request.Validate(sign_contract.SignRequest); err != nil {
+		result.Explanation = criteria.ExplanationCantApply
+		return
+	}
+	signReq := request.(*sign_contract.SignContractRequest)
+
+	user, err := aaac.userRepo.Get(context.Background(), signReq.ApplierID)
+	if err != nil || user.DateCreation.IsZero() {
+		result.Explanation = criteria.ExplanationCantApply
+		return
+	}
+
+	if user.DateCreation.After(cdtime.GetToday().AddDate(0, 0, -MinAccountAgeDays)) {
+		result.Diff = DiffApplierAccountAge
+		result.Explanation = ExplanationNewAccount
+		return
+	}
+
+	result.Explanation = criteria.ExplanationOK
+
+	return
+}
+
+type ApplierAccountAgeCriteriaFabric struct {
+	UserRepo repo.UserRepo
+}
+
+func (fabric *ApplierAccountAgeCriteriaFabric) Create() (criteria.Criteria, error) {
+	return &ApplierAccountAgeCriteria{userRepo: fabric.UserRepo}, nil
+}
@@ -0,0 +1,76 @@
+package sign_contract_criteria
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rauzh/cd-core/models"
+	"github.com/rauzh/cd-core/repo/mocks"
+	"github.com/rauzh/cd-core/requests/base"
+	criteria "github.com/rauzh/cd-core/requests/criteria_controller"
+	"github.com/rauzh/cd-core/requests/sign_contract"
+	cdtime "github.com/rauzh/cd-core/time"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestApplierAccountAgeCriteria_Apply(t *testing.T) {
+
+	signReq := &sign_contract.SignContractRequest{
+		Request: base.Request{
+			RequestID: 1,
+			Type:      sign_contract.SignRequest,
+			Status:    base.ProcessingRequest,
+			ApplierID: 12,
+		},
+		Nickname: "skibidi",
+	}
+
+	tests := []struct {
+		name        string
+		user        *models.User
+		err         error
+		diff        int
+		explanation string
+	}{
+		{
+			name:        "OldAccount",
+			user:        &models.User{UserID: 12, DateCreation: cdtime.GetToday().AddDate(-1, 0, 0)},
+			diff:        0,
+			explanation: criteria.ExplanationOK,
+		},
+		{
+			name:        "NewAccount",
+			user:        &models.User{UserID: 12, DateCreation: cdtime.GetToday().AddDate(0, 0, -1)},
+			diff:        DiffApplierAccountAge,
+			explanation: ExplanationNewAccount,
+		},
+		{
+			name:        "NoCreationDate",
+			user:        &models.User{UserID: 12},
+			diff:        0,
+			explanation: criteria.ExplanationCantApply,
+		},
+		{
+			name:        "RepoError",
+			err:         errors.New("db err"),
+			diff:        0,
+			explanation: criteria.ExplanationCantApply,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			userRepo := mocks.NewUserRepo(t)
+			userRepo.EXPECT().Get(mock.AnythingOfType("context.backgroundCtx"), uint64(12)).Return(tt.user, tt.err).Once()
+
+			crit, _ := (&ApplierAccountAgeCriteriaFabric{UserRepo: userRepo}).Create()
+
+			res := crit.Apply(signReq)
+
+			assert.Equal(t, tt.diff, res.Diff)
+			assert.Equal(t, tt.explanation, res.Explanation)
+		})
+	}
+}
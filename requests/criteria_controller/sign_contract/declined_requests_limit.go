@@ -0,0 +1,66 @@
+package sign_contract_criteria
+
+import (
+	"context"
+
+	"github.com/rauzh/cd-core/requests/base"
+	criteria "github.com/rauzh/cd-core/requests/criteria_controller"
+	"github.com/rauzh/cd-core/requests/sign_contract"
+	signRepo "github.com/rauzh/cd-core/requests/sign_contract/repo"
+)
+
+const (
+	DeclinedRequestsLimit       criteria.CriteriaName = "No more than limit declined sign requests"
+	ExplanationDeclinedRequests                       = "More than limit sign requests were declined before"
+	DiffDeclinedRequests                              = -1
+)
+
+var DeclinedLimit = 2
+
+type DeclinedRequestsLimitCriteria struct {
+	signReqRepo signRepo.SignContractRequestRepo
+}
+
+func (drlc *DeclinedRequestsLimitCriteria) Name() criteria.CriteriaName {
+	return DeclinedRequestsLimit
+}
+
+func (drlc *DeclinedRequestsLimitCriteria) Apply(request base.IRequest) (result criteria.CriteriaDiff) {
+
+	if err := request.Validate(sign_contract.SignRequest); err != nil {
+		result.Explanation = criteria.ExplanationCantApply
+		return
+	}
+	signReq := request.(*sign_contract.SignContractRequest)
+
+	prevReqs, err := drlc.signReqRepo.GetAllByApplierID(context.Background(), signReq.ApplierID)
+	if err != nil {
+		result.Explanation = criteria.ExplanationCantApply
+		return
+	}
+
+	declined := 0
+	for _, prevReq := range prevReqs {
+		if prevReq.RequestID != signReq.RequestID && prevReq.IsDeclined() {
+			declined++
+		}
+	}
+
+	if declined > DeclinedLimit {
+		result.Diff = DiffDeclinedRequests
+		result.Explanation = ExplanationDeclinedRequests
+		return
+	}
+
+	result.Explanation = criteria.ExplanationOK
+
+	return
+}
+
+type DeclinedRequestsLimitCriteriaFabric struct {
+	SignReqRepo signRepo.SignContractRequestRepo
+}
+
+func (fabric *DeclinedRequestsLimitCriteriaFabric) Create() (criteria.Criteria, error) {
+	return &DeclinedRequestsLimitCriteria{signReqRepo: fabric.SignReqRepo}, nil
+}
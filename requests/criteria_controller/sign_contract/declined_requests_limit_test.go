@@ -0,0 +1,94 @@
+package sign_contract_criteria
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rauzh/cd-core/requests/base"
+	criteria "github.com/rauzh/cd-core/requests/criteria_controller"
+	"github.com/rauzh/cd-core/requests/sign_contract"
+	signReqRepoMocks "github.com/rauzh/cd-core/requests/sign_contract/repo/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func declinedSignRequest(requestID uint64) sign_contract.SignContractRequest {
+	return sign_contract.SignContractRequest{
+		Request:     base.Request{RequestID: requestID, Type: sign_contract.SignRequest, Status: base.ClosedRequest, ApplierID: 12},
+		Description: base.DescrDeclinedRequest,
+	}
+}
+
+func TestDeclinedRequestsLimitCriteria_Apply(t *testing.T) {
+
+	signReq := &sign_contract.SignContractRequest{
+		Request: base.Request{
+			RequestID: 1,
+			Type:      sign_contract.SignRequest,
+			Status:    base.ProcessingRequest,
+			ApplierID: 12,
+		},
+		Nickname: "skibidi",
+	}
+
+	tests := []struct {
+		name        string
+		prevReqs    []sign_contract.SignContractRequest
+		err         error
+		diff        int
+		explanation string
+	}{
+		{
+			name:        "NoPreviousRequests",
+			diff:        0,
+			explanation: criteria.ExplanationOK,
+		},
+		{
+			name: "DeclinedUpToLimit",
+			prevReqs: []sign_contract.SignContractRequest{
+				declinedSignRequest(2), declinedSignRequest(3),
+				{Request: base.Request{RequestID: 4, Status: base.ClosedRequest, ApplierID: 12}},
+			},
+			diff:        0,
+			explanation: criteria.ExplanationOK,
+		},
+		{
+			name: "DeclinedOverLimit",
+			prevReqs: []sign_contract.SignContractRequest{
+				declinedSignRequest(2), declinedSignRequest(3), declinedSignRequest(4),
+			},
+			diff:        DiffDeclinedRequests,
+			explanation: ExplanationDeclinedRequests,
+		},
+		{
+			name: "RequestItselfIsNotCounted",
+			prevReqs: []sign_contract.SignContractRequest{
+				declinedSignRequest(1), declinedSignRequest(2), declinedSignRequest(3),
+			},
+			diff:        0,
+			explanation: criteria.ExplanationOK,
+		},
+		{
+			name:        "RepoError",
+			err:         errors.New("db err"),
+			diff:        0,
+			explanation: criteria.ExplanationCantApply,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			signReqRepo := signReqRepoMocks.NewSignContractRequestRepo(t)
+			signReqRepo.EXPECT().GetAllByApplierID(mock.AnythingOfType("context.backgroundCtx"), uint64(12)).Return(
+				tt.prevReqs, tt.err).Once()
+
+			crit, _ := (&DeclinedRequestsLimitCriteriaFabric{SignReqRepo: signReqRepo}).Create()
+
+			res := crit.Apply(signReq)
+
+			assert.Equal(t, tt.diff, res.Diff)
+			assert.Equal(t, tt.explanation, res.Explanation)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package sign_contract_criteria
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	repo_errors "github.com/rauzh/cd-core/errors/repo"
+	"github.com/rauzh/cd-core/repo"
+	"github.com/rauzh/cd-core/requests/base"
+	criteria "github.com/rauzh/cd-core/requests/criteria_controller"
+	"github.com/rauzh/cd-core/requests/sign_contract"
+)
+
+const (
+	NicknameUniqueness       criteria.CriteriaName = "Nickname should be unique"
+	ExplanationNicknameTaken                       = "Nickname is already taken by another artist"
+	DiffNicknameTaken                              = -1
+)
+
+type NicknameUniquenessCriteria struct {
+	artistRepo repo.ArtistRepo
+}
+
+func (nuc *NicknameUniquenessCriteria) Name() criteria.CriteriaName {
+	return NicknameUniqueness
+}
+
+func (nuc *NicknameUniquenessCriteria) Apply(request base.IRequest) (result criteria.CriteriaDiff) {
+
+	if err := request.Validate(sign_contract.SignRequest); err != nil {
+		result.Explanation = criteria.ExplanationCantApply
+		return
+	}
+	signReq := request.(*sign_contract.SignContractRequest)
+
+	nickname := normalizeNickname(signReq.Nickname)
+
+	artist, err := nuc.artistRepo.GetByNickname(context.Background(), nickname)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) && !errors.Is(err, repo_errors.ErrorNotExists) {
+		result.Explanation = criteria.ExplanationCantApply
+		return
+	}
+
+	if artist != nil && normalizeNickname(artist.Nickname) == nickname {
+		result.Diff = DiffNicknameTaken
+		result.Explanation = ExplanationNicknameTaken
+		return
+	}
+
+	result.Explanation = criteria.ExplanationOK
+
+	return
+}
+
+// nicknames differing only in case or surrounding spaces are the same nickname
+func normalizeNickname(nickname string) string {
+	return strings.ToLower(strings.TrimSpace(nickname))
+}
+
+type NicknameUniquenessCriteriaFabric struct {
+	ArtistRepo repo.ArtistRepo
+}
+
+func (fabric *NicknameUniquenessCriteriaFabric) Create() (criteria.Criteria, error) {
+	return &NicknameUniquenessCriteria{artistRepo: fabric.ArtistRepo}, nil
+}
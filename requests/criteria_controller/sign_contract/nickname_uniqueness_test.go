@@ -0,0 +1,80 @@
+package sign_contract_criteria
+
+import (
+	"errors"
+	"testing"
+
+	repo_errors "github.com/rauzh/cd-core/errors/repo"
+	"github.com/rauzh/cd-core/models"
+	"github.com/rauzh/cd-core/repo/mocks"
+	"github.com/rauzh/cd-core/requests/base"
+	criteria "github.com/rauzh/cd-core/requests/criteria_controller"
+	"github.com/rauzh/cd-core/requests/sign_contract"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNicknameUniquenessCriteria_Apply(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		nickname    string
+		artist      *models.Artist
+		err         error
+		diff        int
+		explanation string
+	}{
+		{
+			name:        "FreeNickname",
+			nickname:    "skibidi",
+			err:         repo_errors.ErrorNotExists,
+			diff:        0,
+			explanation: criteria.ExplanationOK,
+		},
+		{
+			name:        "TakenNickname",
+			nickname:    "skibidi",
+			artist:      &models.Artist{ArtistID: 7, Nickname: "skibidi"},
+			diff:        DiffNicknameTaken,
+			explanation: ExplanationNicknameTaken,
+		},
+		{
+			name:        "TakenNicknameOtherCase",
+			nickname:    " Skibidi ",
+			artist:      &models.Artist{ArtistID: 7, Nickname: "skiBIDI"},
+			diff:        DiffNicknameTaken,
+			explanation: ExplanationNicknameTaken,
+		},
+		{
+			name:        "RepoError",
+			nickname:    "skibidi",
+			err:         errors.New("db err"),
+			diff:        0,
+			explanation: criteria.ExplanationCantApply,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			artistRepo := mocks.NewArtistRepo(t)
+			artistRepo.EXPECT().GetByNickname(mock.AnythingOfType("context.backgroundCtx"), "skibidi").Return(
+				tt.artist, tt.err).Once()
+
+			crit, _ := (&NicknameUniquenessCriteriaFabric{ArtistRepo: artistRepo}).Create()
+
+			res := crit.Apply(&sign_contract.SignContractRequest{
+				Request: base.Request{
+					RequestID: 1,
+					Type:      sign_contract.SignRequest,
+					Status:    base.ProcessingRequest,
+					ApplierID: 12,
+				},
+				Nickname: tt.nickname,
+			})
+
+			assert.Equal(t, tt.diff, res.Diff)
+			assert.Equal(t, tt.explanation, res.Explanation)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package sign_contract_criteria
+
+import (
+	"context"
+
+	"github.com/rauzh/cd-core/requests/base"
+	criteria "github.com/rauzh/cd-core/requests/criteria_controller"
+	"github.com/rauzh/cd-core/requests/sign_contract"
+	signRepo "github.com/rauzh/cd-core/requests/sign_contract/repo"
+)
+
+const (
+	NoDuplicateOpenRequests   criteria.CriteriaName = "No other open sign requests"
+	ExplanationDuplicateOpen                        = "Applier has another open sign request"
+	DiffDuplicateOpenRequests                       = -1
+)
+
+type NoDuplicateOpenRequestsCriteria struct {
+	signReqRepo signRepo.SignContractRequestRepo
+}
+
+func (ndorc *NoDuplicateOpenRequestsCriteria) Name() criteria.CriteriaName {
+	return NoDuplicateOpenRequests
+}
+
+func (ndorc *NoDuplicateOpenRequestsCriteria) Apply(request base.IRequest) (result criteria.CriteriaDiff) {
+
+	if err := request.Validate(sign_contract.SignRequest); err != nil {
+		result.Explanation = criteria.ExplanationCantApply
+		return
+	}
+	signReq := request.(*sign_contract.SignContractRequest)
+
+	prevReqs, err := ndorc.signReqRepo.GetAllByApplierID(context.Background(), signReq.ApplierID)
+	if err != nil {
+		result.Explanation = criteria.ExplanationCantApply
+		return
+	}
+
+	for _, prevReq := range prevReqs {
+		if prevReq.RequestID != signReq.RequestID && prevReq.Status != base.ClosedRequest {
+			result.Diff = DiffDuplicateOpenRequests
+			result.Explanation = ExplanationDuplicateOpen
+			return
+		}
+	}
+
+	result.Explanation = criteria.ExplanationOK
+
+	return
+}
+
+type NoDuplicateOpenRequestsCriteriaFabric struct {
+	SignReqRepo signRepo.SignContractRequestRepo
+}
+
+func (fabric *NoDuplicateOpenRequestsCriteriaFabric) Create() (criteria.Criteria, error) {
+	return &NoDuplicateOpenRequestsCriteria{signReqRepo: fabric.SignReqRepo}, nil
+}
@@ -0,0 +1,81 @@
+package sign_contract_criteria
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rauzh/cd-core/requests/base"
+	criteria "github.com/rauzh/cd-core/requests/criteria_controller"
+	"github.com/rauzh/cd-core/requests/sign_contract"
+	signReqRepoMocks "github.com/rauzh/cd-core/requests/sign_contract/repo/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNoDuplicateOpenRequestsCriteria_Apply(t *testing.T) {
+
+	signReq := &sign_contract.SignContractRequest{
+		Request: base.Request{
+			RequestID: 1,
+			Type:      sign_contract.SignRequest,
+			Status:    base.ProcessingRequest,
+			ApplierID: 12,
+		},
+		Nickname: "skibidi",
+	}
+
+	tests := []struct {
+		name        string
+		prevReqs    []sign_contract.SignContractRequest
+		err         error
+		diff        int
+		explanation string
+	}{
+		{
+			name: "OnlyClosedRequests",
+			prevReqs: []sign_contract.SignContractRequest{
+				{Request: base.Request{RequestID: 2, Status: base.ClosedRequest, ApplierID: 12}},
+			},
+			diff:        0,
+			explanation: criteria.ExplanationOK,
+		},
+		{
+			name: "OtherOpenRequest",
+			prevReqs: []sign_contract.SignContractRequest{
+				{Request: base.Request{RequestID: 2, Status: base.OnApprovalRequest, ApplierID: 12}},
+			},
+			diff:        DiffDuplicateOpenRequests,
+			explanation: ExplanationDuplicateOpen,
+		},
+		{
+			name: "RequestItselfIsNotDuplicate",
+			prevReqs: []sign_contract.SignContractRequest{
+				{Request: base.Request{RequestID: 1, Status: base.ProcessingRequest, ApplierID: 12}},
+			},
+			diff:        0,
+			explanation: criteria.ExplanationOK,
+		},
+		{
+			name:        "RepoError",
+			err:         errors.New("db err"),
+			diff:        0,
+			explanation: criteria.ExplanationCantApply,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			signReqRepo := signReqRepoMocks.NewSignContractRequestRepo(t)
+			signReqRepo.EXPECT().GetAllByApplierID(mock.AnythingOfType("context.backgroundCtx"), uint64(12)).Return(
+				tt.prevReqs, tt.err).Once()
+
+			crit, _ := (&NoDuplicateOpenRequestsCriteriaFabric{SignReqRepo: signReqRepo}).Create()
+
+			res := crit.Apply(signReq)
+
+			assert.Equal(t, tt.diff, res.Diff)
+			assert.Equal(t, tt.explanation, res.Explanation)
+		})
+	}
+}
@@ -4,19 +4,24 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/rauzh/cd-core/models"
 
 	"github.com/rauzh/cd-core/repo"
 	"github.com/rauzh/cd-core/requests/base"
-	"github.com/rauzh/cd-core/requests/broker"
 	"github.com/rauzh/cd-core/requests/broker/broker_dto"
+	"github.com/rauzh/cd-core/requests/broker/events"
+	"github.com/rauzh/cd-core/requests/broker/outbox"
+	outboxRepo "github.com/rauzh/cd-core/requests/broker/outbox/repo"
 	publish_req_broker "github.com/rauzh/cd-core/requests/broker/publish"
+	"github.com/rauzh/cd-core/requests/broker/tracing"
 	"github.com/rauzh/cd-core/requests/publish"
 	"github.com/rauzh/cd-core/requests/publish/errors"
 	publishReqRepo "github.com/rauzh/cd-core/requests/publish/repo"
 	statService "github.com/rauzh/cd-core/statistics/service"
 	"github.com/rauzh/cd-core/transactor"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type PublishRequestUseCase struct {
@@ -25,7 +30,8 @@ type PublishRequestUseCase struct {
 	releaseRepo     repo.ReleaseRepo
 	artistRepo      repo.ArtistRepo
 	transactor      transactor.Transactor
-	broker          broker.IBroker
+	outboxRepo      outboxRepo.OutboxRepo
+	eventPublisher  events.IEventPublisher
 
 	repo publishReqRepo.PublishRequestRepo
 
@@ -38,7 +44,7 @@ func NewPublishRequestUseCase(
 	releaseRepo repo.ReleaseRepo,
 	artistRepo repo.ArtistRepo,
 	transactor transactor.Transactor,
-	pbBroker broker.IBroker,
+	outboxRepo outboxRepo.OutboxRepo,
 	repo publishReqRepo.PublishRequestRepo,
 	logger *slog.Logger,
 ) (base.IRequestUseCase, error) {
@@ -50,14 +56,15 @@ func NewPublishRequestUseCase(
 		artistRepo:      artistRepo,
 		repo:            repo,
 		transactor:      transactor,
-		broker:          pbBroker,
+		outboxRepo:      outboxRepo,
+		eventPublisher:  events.NewOutboxPublisher(outboxRepo),
 		logger:          logger,
 	}
 
 	return publishUseCase, nil
 }
 
-func (publishUseCase *PublishRequestUseCase) Apply(request base.IRequest) error {
+func (publishUseCase *PublishRequestUseCase) Apply(request base.IRequest) (err error) {
 
 	if err := request.Validate(publish.PubReq); err != nil {
 		return err
@@ -66,21 +73,45 @@ func (publishUseCase *PublishRequestUseCase) Apply(request base.IRequest) error
 
 	base.InitDateStatus(&pubReq.Request)
 
+	ctx, span := tracing.Start(tracing.EnsureCorrelationID(context.Background()), "PublishRequestUseCase.Apply",
+		attribute.Int64("request.applier_id", int64(pubReq.ApplierID)),
+		attribute.Int64("request.release_id", int64(pubReq.ReleaseID)))
+	defer func() { tracing.End(span, err) }()
+
 	if err := publishUseCase.checkRelease(pubReq); err != nil {
 		return fmt.Errorf("can't apply publish request with err %w", err)
 	}
 
-	if err := publishUseCase.repo.Create(context.Background(), pubReq); err != nil {
-		publishUseCase.logger.Error("PUBREQ_UC Apply", slog.Any("error", err))
-		return fmt.Errorf("can't apply publish request with err %w", err)
-	}
+	err = publishUseCase.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := publishUseCase.repo.Create(ctx, pubReq); err != nil {
+			publishUseCase.logger.ErrorContext(ctx, "PUBREQ_UC TRANSACTION Apply", slog.Any("error", err))
+			return fmt.Errorf("can't apply publish request with err %w", err)
+		}
 
-	if err := publishUseCase.sendProceedToManagerMSG(pubReq); err != nil {
-		publishUseCase.logger.Error("PUBREQ_UC Apply", "req", pubReq.RequestID, slog.Any("error", err))
+		if err := publishUseCase.storeProceedToManagerMSG(ctx, pubReq); err != nil {
+			publishUseCase.logger.ErrorContext(ctx, "PUBREQ_UC TRANSACTION Apply", "req", pubReq.RequestID, slog.Any("error", err))
+			return err
+		}
+
+		err := publishUseCase.eventPublisher.Publish(ctx, &events.RequestApplied{
+			RequestID:  pubReq.RequestID,
+			Type:       pubReq.Type,
+			ApplierID:  pubReq.ApplierID,
+			OccurredAt: time.Now().UTC(),
+		})
+		if err != nil {
+			publishUseCase.logger.ErrorContext(ctx, "PUBREQ_UC TRANSACTION Apply", "req", pubReq.RequestID, slog.Any("error", err))
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
 		return err
 	}
 
-	publishUseCase.logger.Info("PUBREQ_UC Apply", "req", pubReq.RequestID)
+	span.SetAttributes(attribute.Int64("request.id", int64(pubReq.RequestID)))
+	publishUseCase.logger.InfoContext(ctx, "PUBREQ_UC Apply", "req", pubReq.RequestID)
 
 	return nil
 }
@@ -121,7 +152,7 @@ func (publishUseCase *PublishRequestUseCase) checkRelease(pubReq *publish.Publis
 	return nil
 }
 
-func (publishUseCase *PublishRequestUseCase) Accept(request base.IRequest) error {
+func (publishUseCase *PublishRequestUseCase) Accept(request base.IRequest) (err error) {
 
 	if err := request.Validate(publish.PubReq); err != nil {
 		publishUseCase.logger.Warn("PUBREQ_UC Accept", slog.Any("error", err))
@@ -135,32 +166,56 @@ func (publishUseCase *PublishRequestUseCase) Accept(request base.IRequest) error
 		ManagerID: pubReq.ManagerID,
 	}
 
-	ctx := context.Background()
+	ctx, span := tracing.Start(tracing.EnsureCorrelationID(context.Background()), "PublishRequestUseCase.Accept",
+		attribute.Int64("request.id", int64(pubReq.RequestID)),
+		attribute.Int64("request.manager_id", int64(pubReq.ManagerID)))
+	defer func() { tracing.End(span, err) }()
+
 	return publishUseCase.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
 
 		if err := publishUseCase.publicationRepo.Create(ctx, &publication); err != nil {
-			publishUseCase.logger.Error("PUBREQ_UC TRANSACTION Apply", "req", pubReq.RequestID, slog.Any("error", err))
+			publishUseCase.logger.ErrorContext(ctx, "PUBREQ_UC TRANSACTION Apply", "req", pubReq.RequestID, slog.Any("error", err))
 			return fmt.Errorf("can't create publication with err %w", err)
 		}
 
 		if err := publishUseCase.releaseRepo.UpdateStatus(ctx, publication.ReleaseID, models.PublishedRelease); err != nil {
-			publishUseCase.logger.Error("PUBREQ_UC TRANSACTION Apply", "req", pubReq.RequestID, slog.Any("error", err))
+			publishUseCase.logger.ErrorContext(ctx, "PUBREQ_UC TRANSACTION Apply", "req", pubReq.RequestID, slog.Any("error", err))
 			return fmt.Errorf("can't update publication with err %w", err)
 		}
 
 		pubReq.Status = base.ClosedRequest
 		if err := publishUseCase.repo.Update(ctx, pubReq); err != nil {
-			publishUseCase.logger.Error("PUBREQ_UC TRANSACTION Apply", "req", pubReq.RequestID, slog.Any("error", err))
+			publishUseCase.logger.ErrorContext(ctx, "PUBREQ_UC TRANSACTION Apply", "req", pubReq.RequestID, slog.Any("error", err))
 			return fmt.Errorf("can't update request.go with err %w", err)
 		}
 
-		publishUseCase.logger.Debug("PUBREQ_UC Accept", "req", pubReq.RequestID)
+		now := time.Now().UTC()
+		err := publishUseCase.eventPublisher.Publish(ctx,
+			&events.PublicationCreated{
+				PublicationID: publication.PublicationID,
+				ReleaseID:     publication.ReleaseID,
+				ManagerID:     publication.ManagerID,
+				Date:          publication.Date,
+				OccurredAt:    now,
+			},
+			&events.RequestAccepted{
+				RequestID:  pubReq.RequestID,
+				Type:       pubReq.Type,
+				ManagerID:  pubReq.ManagerID,
+				OccurredAt: now,
+			})
+		if err != nil {
+			publishUseCase.logger.ErrorContext(ctx, "PUBREQ_UC TRANSACTION Accept", "req", pubReq.RequestID, slog.Any("error", err))
+			return err
+		}
+
+		publishUseCase.logger.DebugContext(ctx, "PUBREQ_UC Accept", "req", pubReq.RequestID)
 
 		return nil
 	})
 }
 
-func (publishUseCase *PublishRequestUseCase) Decline(request base.IRequest) error {
+func (publishUseCase *PublishRequestUseCase) Decline(request base.IRequest) (err error) {
 
 	if err := request.Validate(publish.PubReq); err != nil {
 		return err
@@ -170,9 +225,26 @@ func (publishUseCase *PublishRequestUseCase) Decline(request base.IRequest) erro
 	pubReq.Status = base.ClosedRequest
 	pubReq.Description = base.DescrDeclinedRequest
 
-	publishUseCase.logger.Debug("PUBREQ_UC Decline", "req", pubReq.RequestID)
+	ctx, span := tracing.Start(tracing.EnsureCorrelationID(context.Background()), "PublishRequestUseCase.Decline",
+		attribute.Int64("request.id", int64(pubReq.RequestID)),
+		attribute.Int64("request.manager_id", int64(pubReq.ManagerID)))
+	defer func() { tracing.End(span, err) }()
+
+	publishUseCase.logger.DebugContext(ctx, "PUBREQ_UC Decline", "req", pubReq.RequestID)
+
+	return publishUseCase.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := publishUseCase.repo.Update(ctx, pubReq); err != nil {
+			return err
+		}
 
-	return publishUseCase.repo.Update(context.Background(), pubReq)
+		return publishUseCase.eventPublisher.Publish(ctx, &events.RequestDeclined{
+			RequestID:  pubReq.RequestID,
+			Type:       pubReq.Type,
+			ManagerID:  pubReq.ManagerID,
+			Reason:     pubReq.Description,
+			OccurredAt: time.Now().UTC(),
+		})
+	})
 }
 
 func (publishUseCase *PublishRequestUseCase) Get(id uint64) (*publish.PublishRequest, error) {
@@ -184,17 +256,21 @@ func (publishUseCase *PublishRequestUseCase) Get(id uint64) (*publish.PublishReq
 	return req, nil
 }
 
-func (publishUseCase *PublishRequestUseCase) sendProceedToManagerMSG(pubReq *publish.PublishRequest) error {
-
-	msg, err := broker_dto.NewPublishRequestProducerMsg(publish_req_broker.PublishRequestProceedToManager, pubReq)
+// storeProceedToManagerMSG writes message to outbox, so it is sent only if the request is stored
+func (publishUseCase *PublishRequestUseCase) storeProceedToManagerMSG(ctx context.Context, pubReq *publish.PublishRequest) error {
+	msg, err := broker_dto.NewPublishRequestProducerMsg(ctx, publish_req_broker.PublishRequestProceedToManager, pubReq)
 	if err != nil {
 		return fmt.Errorf("can't apply publish request: can't proceed to manager with err %w", err)
 	}
 
-	_, _, err = publishUseCase.broker.SendMessage(msg)
+	outboxMsg, err := outbox.NewOutboxMessage(msg)
 	if err != nil {
 		return fmt.Errorf("can't apply publish request: can't proceed to manager with err %w", err)
 	}
 
+	if err := publishUseCase.outboxRepo.Create(ctx, outboxMsg); err != nil {
+		return fmt.Errorf("can't apply publish request: can't proceed to manager with err %w", err)
+	}
+
 	return nil
 }
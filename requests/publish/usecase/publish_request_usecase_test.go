@@ -1,17 +1,21 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"log/slog"
 	"testing"
 
 	cdtime "github.com/rauzh/cd-core/time"
 
+	"github.com/rauzh/cd-core/genre"
 	rlsService "github.com/rauzh/cd-core/release/service"
 	"github.com/rauzh/cd-core/repo/mocks"
 	"github.com/rauzh/cd-core/requests/base"
 	base_errors "github.com/rauzh/cd-core/requests/base/errors"
-	broker_mocks "github.com/rauzh/cd-core/requests/broker/mocks"
+	"github.com/rauzh/cd-core/requests/broker/events"
+	"github.com/rauzh/cd-core/requests/broker/outbox"
+	outboxRepoMocks "github.com/rauzh/cd-core/requests/broker/outbox/repo/mocks"
 	"github.com/rauzh/cd-core/requests/publish"
 	pubReqErrors "github.com/rauzh/cd-core/requests/publish/errors"
 	publishReqRepoMocks "github.com/rauzh/cd-core/requests/publish/repo/mocks"
@@ -31,7 +35,7 @@ type _depFields struct {
 	releaseRepo     *mocks.ReleaseRepo
 	artistRepo      *mocks.ArtistRepo
 	transactor      *transacMock.Transactor
-	pbBroker        *broker_mocks.IBroker
+	outboxRepo      *outboxRepoMocks.OutboxRepo
 
 	publishRepo *publishReqRepoMocks.PublishRequestRepo
 }
@@ -47,11 +51,12 @@ func _newMockPublishReqDepFields(t *testing.T) *_depFields {
 
 	statMockFetcher := statFetcher.NewStatFetcher(t)
 
-	trkSvc := trackService.NewTrackService(trkMockRepo, slog.Default())
-	rlsSvc := rlsService.NewReleaseService(trkSvc, transactionMock, rlsMockRepo, slog.Default())
-	statSvc := statService.NewStatisticsService(trkSvc, statMockFetcher, statMockRepo, rlsSvc, slog.Default())
+	taxonomy, _ := genre.DefaultTaxonomy()
+	trkSvc := trackService.NewTrackService(trkMockRepo, taxonomy, slog.Default())
+	rlsSvc := rlsService.NewReleaseService(trkSvc, transactionMock, rlsMockRepo, taxonomy, slog.Default())
+	statSvc := statService.NewStatisticsService(trkSvc, statMockFetcher, statMockRepo, rlsSvc, taxonomy, slog.Default())
 
-	mockBroker := broker_mocks.NewIBroker(t)
+	mockOutboxRepo := outboxRepoMocks.NewOutboxRepo(t)
 
 	f := &_depFields{
 		_statRepo:       statMockRepo,
@@ -61,7 +66,7 @@ func _newMockPublishReqDepFields(t *testing.T) *_depFields {
 		releaseRepo:     rlsMockRepo,
 		artistRepo:      artistMockRepo,
 		transactor:      transactionMock,
-		pbBroker:        mockBroker,
+		outboxRepo:      mockOutboxRepo,
 		publishRepo:     publishMockRepo,
 	}
 
@@ -103,7 +108,17 @@ func TestPublishRequestUseCase_Decline(t *testing.T) {
 			out: nil,
 			dependencies: func(df *_depFields) {
 
-				df.publishRepo.EXPECT().Update(mock.AnythingOfType("context.backgroundCtx"), &publish.PublishRequest{
+				df.transactor.EXPECT().WithinTransaction(mock.AnythingOfType("*context.valueCtx"), mock.Anything).RunAndReturn(
+					func(ctx context.Context, fn func(context.Context) error) error {
+						return fn(ctx)
+					}).Once()
+
+				df.outboxRepo.EXPECT().Create(mock.AnythingOfType("*context.valueCtx"), mock.MatchedBy(
+					func(msg *outbox.OutboxMessage) bool {
+						return msg.Topic == events.RequestDeclinedTopic
+					})).Return(nil).Once()
+
+				df.publishRepo.EXPECT().Update(mock.AnythingOfType("*context.valueCtx"), &publish.PublishRequest{
 					Request: base.Request{
 						RequestID: 1,
 						Type:      publish.PubReq,
@@ -158,7 +173,7 @@ func TestPublishRequestUseCase_Decline(t *testing.T) {
 				tt.dependencies(f)
 			}
 
-			publishReqUseCase, err := NewPublishRequestUseCase(f.statService, f.publicationRepo, f.releaseRepo, f.artistRepo, f.transactor, f.pbBroker, f.publishRepo, slog.Default())
+			publishReqUseCase, err := NewPublishRequestUseCase(f.statService, f.publicationRepo, f.releaseRepo, f.artistRepo, f.transactor, f.outboxRepo, f.publishRepo, slog.Default())
 
 			// act
 			err = publishReqUseCase.Decline(tt.in.pubReq)
@@ -208,7 +223,7 @@ func TestPublishRequestUseCase_Accept(t *testing.T) {
 			},
 			out: nil,
 			dependencies: func(df *_depFields) {
-				df.transactor.EXPECT().WithinTransaction(mock.AnythingOfType("context.backgroundCtx"),
+				df.transactor.EXPECT().WithinTransaction(mock.AnythingOfType("*context.valueCtx"),
 					mock.Anything).Return(nil).Once()
 			},
 			assert: func(t *testing.T, df *_depFields) {
@@ -276,7 +291,7 @@ func TestPublishRequestUseCase_Accept(t *testing.T) {
 				tt.dependencies(f)
 			}
 
-			publishReqUseCase, err := NewPublishRequestUseCase(f.statService, f.publicationRepo, f.releaseRepo, f.artistRepo, f.transactor, f.pbBroker, f.publishRepo, slog.Default())
+			publishReqUseCase, err := NewPublishRequestUseCase(f.statService, f.publicationRepo, f.releaseRepo, f.artistRepo, f.transactor, f.outboxRepo, f.publishRepo, slog.Default())
 
 			// act
 			err = publishReqUseCase.Accept(tt.in.pubReq)
@@ -327,7 +342,7 @@ func TestPublishRequestUseCase_Apply(t *testing.T) {
 		//	out: nil,
 		//	dependencies: func(df *_depFields) {
 		//
-		//		df.pbBroker.EXPECT().SendMessage(mock.Anything).Return(0, 0, nil)
+		//		df.outboxRepo.EXPECT().Create(mock.Anything, mock.Anything).Return(nil)
 		//
 		//		df.publishRepo.EXPECT().Create(mock.AnythingOfType("context.backgroundCtx"), &publish.PublishRequest{
 		//			Request: base.Request{
@@ -383,7 +398,7 @@ func TestPublishRequestUseCase_Apply(t *testing.T) {
 				tt.dependencies(f)
 			}
 
-			publishReqUseCase, err := NewPublishRequestUseCase(f.statService, f.publicationRepo, f.releaseRepo, f.artistRepo, f.transactor, f.pbBroker, f.publishRepo, slog.Default())
+			publishReqUseCase, err := NewPublishRequestUseCase(f.statService, f.publicationRepo, f.releaseRepo, f.artistRepo, f.transactor, f.outboxRepo, f.publishRepo, slog.Default())
 
 			// act
 			err = publishReqUseCase.Apply(tt.in.pubReq)
@@ -129,6 +129,65 @@ func (_c *SignContractRequestRepo_Get_Call) RunAndReturn(run func(context.Contex
 	return _c
 }
 
+// GetAllByApplierID provides a mock function with given fields: ctx, applierID
+func (_m *SignContractRequestRepo) GetAllByApplierID(ctx context.Context, applierID uint64) ([]sign_contract.SignContractRequest, error) {
+	ret := _m.Called(ctx, applierID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllByApplierID")
+	}
+
+	var r0 []sign_contract.SignContractRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) ([]sign_contract.SignContractRequest, error)); ok {
+		return rf(ctx, applierID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) []sign_contract.SignContractRequest); ok {
+		r0 = rf(ctx, applierID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sign_contract.SignContractRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, applierID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SignContractRequestRepo_GetAllByApplierID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllByApplierID'
+type SignContractRequestRepo_GetAllByApplierID_Call struct {
+	*mock.Call
+}
+
+// GetAllByApplierID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - applierID uint64
+func (_e *SignContractRequestRepo_Expecter) GetAllByApplierID(ctx interface{}, applierID interface{}) *SignContractRequestRepo_GetAllByApplierID_Call {
+	return &SignContractRequestRepo_GetAllByApplierID_Call{Call: _e.mock.On("GetAllByApplierID", ctx, applierID)}
+}
+
+func (_c *SignContractRequestRepo_GetAllByApplierID_Call) Run(run func(ctx context.Context, applierID uint64)) *SignContractRequestRepo_GetAllByApplierID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64))
+	})
+	return _c
+}
+
+func (_c *SignContractRequestRepo_GetAllByApplierID_Call) Return(_a0 []sign_contract.SignContractRequest, _a1 error) *SignContractRequestRepo_GetAllByApplierID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *SignContractRequestRepo_GetAllByApplierID_Call) RunAndReturn(run func(context.Context, uint64) ([]sign_contract.SignContractRequest, error)) *SignContractRequestRepo_GetAllByApplierID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SetMeta provides a mock function with given fields: _a0, _a1
 func (_m *SignContractRequestRepo) SetMeta(_a0 context.Context, _a1 *sign_contract.SignContractRequest) error {
 	ret := _m.Called(_a0, _a1)
@@ -10,6 +10,7 @@ import (
 type SignContractRequestRepo interface {
 	Create(context.Context, *sign_contract.SignContractRequest) error
 	Get(ctx context.Context, id uint64) (*sign_contract.SignContractRequest, error)
+	GetAllByApplierID(ctx context.Context, applierID uint64) ([]sign_contract.SignContractRequest, error)
 	Update(context.Context, *sign_contract.SignContractRequest) error
 	SetMeta(context.Context, *sign_contract.SignContractRequest) error
 }
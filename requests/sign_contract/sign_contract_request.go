@@ -1,6 +1,8 @@
 package sign_contract
 
 import (
+	"strings"
+
 	"github.com/rauzh/cd-core/requests/base"
 	sctErrors "github.com/rauzh/cd-core/requests/sign_contract/errors"
 )
@@ -10,6 +12,7 @@ const SignRequest base.RequestType = "Sign"
 type SignContractRequest struct {
 	base.Request
 	Nickname    string
+	Grade       int
 	Description string
 }
 
@@ -45,6 +48,10 @@ func (scReq *SignContractRequest) Validate(reqType base.RequestType) error {
 	return nil
 }
 
+func (scReq *SignContractRequest) IsDeclined() bool {
+	return scReq.Status == base.ClosedRequest && strings.HasPrefix(scReq.Description, base.DescrDeclinedRequest)
+}
+
 func (scReq *SignContractRequest) GetType() base.RequestType {
 	return scReq.Type
 }
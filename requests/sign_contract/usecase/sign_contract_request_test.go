@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"log/slog"
 	"testing"
@@ -10,7 +11,10 @@ import (
 	"github.com/rauzh/cd-core/repo/mocks"
 	"github.com/rauzh/cd-core/requests/base"
 	base_errors "github.com/rauzh/cd-core/requests/base/errors"
-	broker_mocks "github.com/rauzh/cd-core/requests/broker/mocks"
+	"github.com/rauzh/cd-core/requests/broker/events"
+	"github.com/rauzh/cd-core/requests/broker/outbox"
+	outboxRepoMocks "github.com/rauzh/cd-core/requests/broker/outbox/repo/mocks"
+	signContractBroker "github.com/rauzh/cd-core/requests/broker/sign_contract"
 	"github.com/rauzh/cd-core/requests/sign_contract"
 	sctErrors "github.com/rauzh/cd-core/requests/sign_contract/errors"
 	signReqRepoMocks "github.com/rauzh/cd-core/requests/sign_contract/repo/mocks"
@@ -23,7 +27,7 @@ type _depFields struct {
 	userRepo   *mocks.UserRepo
 
 	transactor *transacMock.Transactor
-	scBroker   *broker_mocks.IBroker
+	outboxRepo *outboxRepoMocks.OutboxRepo
 
 	signReqRepo *signReqRepoMocks.SignContractRequestRepo
 }
@@ -33,7 +37,7 @@ var dberr = errors.New("db err")
 func _newMockSignReqDepFields(t *testing.T) *_depFields {
 
 	transactionMock := transacMock.NewTransactor(t)
-	mockBroker := broker_mocks.NewIBroker(t)
+	mockOutboxRepo := outboxRepoMocks.NewOutboxRepo(t)
 
 	mockArtRepo := mocks.NewArtistRepo(t)
 	mockUserRepo := mocks.NewUserRepo(t)
@@ -44,7 +48,7 @@ func _newMockSignReqDepFields(t *testing.T) *_depFields {
 		artistRepo:  mockArtRepo,
 		userRepo:    mockUserRepo,
 		transactor:  transactionMock,
-		scBroker:    mockBroker,
+		outboxRepo:  mockOutboxRepo,
 		signReqRepo: mockSignReqRepo,
 	}
 
@@ -84,7 +88,17 @@ func TestSignContractRequestUseCase_Decline(t *testing.T) {
 			out: nil,
 			dependencies: func(df *_depFields) {
 
-				df.signReqRepo.EXPECT().Update(mock.AnythingOfType("context.backgroundCtx"), &sign_contract.SignContractRequest{
+				df.transactor.EXPECT().WithinTransaction(mock.AnythingOfType("*context.valueCtx"), mock.Anything).RunAndReturn(
+					func(ctx context.Context, fn func(context.Context) error) error {
+						return fn(ctx)
+					}).Once()
+
+				df.outboxRepo.EXPECT().Create(mock.AnythingOfType("*context.valueCtx"), mock.MatchedBy(
+					func(msg *outbox.OutboxMessage) bool {
+						return msg.Topic == events.RequestDeclinedTopic
+					})).Return(nil).Once()
+
+				df.signReqRepo.EXPECT().Update(mock.AnythingOfType("*context.valueCtx"), &sign_contract.SignContractRequest{
 					Request: base.Request{
 						RequestID: 1,
 						Type:      sign_contract.SignRequest,
@@ -134,7 +148,7 @@ func TestSignContractRequestUseCase_Decline(t *testing.T) {
 				tt.dependencies(f)
 			}
 
-			signReqUseCase, err := NewSignContractRequestUseCase(f.userRepo, f.artistRepo, f.transactor, f.scBroker, f.signReqRepo, slog.Default())
+			signReqUseCase, err := NewSignContractRequestUseCase(f.userRepo, f.artistRepo, f.transactor, f.outboxRepo, f.signReqRepo, slog.Default())
 
 			// act
 			err = signReqUseCase.Decline(tt.in.signReq)
@@ -182,7 +196,7 @@ func TestSignContractRequestUseCase_Accept(t *testing.T) {
 			},
 			out: nil,
 			dependencies: func(df *_depFields) {
-				df.transactor.EXPECT().WithinTransaction(mock.AnythingOfType("context.backgroundCtx"),
+				df.transactor.EXPECT().WithinTransaction(mock.AnythingOfType("*context.valueCtx"),
 					mock.Anything).Return(nil).Once()
 			},
 			assert: func(t *testing.T, df *_depFields) {
@@ -245,7 +259,7 @@ func TestSignContractRequestUseCase_Accept(t *testing.T) {
 				tt.dependencies(f)
 			}
 
-			signReqUseCase, err := NewSignContractRequestUseCase(f.userRepo, f.artistRepo, f.transactor, f.scBroker, f.signReqRepo, slog.Default())
+			signReqUseCase, err := NewSignContractRequestUseCase(f.userRepo, f.artistRepo, f.transactor, f.outboxRepo, f.signReqRepo, slog.Default())
 
 			// act
 			err = signReqUseCase.Accept(tt.in.signReq)
@@ -293,9 +307,22 @@ func TestSignContractRequestUseCase_Apply(t *testing.T) {
 			out: nil,
 			dependencies: func(df *_depFields) {
 
-				df.scBroker.EXPECT().SendMessage(mock.Anything).Return(0, 0, nil)
+				df.transactor.EXPECT().WithinTransaction(mock.AnythingOfType("*context.valueCtx"), mock.Anything).RunAndReturn(
+					func(ctx context.Context, fn func(context.Context) error) error {
+						return fn(ctx)
+					}).Once()
+
+				df.outboxRepo.EXPECT().Create(mock.AnythingOfType("*context.valueCtx"), mock.MatchedBy(
+					func(msg *outbox.OutboxMessage) bool {
+						return msg.Topic == signContractBroker.SignRequestProceedToManager
+					})).Return(nil).Once()
 
-				df.signReqRepo.EXPECT().Create(mock.AnythingOfType("context.backgroundCtx"), &sign_contract.SignContractRequest{
+				df.outboxRepo.EXPECT().Create(mock.AnythingOfType("*context.valueCtx"), mock.MatchedBy(
+					func(msg *outbox.OutboxMessage) bool {
+						return msg.Topic == events.RequestAppliedTopic
+					})).Return(nil).Once()
+
+				df.signReqRepo.EXPECT().Create(mock.AnythingOfType("*context.valueCtx"), &sign_contract.SignContractRequest{
 					Request: base.Request{
 						RequestID: 1,
 						Type:      sign_contract.SignRequest,
@@ -330,7 +357,12 @@ func TestSignContractRequestUseCase_Apply(t *testing.T) {
 			out: dberr,
 			dependencies: func(df *_depFields) {
 
-				df.signReqRepo.EXPECT().Create(mock.AnythingOfType("context.backgroundCtx"), &sign_contract.SignContractRequest{
+				df.transactor.EXPECT().WithinTransaction(mock.AnythingOfType("*context.valueCtx"), mock.Anything).RunAndReturn(
+					func(ctx context.Context, fn func(context.Context) error) error {
+						return fn(ctx)
+					}).Once()
+
+				df.signReqRepo.EXPECT().Create(mock.AnythingOfType("*context.valueCtx"), &sign_contract.SignContractRequest{
 					Request: base.Request{
 						RequestID: 1,
 						Type:      sign_contract.SignRequest,
@@ -357,7 +389,7 @@ func TestSignContractRequestUseCase_Apply(t *testing.T) {
 				tt.dependencies(f)
 			}
 
-			signReqUseCase, err := NewSignContractRequestUseCase(f.userRepo, f.artistRepo, f.transactor, f.scBroker, f.signReqRepo, slog.Default())
+			signReqUseCase, err := NewSignContractRequestUseCase(f.userRepo, f.artistRepo, f.transactor, f.outboxRepo, f.signReqRepo, slog.Default())
 
 			// act
 			err = signReqUseCase.Apply(tt.in.signReq)
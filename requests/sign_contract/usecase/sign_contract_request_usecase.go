@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"time"
 
 	cdtime "github.com/rauzh/cd-core/time"
 
@@ -11,19 +12,24 @@ import (
 
 	repo "github.com/rauzh/cd-core/repo"
 	"github.com/rauzh/cd-core/requests/base"
-	"github.com/rauzh/cd-core/requests/broker"
 	"github.com/rauzh/cd-core/requests/broker/broker_dto"
+	"github.com/rauzh/cd-core/requests/broker/events"
+	"github.com/rauzh/cd-core/requests/broker/outbox"
+	outboxRepo "github.com/rauzh/cd-core/requests/broker/outbox/repo"
 	signContractBroker "github.com/rauzh/cd-core/requests/broker/sign_contract"
+	"github.com/rauzh/cd-core/requests/broker/tracing"
 	"github.com/rauzh/cd-core/requests/sign_contract"
 	signContractRepo "github.com/rauzh/cd-core/requests/sign_contract/repo"
 	"github.com/rauzh/cd-core/transactor"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type SignContractRequestUseCase struct {
-	userRepo   repo.UserRepo
-	artistRepo repo.ArtistRepo
-	transactor transactor.Transactor
-	scBroker   broker.IBroker
+	userRepo       repo.UserRepo
+	artistRepo     repo.ArtistRepo
+	transactor     transactor.Transactor
+	outboxRepo     outboxRepo.OutboxRepo
+	eventPublisher events.IEventPublisher
 
 	repo signContractRepo.SignContractRequestRepo
 
@@ -34,24 +40,25 @@ func NewSignContractRequestUseCase(
 	usrRepo repo.UserRepo,
 	artRepo repo.ArtistRepo,
 	transactor transactor.Transactor,
-	scBroker broker.IBroker,
+	outboxRepo outboxRepo.OutboxRepo,
 	repo signContractRepo.SignContractRequestRepo,
 	logger *slog.Logger,
 ) (base.IRequestUseCase, error) {
 
 	sctUseCase := &SignContractRequestUseCase{
-		userRepo:   usrRepo,
-		artistRepo: artRepo,
-		repo:       repo,
-		transactor: transactor,
-		scBroker:   scBroker,
-		logger:     logger,
+		userRepo:       usrRepo,
+		artistRepo:     artRepo,
+		repo:           repo,
+		transactor:     transactor,
+		outboxRepo:     outboxRepo,
+		eventPublisher: events.NewOutboxPublisher(outboxRepo),
+		logger:         logger,
 	}
 
 	return sctUseCase, nil
 }
 
-func (sctUseCase *SignContractRequestUseCase) Apply(request base.IRequest) error {
+func (sctUseCase *SignContractRequestUseCase) Apply(request base.IRequest) (err error) {
 
 	if err := request.Validate(sign_contract.SignRequest); err != nil {
 		return err
@@ -60,20 +67,45 @@ func (sctUseCase *SignContractRequestUseCase) Apply(request base.IRequest) error
 
 	base.InitDateStatus(&signReq.Request)
 
-	if err := sctUseCase.repo.Create(context.Background(), signReq); err != nil {
-		return fmt.Errorf("can't apply sign contract request with err %w", err)
-	}
+	ctx, span := tracing.Start(tracing.EnsureCorrelationID(context.Background()), "SignContractRequestUseCase.Apply",
+		attribute.Int64("request.applier_id", int64(signReq.ApplierID)))
+	defer func() { tracing.End(span, err) }()
+
+	err = sctUseCase.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := sctUseCase.repo.Create(ctx, signReq); err != nil {
+			sctUseCase.logger.ErrorContext(ctx, "SIGNREQ_UC TRANSACTION Apply", slog.Any("error", err))
+			return fmt.Errorf("can't apply sign contract request with err %w", err)
+		}
+
+		if err := sctUseCase.storeProceedToManagerMSG(ctx, signReq); err != nil {
+			sctUseCase.logger.ErrorContext(ctx, "SIGNREQ_UC TRANSACTION Apply", "req", signReq.RequestID, slog.Any("error", err))
+			return err
+		}
+
+		err := sctUseCase.eventPublisher.Publish(ctx, &events.RequestApplied{
+			RequestID:  signReq.RequestID,
+			Type:       signReq.Type,
+			ApplierID:  signReq.ApplierID,
+			OccurredAt: time.Now().UTC(),
+		})
+		if err != nil {
+			sctUseCase.logger.ErrorContext(ctx, "SIGNREQ_UC TRANSACTION Apply", "req", signReq.RequestID, slog.Any("error", err))
+			return err
+		}
 
-	if err := sctUseCase.sendProceedToManagerMSG(signReq); err != nil {
+		return nil
+	})
+	if err != nil {
 		return err
 	}
 
-	sctUseCase.logger.Info("SIGNREQ_UC Apply", "req", signReq.RequestID)
+	span.SetAttributes(attribute.Int64("request.id", int64(signReq.RequestID)))
+	sctUseCase.logger.InfoContext(ctx, "SIGNREQ_UC Apply", "req", signReq.RequestID)
 
 	return nil
 }
 
-func (sctUseCase *SignContractRequestUseCase) Accept(request base.IRequest) error {
+func (sctUseCase *SignContractRequestUseCase) Accept(request base.IRequest) (err error) {
 
 	if err := request.Validate(sign_contract.SignRequest); err != nil {
 		return err
@@ -88,30 +120,55 @@ func (sctUseCase *SignContractRequestUseCase) Accept(request base.IRequest) erro
 		ManagerID:    signReq.ManagerID,
 	}
 
-	ctx := context.Background()
+	ctx, span := tracing.Start(tracing.EnsureCorrelationID(context.Background()), "SignContractRequestUseCase.Accept",
+		attribute.Int64("request.id", int64(signReq.RequestID)),
+		attribute.Int64("request.manager_id", int64(signReq.ManagerID)))
+	defer func() { tracing.End(span, err) }()
+
 	return sctUseCase.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
 		if err := sctUseCase.userRepo.UpdateType(ctx, artist.UserID, models.ArtistUser); err != nil {
-			sctUseCase.logger.Error("SIGNREQ_UC TRANSACTION Accept", "req", signReq.RequestID, slog.Any("error", err))
+			sctUseCase.logger.ErrorContext(ctx, "SIGNREQ_UC TRANSACTION Accept", "req", signReq.RequestID, slog.Any("error", err))
 			return fmt.Errorf("can't update user with err %w", err)
 		}
 
 		if err := sctUseCase.artistRepo.Create(ctx, &artist); err != nil {
-			sctUseCase.logger.Error("SIGNREQ_UC TRANSACTION Accept", "req", signReq.RequestID, slog.Any("error", err))
+			sctUseCase.logger.ErrorContext(ctx, "SIGNREQ_UC TRANSACTION Accept", "req", signReq.RequestID, slog.Any("error", err))
 			return fmt.Errorf("can't create artist %s with err %w", artist.Nickname, err)
 		}
 
 		signReq.Status = base.ClosedRequest
 		if err := sctUseCase.repo.Update(ctx, signReq); err != nil {
-			sctUseCase.logger.Error("SIGNREQ_UC TRANSACTION Accept", "req", signReq.RequestID, slog.Any("error", err))
+			sctUseCase.logger.ErrorContext(ctx, "SIGNREQ_UC TRANSACTION Accept", "req", signReq.RequestID, slog.Any("error", err))
 			return fmt.Errorf("can't update reqiest with err %w", err)
 		}
 
-		sctUseCase.logger.Debug("SIGNREQ_UC Accept", "req", signReq.RequestID)
+		now := time.Now().UTC()
+		err := sctUseCase.eventPublisher.Publish(ctx,
+			&events.ArtistSigned{
+				ArtistID:     artist.ArtistID,
+				UserID:       artist.UserID,
+				Nickname:     artist.Nickname,
+				ManagerID:    artist.ManagerID,
+				ContractTerm: artist.ContractTerm,
+				OccurredAt:   now,
+			},
+			&events.RequestAccepted{
+				RequestID:  signReq.RequestID,
+				Type:       signReq.Type,
+				ManagerID:  signReq.ManagerID,
+				OccurredAt: now,
+			})
+		if err != nil {
+			sctUseCase.logger.ErrorContext(ctx, "SIGNREQ_UC TRANSACTION Accept", "req", signReq.RequestID, slog.Any("error", err))
+			return err
+		}
+
+		sctUseCase.logger.DebugContext(ctx, "SIGNREQ_UC Accept", "req", signReq.RequestID)
 		return nil
 	})
 }
 
-func (sctUseCase *SignContractRequestUseCase) Decline(request base.IRequest) error {
+func (sctUseCase *SignContractRequestUseCase) Decline(request base.IRequest) (err error) {
 
 	if err := request.Validate(sign_contract.SignRequest); err != nil {
 		return err
@@ -121,9 +178,26 @@ func (sctUseCase *SignContractRequestUseCase) Decline(request base.IRequest) err
 	signReq.Status = base.ClosedRequest
 	signReq.Description = base.DescrDeclinedRequest
 
-	sctUseCase.logger.Debug("SIGNREQ_UC Decline", "req", signReq.RequestID)
+	ctx, span := tracing.Start(tracing.EnsureCorrelationID(context.Background()), "SignContractRequestUseCase.Decline",
+		attribute.Int64("request.id", int64(signReq.RequestID)),
+		attribute.Int64("request.manager_id", int64(signReq.ManagerID)))
+	defer func() { tracing.End(span, err) }()
+
+	sctUseCase.logger.DebugContext(ctx, "SIGNREQ_UC Decline", "req", signReq.RequestID)
+
+	return sctUseCase.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := sctUseCase.repo.Update(ctx, signReq); err != nil {
+			return err
+		}
 
-	return sctUseCase.repo.Update(context.Background(), signReq)
+		return sctUseCase.eventPublisher.Publish(ctx, &events.RequestDeclined{
+			RequestID:  signReq.RequestID,
+			Type:       signReq.Type,
+			ManagerID:  signReq.ManagerID,
+			Reason:     signReq.Description,
+			OccurredAt: time.Now().UTC(),
+		})
+	})
 }
 
 func (sctUseCase *SignContractRequestUseCase) Get(id uint64) (*sign_contract.SignContractRequest, error) {
@@ -136,17 +210,21 @@ func (sctUseCase *SignContractRequestUseCase) Get(id uint64) (*sign_contract.Sig
 	return req, nil
 }
 
-func (sctUseCase *SignContractRequestUseCase) sendProceedToManagerMSG(signReq *sign_contract.SignContractRequest) error {
-
-	msg, err := broker_dto.NewSignRequestProducerMsg(signContractBroker.SignRequestProceedToManager, signReq)
+// storeProceedToManagerMSG writes message to outbox, so it is sent only if the request is stored
+func (sctUseCase *SignContractRequestUseCase) storeProceedToManagerMSG(ctx context.Context, signReq *sign_contract.SignContractRequest) error {
+	msg, err := broker_dto.NewSignRequestProducerMsg(ctx, signContractBroker.SignRequestProceedToManager, signReq)
 	if err != nil {
 		return fmt.Errorf("can't apply sign contract request: can't proceed to manager with err %w", err)
 	}
 
-	_, _, err = sctUseCase.scBroker.SendMessage(msg)
+	outboxMsg, err := outbox.NewOutboxMessage(msg)
 	if err != nil {
 		return fmt.Errorf("can't apply sign contract request: can't proceed to manager with err %w", err)
 	}
 
+	if err := sctUseCase.outboxRepo.Create(ctx, outboxMsg); err != nil {
+		return fmt.Errorf("can't apply sign contract request: can't proceed to manager with err %w", err)
+	}
+
 	return nil
 }
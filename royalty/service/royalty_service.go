@@ -0,0 +1,411 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/rauzh/cd-core/models"
+	"github.com/rauzh/cd-core/repo"
+	"github.com/rauzh/cd-core/statistics/convert"
+	cdtime "github.com/rauzh/cd-core/time"
+	"github.com/rauzh/cd-core/transactor"
+)
+
+var (
+	ErrBadPeriod     = errors.New("bad royalty period")
+	ErrPeriodNotOver = errors.New("royalty period is not over yet")
+	ErrPeriodClosed  = errors.New("royalty period overlaps closed one")
+	ErrPeriodGap     = errors.New("royalty period doesn't follow closed one")
+	ErrBadRate       = errors.New("bad stream rate")
+	ErrNoRate        = errors.New("no stream rate")
+	ErrBadShares     = errors.New("bad royalty shares")
+	ErrNoContract    = errors.New("no royalty contract")
+)
+
+type IRoyaltyService interface {
+	SetRate(rate *models.StreamRate) error
+	SetContract(contract *models.RoyaltyContract) error
+	// ClosePeriod computes amounts of all published tracks for the period and appends them to the ledger.
+	// Periods follow each other without gaps, from is the day after the last closed period
+	// unless no period is closed yet. A closed period is never computed again
+	ClosePeriod(from, to time.Time) ([]models.RoyaltyLedgerEntry, error)
+	// Statement lists ledger entries of the artist, both of own and featured tracks
+	Statement(artistID uint64, from, to time.Time) (*models.RoyaltyStatement, error)
+}
+
+type RoyaltyService struct {
+	rateRepo     repo.StreamRateRepo
+	contractRepo repo.RoyaltyContractRepo
+	ledgerRepo   repo.RoyaltyLedgerRepo
+	statRepo     repo.StatisticsRepo
+	pbcRepo      repo.PublicationRepo
+	releaseRepo  repo.ReleaseRepo
+	trackRepo    repo.TrackRepo
+	transactor   transactor.Transactor
+
+	logger *slog.Logger
+}
+
+func NewRoyaltyService(
+	rateRepo repo.StreamRateRepo,
+	contractRepo repo.RoyaltyContractRepo,
+	ledgerRepo repo.RoyaltyLedgerRepo,
+	statRepo repo.StatisticsRepo,
+	pbcRepo repo.PublicationRepo,
+	releaseRepo repo.ReleaseRepo,
+	trackRepo repo.TrackRepo,
+	transactor transactor.Transactor,
+	logger *slog.Logger) IRoyaltyService {
+	return &RoyaltyService{
+		rateRepo:     rateRepo,
+		contractRepo: contractRepo,
+		ledgerRepo:   ledgerRepo,
+		statRepo:     statRepo,
+		pbcRepo:      pbcRepo,
+		releaseRepo:  releaseRepo,
+		trackRepo:    trackRepo,
+		transactor:   transactor,
+		logger:       logger,
+	}
+}
+
+func (rltSvc *RoyaltyService) SetRate(rate *models.StreamRate) error {
+
+	if rate.Platform == "" || len(rate.Currency) != 3 || rate.ValidFrom.IsZero() {
+		return fmt.Errorf("%w: platform, currency and valid from are required", ErrBadRate)
+	}
+	rate.Country = models.NormalizeCountry(rate.Country)
+	rate.ValidFrom = cdtime.Day(rate.ValidFrom)
+
+	if err := rltSvc.rateRepo.Create(context.Background(), rate); err != nil {
+		rltSvc.logger.Error("ROYALTY_SERVICE SetRate", slog.Any("error", err))
+		return fmt.Errorf("can't create stream rate with err %w", err)
+	}
+
+	rltSvc.logger.Info("ROYALTY_SERVICE SetRate", "platform", rate.Platform, "country", rate.Country,
+		"micro_per_stream", rate.MicroPerStream, "valid_from", rate.ValidFrom)
+	return nil
+}
+
+func (rltSvc *RoyaltyService) SetContract(contract *models.RoyaltyContract) error {
+
+	if contract.LabelShare+contract.FeaturedShare > models.FullShare {
+		return fmt.Errorf("%w: label %d and featured %d are more than %d",
+			ErrBadShares, contract.LabelShare, contract.FeaturedShare, models.FullShare)
+	}
+	if contract.ValidFrom.IsZero() || (!contract.ValidTo.IsZero() && !contract.ValidTo.After(contract.ValidFrom)) {
+		return fmt.Errorf("%w: valid from %s to %s", ErrBadPeriod,
+			contract.ValidFrom.Format(time.DateOnly), contract.ValidTo.Format(time.DateOnly))
+	}
+
+	if err := rltSvc.contractRepo.Create(context.Background(), contract); err != nil {
+		rltSvc.logger.Error("ROYALTY_SERVICE SetContract", slog.Any("error", err))
+		return fmt.Errorf("can't create royalty contract with err %w", err)
+	}
+
+	rltSvc.logger.Info("ROYALTY_SERVICE SetContract", "artist_id", contract.ArtistID, "contract_id", contract.ContractID)
+	return nil
+}
+
+func (rltSvc *RoyaltyService) ClosePeriod(from, to time.Time) ([]models.RoyaltyLedgerEntry, error) {
+
+	from, to = cdtime.Day(from), cdtime.Day(to)
+	if from.IsZero() || from.After(to) {
+		return nil, fmt.Errorf("%w: from %s to %s", ErrBadPeriod, from.Format(time.DateOnly), to.Format(time.DateOnly))
+	}
+	if !to.Before(cdtime.GetToday()) {
+		return nil, fmt.Errorf("%w: %s", ErrPeriodNotOver, to.Format(time.DateOnly))
+	}
+
+	var entries []models.RoyaltyLedgerEntry
+	err := rltSvc.transactor.WithinTransaction(context.Background(), func(ctx context.Context) error {
+
+		lastEnd, err := rltSvc.ledgerRepo.GetLastPeriodEnd(ctx)
+		if err != nil {
+			return fmt.Errorf("can't get last royalty period with err %w", err)
+		}
+		if !lastEnd.IsZero() {
+			lastEnd = cdtime.Day(lastEnd)
+			if !from.After(lastEnd) {
+				return fmt.Errorf("%w: closed until %s", ErrPeriodClosed, lastEnd.Format(time.DateOnly))
+			}
+			if next := lastEnd.AddDate(0, 0, 1); !from.Equal(next) {
+				return fmt.Errorf("%w: next period starts on %s", ErrPeriodGap, next.Format(time.DateOnly))
+			}
+		}
+
+		if entries, err = rltSvc.compute(ctx, from, to); err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+
+		if err := rltSvc.ledgerRepo.Append(ctx, entries); err != nil {
+			return fmt.Errorf("can't append royalty ledger with err %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		rltSvc.logger.Error("ROYALTY_SERVICE ClosePeriod", "from", from, "to", to, slog.Any("error", err))
+		return nil, err
+	}
+
+	rltSvc.logger.Info("ROYALTY_SERVICE ClosePeriod", "from", from, "to", to, "entries", len(entries))
+
+	return entries, nil
+}
+
+func (rltSvc *RoyaltyService) Statement(artistID uint64, from, to time.Time) (*models.RoyaltyStatement, error) {
+
+	from, to = cdtime.Day(from), cdtime.Day(to)
+	if from.After(to) {
+		return nil, fmt.Errorf("%w: from %s to %s", ErrBadPeriod, from.Format(time.DateOnly), to.Format(time.DateOnly))
+	}
+
+	entries, err := rltSvc.ledgerRepo.GetForArtist(context.Background(), artistID, from, to)
+	if err != nil {
+		rltSvc.logger.Error("ROYALTY_SERVICE Statement", "artist_id", artistID, slog.Any("error", err))
+		return nil, fmt.Errorf("can't get royalty ledger with err %w", err)
+	}
+
+	statement := &models.RoyaltyStatement{
+		ArtistID: artistID,
+		From:     from,
+		To:       to,
+		Entries:  entries,
+		Totals:   make(map[string]int64),
+	}
+
+	micro := make(map[string]int64)
+	for _, entry := range entries {
+		micro[entry.Currency] += entry.AmountMicro
+	}
+	for currency, amount := range micro {
+		statement.Totals[currency] = amount / int64(models.MicroUnits)
+	}
+
+	return statement, nil
+}
+
+type revenueKey struct {
+	trackID    uint64
+	platform   string
+	currency   string
+	contractID uint64
+}
+
+type revenue struct {
+	streams uint64
+	micro   uint64
+}
+
+type trackInfo struct {
+	ownerID  uint64
+	featured []uint64
+}
+
+// compute sums up revenue of every track, platform and contract in micro units.
+// Entries keep exact micro amounts, statements round them. Remainders of a split go to the label
+func (rltSvc *RoyaltyService) compute(ctx context.Context, from, to time.Time) ([]models.RoyaltyLedgerEntry, error) {
+
+	tracks, err := rltSvc.publishedTracks(ctx, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(tracks) == 0 {
+		return nil, nil
+	}
+
+	trackIDs := make([]uint64, 0, len(tracks))
+	for trackID := range tracks {
+		trackIDs = append(trackIDs, trackID)
+	}
+	sort.Slice(trackIDs, func(i, j int) bool { return trackIDs[i] < trackIDs[j] })
+
+	rates, err := rltSvc.rateRepo.GetAllUntil(ctx, to)
+	if err != nil {
+		return nil, fmt.Errorf("can't get stream rates with err %w", err)
+	}
+
+	// streams gained before the first report of a track are paid too
+	deltas, err := convert.DeltasBetweenFromZero(ctx, rltSvc.statRepo, trackIDs, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("can't get stats with err %w", err)
+	}
+
+	contracts := make(map[uint64][]models.RoyaltyContract)
+	contractByID := make(map[uint64]models.RoyaltyContract)
+	revenues := make(map[revenueKey]*revenue)
+	keys := make([]revenueKey, 0)
+
+	for _, delta := range deltas {
+		if delta.Streams == 0 {
+			continue
+		}
+
+		rate, ok := rateFor(rates, delta.Platform, delta.Country, delta.Date)
+		if !ok {
+			return nil, fmt.Errorf("%w for %s in %q on %s", ErrNoRate, delta.Platform, delta.Country,
+				delta.Date.Format(time.DateOnly))
+		}
+
+		ownerID := tracks[delta.TrackID].ownerID
+		if _, ok := contracts[ownerID]; !ok {
+			if contracts[ownerID], err = rltSvc.contractRepo.GetForArtist(ctx, ownerID); err != nil {
+				return nil, fmt.Errorf("can't get royalty contracts with err %w", err)
+			}
+		}
+		contract, ok := contractFor(contracts[ownerID], delta.Date)
+		if !ok {
+			return nil, fmt.Errorf("%w for artist %d on %s", ErrNoContract, ownerID, delta.Date.Format(time.DateOnly))
+		}
+		contractByID[contract.ContractID] = contract
+
+		key := revenueKey{trackID: delta.TrackID, platform: delta.Platform, currency: rate.Currency, contractID: contract.ContractID}
+		if _, ok := revenues[key]; !ok {
+			revenues[key] = &revenue{}
+			keys = append(keys, key)
+		}
+		revenues[key].streams += delta.Streams
+		revenues[key].micro += delta.Streams * rate.MicroPerStream
+	}
+
+	now := time.Now().UTC()
+	entries := make([]models.RoyaltyLedgerEntry, 0, len(keys))
+	for _, key := range keys {
+		track := tracks[key.trackID]
+		contract := contractByID[key.contractID]
+
+		featuredShares := contract.FeaturedShare * uint32(len(track.featured))
+		if contract.LabelShare+featuredShares > models.FullShare {
+			return nil, fmt.Errorf("%w: track %d has %d featured artists", ErrBadShares, key.trackID, len(track.featured))
+		}
+
+		gross := int64(revenues[key].micro)
+
+		entry := models.RoyaltyLedgerEntry{
+			ContractID: key.contractID,
+			TrackID:    key.trackID,
+			Platform:   key.platform,
+			PeriodFrom: from,
+			PeriodTo:   to,
+			Streams:    revenues[key].streams,
+			Currency:   key.currency,
+			CreatedAt:  now,
+		}
+
+		parties := []party{{artistID: track.ownerID, share: models.FullShare - contract.LabelShare - featuredShares}}
+		for _, artistID := range track.featured {
+			parties = append(parties, party{artistID: artistID, share: contract.FeaturedShare})
+		}
+
+		split := make([]models.RoyaltyLedgerEntry, 0, len(parties))
+		paid := int64(0)
+		for _, party := range parties {
+			artistEntry := entry
+			artistEntry.ArtistID, artistEntry.Share = party.artistID, party.share
+			artistEntry.AmountMicro = gross * int64(party.share) / int64(models.FullShare)
+			artistEntry.Amount = artistEntry.AmountMicro / int64(models.MicroUnits)
+			paid += artistEntry.AmountMicro
+			split = append(split, artistEntry)
+		}
+
+		labelEntry := entry
+		labelEntry.Share = contract.LabelShare
+		labelEntry.AmountMicro = gross - paid
+		labelEntry.Amount = labelEntry.AmountMicro / int64(models.MicroUnits)
+
+		entries = append(entries, labelEntry)
+		entries = append(entries, split...)
+	}
+
+	return entries, nil
+}
+
+type party struct {
+	artistID uint64
+	share    uint32
+}
+
+// publishedTracks returns the owner and featured artists of every track published until date
+func (rltSvc *RoyaltyService) publishedTracks(ctx context.Context, date time.Time) (map[uint64]trackInfo, error) {
+
+	pubs, err := rltSvc.pbcRepo.GetAllUntilDate(ctx, date)
+	if err != nil {
+		return nil, fmt.Errorf("can't get publications with err %w", err)
+	}
+
+	tracks := make(map[uint64]trackInfo)
+	for _, pub := range pubs {
+		release, err := rltSvc.releaseRepo.Get(ctx, pub.ReleaseID)
+		if err != nil {
+			return nil, fmt.Errorf("can't get release %d with err %w", pub.ReleaseID, err)
+		}
+
+		for _, trackID := range release.Tracks {
+			if _, ok := tracks[trackID]; ok {
+				continue
+			}
+
+			track, err := rltSvc.trackRepo.Get(ctx, trackID)
+			if err != nil {
+				return nil, fmt.Errorf("can't get track %d with err %w", trackID, err)
+			}
+
+			info := trackInfo{ownerID: release.ArtistID}
+			seen := map[uint64]bool{release.ArtistID: true}
+			for _, artistID := range track.Artists {
+				if !seen[artistID] {
+					seen[artistID] = true
+					info.featured = append(info.featured, artistID)
+				}
+			}
+			tracks[trackID] = info
+		}
+	}
+
+	return tracks, nil
+}
+
+// rateFor returns the latest rate of the platform valid on date,
+// the country rate is preferred over the platform default
+func rateFor(rates []models.StreamRate, platform, country string, date time.Time) (models.StreamRate, bool) {
+
+	var best models.StreamRate
+	found := false
+	for _, rate := range rates {
+		if rate.Platform != platform || rate.ValidFrom.After(date) {
+			continue
+		}
+		if rate.Country != "" && rate.Country != country {
+			continue
+		}
+
+		better := !found ||
+			(rate.Country != "" && best.Country == "") ||
+			(rate.Country == best.Country && rate.ValidFrom.After(best.ValidFrom))
+		if better {
+			best, found = rate, true
+		}
+	}
+
+	return best, found
+}
+
+func contractFor(contracts []models.RoyaltyContract, date time.Time) (models.RoyaltyContract, bool) {
+	for _, contract := range contracts {
+		if contract.ValidFrom.After(date) {
+			continue
+		}
+		if !contract.ValidTo.IsZero() && !date.Before(contract.ValidTo) {
+			continue
+		}
+		return contract, true
+	}
+	return models.RoyaltyContract{}, false
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/rauzh/cd-core/models"
+	"github.com/rauzh/cd-core/repo/mocks"
+	cdtime "github.com/rauzh/cd-core/time"
+	transacMock "github.com/rauzh/cd-core/transactor/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type royaltyMocks struct {
+	rateRepo     *mocks.StreamRateRepo
+	contractRepo *mocks.RoyaltyContractRepo
+	ledgerRepo   *mocks.RoyaltyLedgerRepo
+	statRepo     *mocks.StatisticsRepo
+	pbcRepo      *mocks.PublicationRepo
+	releaseRepo  *mocks.ReleaseRepo
+	trackRepo    *mocks.TrackRepo
+}
+
+func newTestRoyaltyService(t *testing.T) (IRoyaltyService, *royaltyMocks) {
+
+	m := &royaltyMocks{
+		rateRepo:     mocks.NewStreamRateRepo(t),
+		contractRepo: mocks.NewRoyaltyContractRepo(t),
+		ledgerRepo:   mocks.NewRoyaltyLedgerRepo(t),
+		statRepo:     mocks.NewStatisticsRepo(t),
+		pbcRepo:      mocks.NewPublicationRepo(t),
+		releaseRepo:  mocks.NewReleaseRepo(t),
+		trackRepo:    mocks.NewTrackRepo(t),
+	}
+
+	transactor := transacMock.NewTransactor(t)
+	transactor.EXPECT().WithinTransaction(mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) }).Maybe()
+
+	rltSvc := NewRoyaltyService(m.rateRepo, m.contractRepo, m.ledgerRepo, m.statRepo, m.pbcRepo,
+		m.releaseRepo, m.trackRepo, transactor, slog.Default())
+
+	return rltSvc, m
+}
+
+func TestRoyaltyService_ClosePeriod(t *testing.T) {
+
+	rltSvc, m := newTestRoyaltyService(t)
+
+	from, to := cdtime.Date(2024, 4, 1), cdtime.Date(2024, 4, 30)
+
+	m.ledgerRepo.EXPECT().GetLastPeriodEnd(mock.Anything).Return(cdtime.Date(2024, 3, 31), nil).Once()
+
+	m.pbcRepo.EXPECT().GetAllUntilDate(mock.Anything, to).Return([]models.Publication{{ReleaseID: 1}}, nil).Once()
+	m.releaseRepo.EXPECT().Get(mock.Anything, uint64(1)).Return(
+		&models.Release{ReleaseID: 1, ArtistID: 10, Tracks: []uint64{1}}, nil).Once()
+	// artist 20 is featured
+	m.trackRepo.EXPECT().Get(mock.Anything, uint64(1)).Return(&models.Track{TrackID: 1, Artists: []uint64{10, 20}}, nil).Once()
+
+	m.rateRepo.EXPECT().GetAllUntil(mock.Anything, to).Return([]models.StreamRate{
+		{Platform: "spotify", Currency: "USD", MicroPerStream: 300_000, ValidFrom: cdtime.Date(2023, 1, 1)},
+		{Platform: "spotify", Currency: "USD", MicroPerStream: 400_000, ValidFrom: cdtime.Date(2024, 1, 1)},
+		{Platform: "spotify", Country: "DE", Currency: "USD", MicroPerStream: 500_000, ValidFrom: cdtime.Date(2024, 4, 15)},
+		{Platform: "deezer", Currency: "USD", MicroPerStream: 1_000_000, ValidFrom: cdtime.Date(2023, 1, 1)},
+	}, nil).Once()
+
+	m.statRepo.EXPECT().GetLastForTracksBefore(mock.Anything, []uint64{1}, from).Return([]models.Statistics{
+		{TrackID: 1, Platform: "deezer", Date: cdtime.Date(2024, 3, 31), Streams: 5000},
+	}, nil).Once()
+	m.statRepo.EXPECT().GetForTracksBetween(mock.Anything, []uint64{1}, from, to).Return([]models.Statistics{
+		{TrackID: 1, Platform: "deezer", Date: cdtime.Date(2024, 4, 30), Streams: 5500},
+		{TrackID: 1, Platform: "spotify", Country: "US", Date: cdtime.Date(2024, 4, 10), Streams: 1001, Kind: models.DeltaStatistics},
+		// the country rate starts in the middle of the period
+		{TrackID: 1, Platform: "spotify", Country: "DE", Date: cdtime.Date(2024, 4, 10), Streams: 100, Kind: models.DeltaStatistics},
+		{TrackID: 1, Platform: "spotify", Country: "DE", Date: cdtime.Date(2024, 4, 20), Streams: 100, Kind: models.DeltaStatistics},
+	}, nil).Once()
+
+	m.contractRepo.EXPECT().GetForArtist(mock.Anything, uint64(10)).Return([]models.RoyaltyContract{
+		{ContractID: 5, ArtistID: 10, LabelShare: 5000, FeaturedShare: 1000, ValidFrom: cdtime.Date(2024, 1, 1)},
+	}, nil).Once()
+
+	m.ledgerRepo.EXPECT().Append(mock.Anything, mock.AnythingOfType("[]models.RoyaltyLedgerEntry")).Return(nil).Once()
+
+	entries, err := rltSvc.ClosePeriod(from, to)
+
+	assert.Nil(t, err)
+
+	type amount struct {
+		artistID uint64
+		platform string
+		share    uint32
+		amount   int64
+	}
+	amounts := make([]amount, 0, len(entries))
+	for _, entry := range entries {
+		assert.Equal(t, uint64(5), entry.ContractID)
+		assert.Equal(t, "USD", entry.Currency)
+		amounts = append(amounts, amount{entry.ArtistID, entry.Platform, entry.Share, entry.Amount})
+	}
+
+	// 1001 * 0.4 + 100 * 0.4 + 100 * 0.5 = 490.4 cents from spotify, entries keep the fractions.
+	// The label gets rounding remainders
+	assert.Equal(t, []amount{
+		{0, "deezer", 5000, 250},
+		{10, "deezer", 4000, 200},
+		{20, "deezer", 1000, 50},
+		{0, "spotify", 5000, 245},
+		{10, "spotify", 4000, 196},
+		{20, "spotify", 1000, 49},
+	}, amounts)
+	assert.Equal(t, uint64(1201), entries[3].Streams)
+	assert.Equal(t, int64(49_040_000), entries[5].AmountMicro)
+}
+
+func TestRoyaltyService_ClosePeriodOverlaps(t *testing.T) {
+
+	rltSvc, m := newTestRoyaltyService(t)
+
+	m.ledgerRepo.EXPECT().GetLastPeriodEnd(mock.Anything).Return(cdtime.Date(2024, 4, 30), nil).Once()
+
+	_, err := rltSvc.ClosePeriod(cdtime.Date(2024, 4, 15), cdtime.Date(2024, 5, 14))
+	assert.ErrorIs(t, err, ErrPeriodClosed)
+
+	// days between periods would never be paid
+	m.ledgerRepo.EXPECT().GetLastPeriodEnd(mock.Anything).Return(cdtime.Date(2024, 4, 30), nil).Once()
+
+	_, err = rltSvc.ClosePeriod(cdtime.Date(2024, 5, 2), cdtime.Date(2024, 5, 31))
+	assert.ErrorIs(t, err, ErrPeriodGap)
+
+	_, err = rltSvc.ClosePeriod(cdtime.GetToday().AddDate(0, 0, -7), cdtime.GetToday())
+	assert.ErrorIs(t, err, ErrPeriodNotOver)
+}
+
+func TestRoyaltyService_Statement(t *testing.T) {
+
+	rltSvc, m := newTestRoyaltyService(t)
+
+	from, to := cdtime.Date(2024, 1, 1), cdtime.Date(2024, 6, 30)
+
+	m.ledgerRepo.EXPECT().GetForArtist(mock.AnythingOfType("context.backgroundCtx"), uint64(20), from, to).Return(
+		[]models.RoyaltyLedgerEntry{
+			{ArtistID: 20, TrackID: 1, Currency: "USD", Amount: 49, AmountMicro: 49_600_000},
+			{ArtistID: 20, TrackID: 1, Currency: "USD", Amount: 50, AmountMicro: 50_600_000},
+			{ArtistID: 20, TrackID: 7, Currency: "EUR", Amount: 1200, AmountMicro: 1_200_000_000},
+		}, nil).Once()
+
+	statement, err := rltSvc.Statement(20, from, to)
+
+	assert.Nil(t, err)
+	assert.Len(t, statement.Entries, 3)
+	// fractions of entries add up to a cent
+	assert.Equal(t, map[string]int64{"USD": 100, "EUR": 1200}, statement.Totals)
+}
+
+func TestRoyaltyService_SetContract(t *testing.T) {
+
+	rltSvc, _ := newTestRoyaltyService(t)
+
+	err := rltSvc.SetContract(&models.RoyaltyContract{ArtistID: 10, LabelShare: 8000, FeaturedShare: 3000,
+		ValidFrom: cdtime.Date(2024, 1, 1)})
+	assert.ErrorIs(t, err, ErrBadShares)
+}
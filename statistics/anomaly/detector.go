@@ -0,0 +1,241 @@
+// Package anomaly flags streams spikes that look like bot streams.
+// Streams per day of every track, platform and country are compared with the rolling
+// median of the previous days, the spread is measured by median absolute deviation,
+// so a single earlier spike does not hide the next one.
+// Platforms may skip days, so every row is divided by the days since the previous one
+package anomaly
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/rauzh/cd-core/models"
+	"github.com/rauzh/cd-core/repo"
+	"github.com/rauzh/cd-core/requests/broker/events"
+	"github.com/rauzh/cd-core/statistics/convert"
+)
+
+// madScale makes MAD comparable with standard deviation of normal distribution
+const madScale = 1.4826
+
+type Config struct {
+	// Window is the number of previous days in the baseline
+	Window int
+	// MinHistory is the number of days covered by the baseline needed to judge a day
+	MinHistory int
+	// Threshold is the robust z-score from which a day is flagged
+	Threshold float64
+	// MinStreams are ignored, small tracks jump from 2 to 20 streams a day naturally
+	MinStreams uint64
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Window:     28,
+		MinHistory: 7,
+		Threshold:  6,
+		MinStreams: 1000,
+	}
+}
+
+type Detector struct {
+	statRepo  repo.StatisticsRepo
+	flagRepo  repo.StatisticsFlagRepo
+	publisher events.IEventPublisher
+
+	cfg Config
+
+	logger *slog.Logger
+}
+
+func NewDetector(
+	statRepo repo.StatisticsRepo,
+	flagRepo repo.StatisticsFlagRepo,
+	publisher events.IEventPublisher,
+	cfg Config,
+	logger *slog.Logger) *Detector {
+	return &Detector{
+		statRepo:  statRepo,
+		flagRepo:  flagRepo,
+		publisher: publisher,
+		cfg:       cfg,
+		logger:    logger,
+	}
+}
+
+type rowKey struct {
+	trackID  uint64
+	platform string
+	country  string
+	date     time.Time
+}
+
+// Check compares just stored stats with the history of their tracks,
+// stores flags of spikes and publishes an event per flag for manager review
+func (detector *Detector) Check(ctx context.Context, fresh []models.Statistics) ([]models.StatisticsFlag, error) {
+
+	if len(fresh) == 0 {
+		return nil, nil
+	}
+
+	freshKeys := make(map[rowKey]bool, len(fresh))
+	trackSet := make(map[uint64]struct{})
+	from, to := fresh[0].Date, fresh[0].Date
+	for _, stat := range fresh {
+		freshKeys[rowKey{trackID: stat.TrackID, platform: stat.Platform, country: stat.Country, date: stat.Date}] = true
+		trackSet[stat.TrackID] = struct{}{}
+		if stat.Date.Before(from) {
+			from = stat.Date
+		}
+		if stat.Date.After(to) {
+			to = stat.Date
+		}
+	}
+
+	trackIDs := make([]uint64, 0, len(trackSet))
+	for trackID := range trackSet {
+		trackIDs = append(trackIDs, trackID)
+	}
+	sort.Slice(trackIDs, func(i, j int) bool { return trackIDs[i] < trackIDs[j] })
+
+	windowStart := from.AddDate(0, 0, -detector.cfg.Window)
+
+	deltas, err := convert.DeltasBetween(ctx, detector.statRepo, trackIDs, windowStart, to)
+	if err != nil {
+		return nil, fmt.Errorf("can't get stats history with err %w", err)
+	}
+
+	flags := detector.detect(deltas, freshKeys)
+	if len(flags) == 0 {
+		return nil, nil
+	}
+
+	if err := detector.flagRepo.CreateMany(ctx, flags); err != nil {
+		return nil, fmt.Errorf("can't store statistics flags with err %w", err)
+	}
+
+	anomalies := make([]events.Event, 0, len(flags))
+	for _, flag := range flags {
+		detector.logger.WarnContext(ctx, "STAT_ANOMALY spike", "track_id", flag.TrackID,
+			"platform", flag.Platform, "country", flag.Country, "date", flag.Date, "streams", flag.Streams, "score", flag.Score)
+
+		anomalies = append(anomalies, &events.StatisticsAnomalyDetected{
+			TrackID:    flag.TrackID,
+			Platform:   flag.Platform,
+			Country:    flag.Country,
+			Date:       flag.Date,
+			Streams:    flag.Streams,
+			Median:     flag.Median,
+			Score:      flag.Score,
+			OccurredAt: flag.CreatedAt,
+		})
+	}
+
+	if err := detector.publisher.Publish(ctx, anomalies...); err != nil {
+		return nil, fmt.Errorf("can't publish statistics anomalies with err %w", err)
+	}
+
+	return flags, nil
+}
+
+// point is a row of a series, its streams are spread over the days since the previous row
+type point struct {
+	date  time.Time
+	days  int
+	daily float64
+}
+
+// detect walks ordered deltas of every series and scores fresh days against previous ones.
+// The first row of a series only tells where the next one starts counting from
+func (detector *Detector) detect(deltas []models.Statistics, fresh map[rowKey]bool) []models.StatisticsFlag {
+
+	now := time.Now().UTC()
+	flags := make([]models.StatisticsFlag, 0)
+
+	var window []point
+	var series *rowKey
+	var previous time.Time
+	for _, delta := range deltas {
+		key := rowKey{trackID: delta.TrackID, platform: delta.Platform, country: delta.Country, date: delta.Date}
+		if series == nil || key.trackID != series.trackID || key.platform != series.platform || key.country != series.country {
+			series, window, previous = &key, window[:0], delta.Date
+			continue
+		}
+
+		days := max(daysBetween(previous, delta.Date), 1)
+		previous = delta.Date
+		cur := point{date: delta.Date, days: days, daily: float64(delta.Streams) / float64(days)}
+
+		// the baseline is the rows of the previous Window days
+		windowStart := delta.Date.AddDate(0, 0, -detector.cfg.Window)
+		for len(window) > 0 && window[0].date.Before(windowStart) {
+			window = window[1:]
+		}
+
+		covered := 0
+		values := make([]float64, 0, len(window))
+		for _, p := range window {
+			covered += p.days
+			values = append(values, p.daily)
+		}
+		covered = min(covered, detector.cfg.Window)
+
+		if fresh[key] && covered >= detector.cfg.MinHistory && cur.daily >= float64(detector.cfg.MinStreams) {
+			median, mad := medianMAD(values)
+			if score := robustScore(cur.daily, median, mad); score >= detector.cfg.Threshold {
+				flags = append(flags, models.StatisticsFlag{
+					TrackID:   delta.TrackID,
+					Platform:  delta.Platform,
+					Country:   delta.Country,
+					Date:      delta.Date,
+					Streams:   uint64(math.Round(cur.daily)),
+					Median:    median,
+					MAD:       mad,
+					Score:     score,
+					CreatedAt: now,
+				})
+			}
+		}
+
+		window = append(window, cur)
+	}
+
+	return flags
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(math.Round(to.Sub(from).Hours() / 24))
+}
+
+func medianMAD(values []float64) (median, mad float64) {
+	median = medianOf(values)
+
+	deviations := make([]float64, len(values))
+	for i, value := range values {
+		deviations[i] = math.Abs(value - median)
+	}
+
+	return median, medianOf(deviations)
+}
+
+func medianOf(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// robustScore is z-score with median and MAD instead of mean and deviation.
+// Flat history has zero MAD, the spread is then taken as a tenth of the median
+func robustScore(value, median, mad float64) float64 {
+	spread := math.Max(madScale*mad, math.Max(median/10, 1))
+	return (value - median) / spread
+}
@@ -0,0 +1,99 @@
+package anomaly
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/rauzh/cd-core/models"
+	"github.com/rauzh/cd-core/repo/mocks"
+	"github.com/rauzh/cd-core/requests/broker/events"
+	"github.com/rauzh/cd-core/requests/broker/outbox"
+	outboxMocks "github.com/rauzh/cd-core/requests/broker/outbox/repo/mocks"
+	cdtime "github.com/rauzh/cd-core/time"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCheckingStatisticsRepo_CreateMany(t *testing.T) {
+
+	statRepo := mocks.NewStatisticsRepo(t)
+	flagRepo := mocks.NewStatisticsFlagRepo(t)
+	outboxRepo := outboxMocks.NewOutboxRepo(t)
+
+	cfg := Config{Window: 7, MinHistory: 5, Threshold: 6, MinStreams: 100}
+	detector := NewDetector(statRepo, flagRepo, events.NewOutboxPublisher(outboxRepo), cfg, slog.Default())
+	checkingRepo := NewCheckingStatisticsRepo(statRepo, detector)
+
+	day := cdtime.Date(2024, 5, 10)
+	windowStart := day.AddDate(0, 0, -cfg.Window)
+
+	// track 1 gains about 1000 streams a day, track 2 about 500
+	// and track 3 1000 until its platform skips a day
+	history := make([]models.Statistics, 0)
+	var total uint64 = 50000
+	for i, gained := range []uint64{1000, 1100, 950, 1020, 980, 1050, 990} {
+		total += gained
+		history = append(history,
+			models.Statistics{TrackID: 1, Platform: "spotify", Date: windowStart.AddDate(0, 0, i), Streams: total},
+			models.Statistics{TrackID: 2, Platform: "spotify", Date: windowStart.AddDate(0, 0, i), Streams: gained / 2,
+				Kind: models.DeltaStatistics})
+		if i < 6 {
+			history = append(history, models.Statistics{TrackID: 3, Platform: "deezer", Date: windowStart.AddDate(0, 0, i),
+				Streams: 1000, Kind: models.DeltaStatistics})
+		}
+	}
+
+	fresh := []models.Statistics{
+		// bot streams
+		{TrackID: 1, Platform: "spotify", Date: day, Streams: total + 25000},
+		{TrackID: 2, Platform: "spotify", Date: day, Streams: 530, Kind: models.DeltaStatistics},
+		// two days in one row
+		{TrackID: 3, Platform: "deezer", Date: day, Streams: 2000, Kind: models.DeltaStatistics},
+	}
+
+	statRepo.EXPECT().CreateMany(mock.Anything, fresh).Return(nil).Once()
+	statRepo.EXPECT().GetLastForTracksBefore(mock.Anything, []uint64{1, 2, 3}, windowStart).Return(
+		[]models.Statistics{{TrackID: 1, Platform: "spotify", Date: windowStart.AddDate(0, 0, -1), Streams: 50000}}, nil).Once()
+	statRepo.EXPECT().GetForTracksBetween(mock.Anything, []uint64{1, 2, 3}, windowStart, day).Return(
+		append(history, fresh...), nil).Once()
+
+	flagRepo.EXPECT().CreateMany(mock.Anything, mock.MatchedBy(func(flags []models.StatisticsFlag) bool {
+		return len(flags) == 1 && flags[0].TrackID == 1 && flags[0].Date.Equal(day) &&
+			flags[0].Streams == 25000 && flags[0].Median == 1005
+	})).Return(nil).Once()
+
+	outboxRepo.EXPECT().Create(mock.Anything, mock.MatchedBy(func(msg *outbox.OutboxMessage) bool {
+		return msg.Topic == events.StatisticsAnomalyDetectedTopic
+	})).Return(nil).Once()
+
+	assert.Nil(t, checkingRepo.CreateMany(context.Background(), fresh))
+}
+
+func TestCheckingStatisticsRepo_CheckFails(t *testing.T) {
+
+	statRepo := mocks.NewStatisticsRepo(t)
+
+	detector := NewDetector(statRepo, mocks.NewStatisticsFlagRepo(t), nil, DefaultConfig(), slog.Default())
+	checkingRepo := NewCheckingStatisticsRepo(statRepo, detector)
+
+	fresh := []models.Statistics{{TrackID: 1, Date: cdtime.Date(2024, 5, 10), Streams: 10}}
+
+	statRepo.EXPECT().CreateMany(mock.Anything, fresh).Return(nil).Once()
+	statRepo.EXPECT().GetLastForTracksBefore(mock.Anything, []uint64{1}, mock.Anything).Return(
+		nil, errors.New("db is down")).Once()
+
+	// stats are stored even if they can't be checked
+	assert.Nil(t, checkingRepo.CreateMany(context.Background(), fresh))
+}
+
+func TestRobustScore(t *testing.T) {
+
+	median, mad := medianMAD([]float64{10, 12, 11, 100, 9})
+	assert.Equal(t, 11.0, median)
+	assert.Equal(t, 1.0, mad)
+
+	// flat history has no spread, a tenth of the median is used
+	assert.Equal(t, 10.0, robustScore(200, 100, 0))
+}
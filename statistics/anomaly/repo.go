@@ -0,0 +1,33 @@
+package anomaly
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/rauzh/cd-core/models"
+	"github.com/rauzh/cd-core/repo"
+)
+
+// CheckingStatisticsRepo checks stats for spikes after they are stored by CreateMany,
+// the way both fetched and imported stats are stored
+type CheckingStatisticsRepo struct {
+	repo.StatisticsRepo
+	detector *Detector
+}
+
+func NewCheckingStatisticsRepo(statRepo repo.StatisticsRepo, detector *Detector) repo.StatisticsRepo {
+	return &CheckingStatisticsRepo{StatisticsRepo: statRepo, detector: detector}
+}
+
+// CreateMany does not fail if the check fails, stats are stored anyway and the error is logged.
+// They are not checked again, later rows only have them in their baseline
+func (statRepo *CheckingStatisticsRepo) CreateMany(ctx context.Context, stats []models.Statistics) error {
+	if err := statRepo.StatisticsRepo.CreateMany(ctx, stats); err != nil {
+		return err
+	}
+
+	if _, err := statRepo.detector.Check(ctx, stats); err != nil {
+		statRepo.detector.logger.ErrorContext(ctx, "STAT_ANOMALY Check", slog.Any("error", err))
+	}
+	return nil
+}
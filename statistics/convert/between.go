@@ -0,0 +1,50 @@
+package convert
+
+import (
+	"context"
+	"time"
+
+	"github.com/rauzh/cd-core/models"
+	"github.com/rauzh/cd-core/repo"
+	cdtime "github.com/rauzh/cd-core/time"
+)
+
+// DeltasBetween returns increments of tracks from from to to inclusive. The last rows before
+// the range are the baseline of cumulative series, so their totals are not counted as gained
+func DeltasBetween(ctx context.Context, statRepo repo.StatisticsRepo, trackIDs []uint64, from, to time.Time) ([]models.Statistics, error) {
+	return deltasBetween(ctx, statRepo, trackIDs, from, to, false)
+}
+
+// DeltasBetweenFromZero is DeltasBetween that counts series without rows before the range
+// from zero, for callers that must account the total gained before the first report, like royalties
+func DeltasBetweenFromZero(ctx context.Context, statRepo repo.StatisticsRepo, trackIDs []uint64, from, to time.Time) ([]models.Statistics, error) {
+	return deltasBetween(ctx, statRepo, trackIDs, from, to, true)
+}
+
+func deltasBetween(ctx context.Context, statRepo repo.StatisticsRepo, trackIDs []uint64, from, to time.Time,
+	fromZero bool) ([]models.Statistics, error) {
+
+	if len(trackIDs) == 0 {
+		return nil, nil
+	}
+
+	baseline, err := statRepo.GetLastForTracksBefore(ctx, trackIDs, from)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := statRepo.GetForTracksBetween(ctx, trackIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	deltas := make([]models.Statistics, 0, len(stats))
+	for _, delta := range toDeltas(WithoutWorldwideOverlap(append(baseline, stats...)), fromZero) {
+		if date := cdtime.Day(delta.Date); date.Before(from) || date.After(to) {
+			continue
+		}
+		deltas = append(deltas, delta)
+	}
+
+	return deltas, nil
+}
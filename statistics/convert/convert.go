@@ -0,0 +1,210 @@
+// Package convert turns statistics rows of any kind into daily deltas or cumulative totals.
+// A series is the rows of one track from one platform and country, series are never mixed
+// with each other except in Totals, which sums platforms and countries up.
+// A platform may report both worldwide and per country numbers of a track, the worldwide
+// series is then the sum of the country ones and must not be added to them, see WithoutWorldwideOverlap
+package convert
+
+import (
+	"sort"
+	"time"
+
+	"github.com/rauzh/cd-core/models"
+)
+
+type seriesKey struct {
+	trackID  uint64
+	platform string
+	country  string
+}
+
+// series groups rows by track, platform and country, rows of a series are ordered by date
+func series(stats []models.Statistics) ([]seriesKey, map[seriesKey][]models.Statistics) {
+
+	grouped := make(map[seriesKey][]models.Statistics)
+	keys := make([]seriesKey, 0)
+
+	for _, stat := range stats {
+		key := seriesKey{trackID: stat.TrackID, platform: stat.Platform, country: stat.Country}
+		if _, ok := grouped[key]; !ok {
+			keys = append(keys, key)
+		}
+		grouped[key] = append(grouped[key], stat)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].trackID != keys[j].trackID {
+			return keys[i].trackID < keys[j].trackID
+		}
+		if keys[i].platform != keys[j].platform {
+			return keys[i].platform < keys[j].platform
+		}
+		return keys[i].country < keys[j].country
+	})
+
+	for _, rows := range grouped {
+		sort.SliceStable(rows, func(i, j int) bool { return rows[i].Date.Before(rows[j].Date) })
+	}
+
+	return keys, grouped
+}
+
+// toDeltas returns increments for every row date, ordered by track, platform, country and date.
+// The first cumulative row of a series is its baseline and gives zero delta, as nothing is known
+// about when its total was gained, unless fromZero is set for callers that must account the total
+// gained before the first report, like royalties. Stored rows are converted by DeltasBetween,
+// which adds the last rows before the range and drops worldwide rows overlapping per country ones.
+// Decreasing totals (clawed back streams) give zero delta and lower the baseline
+func toDeltas(stats []models.Statistics, fromZero bool) []models.Statistics {
+
+	keys, grouped := series(stats)
+	deltas := make([]models.Statistics, 0, len(stats))
+
+	for _, key := range keys {
+		var streams, likes uint64
+		started := fromZero
+		for _, stat := range grouped[key] {
+			if stat.IsDelta() {
+				streams += stat.Streams
+				likes += stat.Likes
+				deltas = append(deltas, stat)
+				started = true
+				continue
+			}
+			if !started {
+				streams, likes = stat.Streams, stat.Likes
+				started = true
+			}
+
+			delta := stat
+			delta.Kind = models.DeltaStatistics
+			delta.Streams = sub(stat.Streams, streams)
+			delta.Likes = sub(stat.Likes, likes)
+			streams, likes = stat.Streams, stat.Likes
+
+			deltas = append(deltas, delta)
+		}
+	}
+
+	return deltas
+}
+
+// ToCumulative returns totals for every row date, ordered by track, platform, country and date.
+// Deltas are added to the latest total, cumulative rows replace it
+func ToCumulative(stats []models.Statistics) []models.Statistics {
+
+	keys, grouped := series(stats)
+	totals := make([]models.Statistics, 0, len(stats))
+
+	for _, key := range keys {
+		var streams, likes uint64
+		for _, stat := range grouped[key] {
+			if stat.IsDelta() {
+				streams += stat.Streams
+				likes += stat.Likes
+			} else {
+				streams, likes = stat.Streams, stat.Likes
+			}
+
+			total := stat
+			total.Kind = models.CumulativeStatistics
+			total.Streams, total.Likes = streams, likes
+
+			totals = append(totals, total)
+		}
+	}
+
+	return totals
+}
+
+// Totals returns cumulative totals of every track summed over platforms and countries
+// for every date any series has a row for, ordered by track and date.
+// Series without a row for the date counts with its latest known total
+func Totals(stats []models.Statistics) []models.Statistics {
+
+	keys, grouped := series(ToCumulative(WithoutWorldwideOverlap(stats)))
+
+	byTrack := make(map[uint64][]seriesKey)
+	trackIDs := make([]uint64, 0)
+	for _, key := range keys {
+		if _, ok := byTrack[key.trackID]; !ok {
+			trackIDs = append(trackIDs, key.trackID)
+		}
+		byTrack[key.trackID] = append(byTrack[key.trackID], key)
+	}
+
+	totals := make([]models.Statistics, 0)
+	for _, trackID := range trackIDs {
+
+		dateSet := make(map[time.Time]struct{})
+		for _, key := range byTrack[trackID] {
+			for _, stat := range grouped[key] {
+				dateSet[stat.Date] = struct{}{}
+			}
+		}
+		dates := make([]time.Time, 0, len(dateSet))
+		for date := range dateSet {
+			dates = append(dates, date)
+		}
+		sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+		// position of the next row of every series
+		next := make([]int, len(byTrack[trackID]))
+		latest := make([]models.Statistics, len(byTrack[trackID]))
+
+		for _, date := range dates {
+			total := models.Statistics{TrackID: trackID, Date: date, Kind: models.CumulativeStatistics}
+
+			for i, key := range byTrack[trackID] {
+				rows := grouped[key]
+				for next[i] < len(rows) && !rows[next[i]].Date.After(date) {
+					latest[i] = rows[next[i]]
+					next[i]++
+				}
+				total.Streams += latest[i].Streams
+				total.Likes += latest[i].Likes
+			}
+
+			totals = append(totals, total)
+		}
+	}
+
+	return totals
+}
+
+// WithoutWorldwideOverlap drops worldwide rows (empty Country) of track and platform
+// that also has per country rows, so streams are not counted twice when summed up
+func WithoutWorldwideOverlap(stats []models.Statistics) []models.Statistics {
+
+	type platformKey struct {
+		trackID  uint64
+		platform string
+	}
+
+	perCountry := make(map[platformKey]bool)
+	for _, stat := range stats {
+		if stat.Country != "" {
+			perCountry[platformKey{trackID: stat.TrackID, platform: stat.Platform}] = true
+		}
+	}
+	if len(perCountry) == 0 {
+		return stats
+	}
+
+	filtered := make([]models.Statistics, 0, len(stats))
+	for _, stat := range stats {
+		if stat.Country == "" && perCountry[platformKey{trackID: stat.TrackID, platform: stat.Platform}] {
+			continue
+		}
+		filtered = append(filtered, stat)
+	}
+
+	return filtered
+}
+
+func sub(cur, prev uint64) uint64 {
+	if cur < prev {
+		return 0
+	}
+	return cur - prev
+}
@@ -0,0 +1,97 @@
+package convert
+
+import (
+	"testing"
+
+	"github.com/rauzh/cd-core/models"
+	cdtime "github.com/rauzh/cd-core/time"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	day1 = cdtime.Date(2024, 5, 1)
+	day2 = cdtime.Date(2024, 5, 2)
+	day3 = cdtime.Date(2024, 5, 3)
+)
+
+func TestToDeltas(t *testing.T) {
+
+	stats := []models.Statistics{
+		{TrackID: 1, Platform: "spotify", Date: day3, Streams: 90, Likes: 4},
+		{TrackID: 1, Platform: "spotify", Date: day1, Streams: 100, Likes: 5},
+		// clawed back streams
+		{TrackID: 1, Platform: "spotify", Date: day2, Streams: 60, Likes: 5},
+		{TrackID: 1, Platform: "believe", Date: day1, Streams: 7, Kind: models.DeltaStatistics},
+	}
+
+	// the first total is the baseline
+	assert.Equal(t, []models.Statistics{
+		{TrackID: 1, Platform: "believe", Date: day1, Streams: 7, Kind: models.DeltaStatistics},
+		{TrackID: 1, Platform: "spotify", Date: day1, Streams: 0, Likes: 0, Kind: models.DeltaStatistics},
+		{TrackID: 1, Platform: "spotify", Date: day2, Streams: 0, Likes: 0, Kind: models.DeltaStatistics},
+		{TrackID: 1, Platform: "spotify", Date: day3, Streams: 30, Likes: 0, Kind: models.DeltaStatistics},
+	}, toDeltas(stats, false))
+
+	assert.Equal(t, []models.Statistics{
+		{TrackID: 1, Platform: "believe", Date: day1, Streams: 7, Kind: models.DeltaStatistics},
+		{TrackID: 1, Platform: "spotify", Date: day1, Streams: 100, Likes: 5, Kind: models.DeltaStatistics},
+		{TrackID: 1, Platform: "spotify", Date: day2, Streams: 0, Likes: 0, Kind: models.DeltaStatistics},
+		{TrackID: 1, Platform: "spotify", Date: day3, Streams: 30, Likes: 0, Kind: models.DeltaStatistics},
+	}, toDeltas(stats, true))
+}
+
+func TestToCumulative(t *testing.T) {
+
+	stats := []models.Statistics{
+		{TrackID: 1, Date: day1, Streams: 10, Kind: models.DeltaStatistics},
+		{TrackID: 1, Date: day2, Streams: 5, Likes: 1, Kind: models.DeltaStatistics},
+		// snapshot replaces the running total
+		{TrackID: 1, Date: day3, Streams: 40, Likes: 2},
+	}
+
+	assert.Equal(t, []models.Statistics{
+		{TrackID: 1, Date: day1, Streams: 10, Kind: models.CumulativeStatistics},
+		{TrackID: 1, Date: day2, Streams: 15, Likes: 1, Kind: models.CumulativeStatistics},
+		{TrackID: 1, Date: day3, Streams: 40, Likes: 2, Kind: models.CumulativeStatistics},
+	}, ToCumulative(stats))
+
+	// converting back gives the same increments
+	assert.Equal(t, []models.Statistics{
+		{TrackID: 1, Date: day1, Streams: 10, Kind: models.DeltaStatistics},
+		{TrackID: 1, Date: day2, Streams: 5, Likes: 1, Kind: models.DeltaStatistics},
+		{TrackID: 1, Date: day3, Streams: 25, Likes: 1, Kind: models.DeltaStatistics},
+	}, toDeltas(ToCumulative(stats), true))
+}
+
+func TestTotals(t *testing.T) {
+
+	stats := []models.Statistics{
+		{TrackID: 2, Platform: "spotify", Date: day1, Streams: 1},
+		{TrackID: 1, Platform: "spotify", Date: day1, Streams: 100},
+		{TrackID: 1, Platform: "spotify", Date: day3, Streams: 150},
+		{TrackID: 1, Platform: "deezer", Date: day2, Streams: 10, Kind: models.DeltaStatistics},
+		{TrackID: 1, Platform: "deezer", Date: day3, Streams: 5, Kind: models.DeltaStatistics},
+	}
+
+	assert.Equal(t, []models.Statistics{
+		{TrackID: 1, Date: day1, Streams: 100, Kind: models.CumulativeStatistics},
+		{TrackID: 1, Date: day2, Streams: 110, Kind: models.CumulativeStatistics},
+		{TrackID: 1, Date: day3, Streams: 165, Kind: models.CumulativeStatistics},
+		{TrackID: 2, Date: day1, Streams: 1, Kind: models.CumulativeStatistics},
+	}, Totals(stats))
+}
+
+func TestTotals_WorldwideAndCountries(t *testing.T) {
+
+	stats := []models.Statistics{
+		{TrackID: 1, Platform: "spotify", Date: day1, Streams: 100},
+		{TrackID: 1, Platform: "spotify", Country: "GB", Date: day1, Streams: 60},
+		{TrackID: 1, Platform: "spotify", Country: "US", Date: day1, Streams: 40},
+		// deezer reports worldwide only
+		{TrackID: 1, Platform: "deezer", Date: day1, Streams: 10},
+	}
+
+	assert.Equal(t, []models.Statistics{
+		{TrackID: 1, Date: day1, Streams: 110, Kind: models.CumulativeStatistics},
+	}, Totals(stats))
+}
@@ -0,0 +1,88 @@
+package fetcher
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rauzh/cd-core/models"
+	cdtime "github.com/rauzh/cd-core/time"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPJSONFetcher_Fetch(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token", r.Header.Get("Authorization"))
+		assert.Equal(t, "Song 2", r.URL.Query().Get("title"))
+
+		switch r.URL.Path {
+		case "/tracks/1/stats":
+			_, _ = io.WriteString(w, `{"data": {"days": [
+				{"day": "2024-05-01", "counters": {"plays": 10, "likes": "2"}},
+				{"day": "2024-05-02", "counters": {"plays": 12}}
+			]}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	fetcher, err := NewHTTPJSONFetcher(HTTPJSONConfig{
+		URLTemplate: server.URL + "/tracks/{track_id}/stats?title={title}",
+		Headers:     map[string]string{"Authorization": "token"},
+		Mapping: JSONFieldMapping{
+			Items:   "data.days",
+			Streams: "counters.plays",
+			Likes:   "counters.likes",
+			Date:    "day",
+		},
+	})
+	assert.Nil(t, err)
+
+	stats, err := fetcher.Fetch([]models.Track{{TrackID: 1, Title: "Song 2"}})
+	assert.Nil(t, err)
+	assert.Equal(t, []models.Statistics{
+		{TrackID: 1, Date: cdtime.Date(2024, 5, 1), Streams: 10, Likes: 2, Kind: models.CumulativeStatistics},
+		{TrackID: 1, Date: cdtime.Date(2024, 5, 2), Streams: 12, Kind: models.CumulativeStatistics},
+	}, stats)
+
+	_, err = fetcher.Fetch([]models.Track{{TrackID: 2, Title: "Song 2"}})
+	assert.ErrorIs(t, err, ErrUnexpectedStatus)
+}
+
+func TestCSVReportFetcher_Fetch(t *testing.T) {
+
+	report := "date;song;plays;likes;country\n" +
+		"2024-05-01;Song  2;1 000;3; de\n" +
+		"2024-05-01;Unknown track;50;1;DE\n" +
+		"2024-05-01;TUSA;7;;\n"
+
+	fetcher, err := NewCSVReportFetcher(CSVReportConfig{
+		Open:    func() (io.ReadCloser, error) { return io.NopCloser(strings.NewReader(report)), nil },
+		Comma:   ';',
+		Columns: CSVColumns{Title: "song", Streams: "plays", Likes: "likes", Date: "date", Country: "country"},
+	})
+	assert.Nil(t, err)
+
+	stats, err := fetcher.Fetch([]models.Track{{TrackID: 1, Title: "song 2"}, {TrackID: 2, Title: "Tusa"}})
+	assert.Nil(t, err)
+	assert.Equal(t, []models.Statistics{
+		{TrackID: 1, Date: cdtime.Date(2024, 5, 1), Streams: 1000, Likes: 3, Country: "DE", Kind: models.CumulativeStatistics},
+		{TrackID: 2, Date: cdtime.Date(2024, 5, 1), Streams: 7, Kind: models.CumulativeStatistics},
+	}, stats)
+}
+
+func TestCSVReportFetcher_MissingColumn(t *testing.T) {
+
+	fetcher, err := NewCSVReportFetcher(CSVReportConfig{
+		Open:    func() (io.ReadCloser, error) { return io.NopCloser(strings.NewReader("song,likes\n")), nil },
+		Columns: CSVColumns{Title: "song", Streams: "plays"},
+	})
+	assert.Nil(t, err)
+
+	_, err = fetcher.Fetch([]models.Track{{TrackID: 1, Title: "song"}})
+	assert.ErrorIs(t, err, ErrMissingColumn)
+}
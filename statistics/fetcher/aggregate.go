@@ -0,0 +1,112 @@
+package fetcher
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/rauzh/cd-core/models"
+)
+
+var ErrAllPlatformsFailed = errors.New("all streaming platforms failed")
+
+// AggregatingFetcher fetches stats from several platforms and merges them.
+// Every row is tagged with its platform, so the same streams are not summed twice
+type AggregatingFetcher struct {
+	registry  *Registry
+	platforms []string
+
+	logger *slog.Logger
+}
+
+// NewAggregatingFetcher fetches from the given platforms, all registered platforms if none given
+func NewAggregatingFetcher(registry *Registry, logger *slog.Logger, platforms ...string) StatFetcher {
+	return &AggregatingFetcher{
+		registry:  registry,
+		platforms: platforms,
+		logger:    logger,
+	}
+}
+
+type platformResult struct {
+	stats []models.Statistics
+	err   error
+}
+
+// Fetch calls platforms concurrently. A failed platform is logged and skipped,
+// error is returned only if no platform answered
+func (aggregator *AggregatingFetcher) Fetch(tracks []models.Track) ([]models.Statistics, error) {
+
+	platforms := aggregator.platforms
+	if len(platforms) == 0 {
+		platforms = aggregator.registry.Platforms()
+	}
+	if len(platforms) == 0 {
+		return nil, fmt.Errorf("%w: no platforms to fetch from", ErrUnknownPlatform)
+	}
+
+	results := make([]platformResult, len(platforms))
+
+	var wg sync.WaitGroup
+	for i, platform := range platforms {
+		fetcher, err := aggregator.registry.Get(platform)
+		if err != nil {
+			results[i].err = err
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, fetcher StatFetcher) {
+			defer wg.Done()
+			results[i].stats, results[i].err = fetcher.Fetch(tracks)
+		}(i, fetcher)
+	}
+	wg.Wait()
+
+	var errs []error
+	merged := make([]models.Statistics, 0)
+	for i, platform := range platforms {
+		if results[i].err != nil {
+			aggregator.logger.Error("STAT_FETCHER Fetch", "platform", platform, slog.Any("error", results[i].err))
+			errs = append(errs, fmt.Errorf("%s: %w", platform, results[i].err))
+			continue
+		}
+		merged = append(merged, mergePlatform(platform, results[i].stats)...)
+	}
+
+	if len(errs) == len(platforms) {
+		return nil, fmt.Errorf("%w: %w", ErrAllPlatformsFailed, errors.Join(errs...))
+	}
+
+	return merged, nil
+}
+
+type statKey struct {
+	trackID uint64
+	country string
+	date    time.Time
+}
+
+// mergePlatform tags stats with platform and keeps one row per track, country and date,
+// the later row of an adapter replaces the earlier one
+func mergePlatform(platform string, stats []models.Statistics) []models.Statistics {
+
+	index := make(map[statKey]int, len(stats))
+	merged := make([]models.Statistics, 0, len(stats))
+
+	for _, stat := range stats {
+		stat.Platform = platform
+
+		key := statKey{trackID: stat.TrackID, country: stat.Country, date: stat.Date}
+		if i, ok := index[key]; ok {
+			merged[i] = stat
+			continue
+		}
+		index[key] = len(merged)
+		merged = append(merged, stat)
+	}
+
+	return merged
+}
@@ -0,0 +1,67 @@
+package fetcher
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/rauzh/cd-core/models"
+	"github.com/rauzh/cd-core/statistics/fetcher/mocks"
+	cdtime "github.com/rauzh/cd-core/time"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregatingFetcher_Fetch(t *testing.T) {
+
+	tracks := []models.Track{{TrackID: 1}, {TrackID: 2}}
+	day := cdtime.Date(2024, 5, 1)
+
+	spotify := mocks.NewStatFetcher(t)
+	deezer := mocks.NewStatFetcher(t)
+	broken := mocks.NewStatFetcher(t)
+
+	spotify.EXPECT().Fetch(tracks).Return([]models.Statistics{
+		{TrackID: 1, Date: day, Streams: 10},
+		{TrackID: 2, Date: day, Streams: 20},
+		// repeated row of the same platform replaces the first one
+		{TrackID: 1, Date: day, Streams: 15},
+	}, nil)
+	deezer.EXPECT().Fetch(tracks).Return([]models.Statistics{
+		{TrackID: 1, Date: day, Streams: 5, Platform: "wrong"},
+		// rows of other countries are kept
+		{TrackID: 1, Date: day, Streams: 3, Country: "FR"},
+	}, nil)
+	broken.EXPECT().Fetch(tracks).Return(nil, errors.New("platform is down"))
+
+	registry := NewRegistry()
+	assert.Nil(t, registry.Register("spotify", spotify))
+	assert.Nil(t, registry.Register("deezer", deezer))
+	assert.Nil(t, registry.Register("broken", broken))
+	assert.ErrorIs(t, registry.Register("spotify", spotify), ErrPlatformRegistered)
+
+	stats, err := NewAggregatingFetcher(registry, slog.Default()).Fetch(tracks)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []models.Statistics{
+		{TrackID: 1, Date: day, Streams: 5, Platform: "deezer"},
+		{TrackID: 1, Date: day, Streams: 3, Platform: "deezer", Country: "FR"},
+		{TrackID: 1, Date: day, Streams: 15, Platform: "spotify"},
+		{TrackID: 2, Date: day, Streams: 20, Platform: "spotify"},
+	}, stats)
+}
+
+func TestAggregatingFetcher_AllFailed(t *testing.T) {
+
+	tracks := []models.Track{{TrackID: 1}}
+
+	broken := mocks.NewStatFetcher(t)
+	broken.EXPECT().Fetch(tracks).Return(nil, errors.New("platform is down"))
+
+	registry := NewRegistry()
+	assert.Nil(t, registry.Register("broken", broken))
+
+	_, err := NewAggregatingFetcher(registry, slog.Default(), "broken", "unknown").Fetch(tracks)
+
+	assert.ErrorIs(t, err, ErrAllPlatformsFailed)
+	assert.ErrorIs(t, err, ErrUnknownPlatform)
+}
@@ -0,0 +1,215 @@
+package fetcher
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rauzh/cd-core/models"
+)
+
+var (
+	ErrNoReportSource = errors.New("no csv report source in config")
+	ErrMissingColumn  = errors.New("missing column in csv report")
+)
+
+// CSVColumns are header names of report columns. Streams is required,
+// rows are matched to tracks by TrackID column or by Title if there is no TrackID.
+// Country is optional, reports without it are worldwide
+type CSVColumns struct {
+	TrackID string
+	Title   string
+	Streams string
+	Likes   string
+	Date    string
+	Country string
+}
+
+type CSVReportConfig struct {
+	// Open returns the latest report of the platform, it is closed after every Fetch
+	Open func() (io.ReadCloser, error)
+	// Comma is field delimiter, ',' if zero
+	Comma   rune
+	Columns CSVColumns
+	// DateLayout is time layout of Date column, DefaultDateLayout if empty
+	DateLayout string
+	// Kind of report numbers, models.CumulativeStatistics if empty
+	Kind models.StatisticsKind
+}
+
+// CSVReportFetcher is adapter for platforms that only give periodic csv reports
+type CSVReportFetcher struct {
+	cfg CSVReportConfig
+}
+
+func NewCSVReportFetcher(cfg CSVReportConfig) (StatFetcher, error) {
+
+	if cfg.Open == nil {
+		return nil, ErrNoReportSource
+	}
+	if cfg.Columns.Streams == "" {
+		return nil, fmt.Errorf("%w: no streams column", ErrMissingColumn)
+	}
+	if cfg.Columns.TrackID == "" && cfg.Columns.Title == "" {
+		return nil, fmt.Errorf("%w: no track id or title column", ErrMissingColumn)
+	}
+	if cfg.Comma == 0 {
+		cfg.Comma = ','
+	}
+	if cfg.Kind == "" {
+		cfg.Kind = models.CumulativeStatistics
+	}
+	if cfg.DateLayout == "" {
+		cfg.DateLayout = DefaultDateLayout
+	}
+
+	return &CSVReportFetcher{cfg: cfg}, nil
+}
+
+func (fetcher *CSVReportFetcher) Fetch(tracks []models.Track) ([]models.Statistics, error) {
+
+	report, err := fetcher.cfg.Open()
+	if err != nil {
+		return nil, fmt.Errorf("can't open csv report with err %w", err)
+	}
+	defer report.Close()
+
+	reader := csv.NewReader(report)
+	reader.Comma = fetcher.cfg.Comma
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("can't read csv report header with err %w", err)
+	}
+
+	columns, err := fetcher.columnIndexes(header)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]uint64, len(tracks))
+	byTitle := make(map[string]uint64, len(tracks))
+	for _, track := range tracks {
+		byID[strconv.FormatUint(track.TrackID, 10)] = track.TrackID
+		byTitle[normalizeTitle(track.Title)] = track.TrackID
+	}
+
+	stats := make([]models.Statistics, 0, len(tracks))
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("can't read csv report with err %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		var trackID uint64
+		var ok bool
+		if columns.trackID >= 0 {
+			trackID, ok = byID[strings.TrimSpace(row[columns.trackID])]
+		} else {
+			trackID, ok = byTitle[normalizeTitle(row[columns.title])]
+		}
+		if !ok {
+			continue
+		}
+
+		stat := models.Statistics{TrackID: trackID, Kind: fetcher.cfg.Kind}
+
+		if stat.Streams, err = parseCount(row, columns.streams); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if stat.Likes, err = parseCount(row, columns.likes); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if columns.date >= 0 {
+			if stat.Date, err = time.Parse(fetcher.cfg.DateLayout, strings.TrimSpace(row[columns.date])); err != nil {
+				return nil, fmt.Errorf("line %d: can't parse date with err %w", line, err)
+			}
+		}
+		if columns.country >= 0 {
+			stat.Country = models.NormalizeCountry(row[columns.country])
+		}
+
+		stats = append(stats, stat)
+	}
+
+	return stats, nil
+}
+
+type csvColumnIndexes struct {
+	trackID, title, streams, likes, date, country int
+}
+
+func (fetcher *CSVReportFetcher) columnIndexes(header []string) (csvColumnIndexes, error) {
+
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		positions[strings.TrimSpace(name)] = i
+	}
+
+	index := func(name string, required bool) (int, error) {
+		if name == "" {
+			return -1, nil
+		}
+		i, ok := positions[name]
+		if !ok {
+			if required {
+				return -1, fmt.Errorf("%w: %s", ErrMissingColumn, name)
+			}
+			return -1, nil
+		}
+		return i, nil
+	}
+
+	cols := fetcher.cfg.Columns
+	var indexes csvColumnIndexes
+	var err error
+
+	if indexes.trackID, err = index(cols.TrackID, false); err != nil {
+		return indexes, err
+	}
+	if indexes.title, err = index(cols.Title, indexes.trackID < 0); err != nil {
+		return indexes, err
+	}
+	if indexes.streams, err = index(cols.Streams, true); err != nil {
+		return indexes, err
+	}
+	if indexes.likes, err = index(cols.Likes, false); err != nil {
+		return indexes, err
+	}
+	if indexes.date, err = index(cols.Date, false); err != nil {
+		return indexes, err
+	}
+	if indexes.country, err = index(cols.Country, false); err != nil {
+		return indexes, err
+	}
+
+	return indexes, nil
+}
+
+func parseCount(row []string, i int) (uint64, error) {
+	if i < 0 {
+		return 0, nil
+	}
+	raw := strings.ReplaceAll(strings.TrimSpace(row[i]), " ", "")
+	if raw == "" {
+		return 0, nil
+	}
+	count, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("can't parse count %q with err %w", row[i], err)
+	}
+	return count, nil
+}
+
+func normalizeTitle(title string) string {
+	return strings.ToLower(strings.Join(strings.Fields(title), " "))
+}
@@ -2,6 +2,9 @@ package fetcher
 
 import "github.com/rauzh/cd-core/models"
 
+// StatFetcher returns stats of the tracks. A track may have a row per date and country,
+// rows with empty Country are worldwide. Platform is set by NewAggregatingFetcher
+//
 //go:generate mockery --name StatFetcher --with-expecter
 type StatFetcher interface {
 	Fetch(tracks []models.Track) ([]models.Statistics, error)
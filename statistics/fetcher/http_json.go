@@ -0,0 +1,265 @@
+package fetcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rauzh/cd-core/models"
+)
+
+var (
+	ErrUnexpectedStatus = errors.New("unexpected http status")
+	ErrBadField         = errors.New("bad field in platform response")
+	ErrNoURLTemplate    = errors.New("no url template in config")
+)
+
+const (
+	PlaceholderTrackID = "{track_id}"
+	PlaceholderTitle   = "{title}"
+
+	DefaultDateLayout = "2006-01-02"
+)
+
+// JSONFieldMapping is a set of dot separated paths, like "data.stats.plays".
+// Streams is required, other fields are optional
+type JSONFieldMapping struct {
+	// Items is the path of the array with per date (and per country) stats, the whole response is one item if empty
+	Items   string
+	Streams string
+	Likes   string
+	Date    string
+	// Country is the path of ISO 3166-1 alpha-2 code, numbers are worldwide if empty
+	Country string
+	// DateLayout is time layout of Date, DefaultDateLayout if empty
+	DateLayout string
+}
+
+type HTTPJSONConfig struct {
+	// URLTemplate is requested per track, PlaceholderTrackID and PlaceholderTitle are replaced
+	URLTemplate string
+	Headers     map[string]string
+	Mapping     JSONFieldMapping
+	// Kind of returned numbers, models.CumulativeStatistics if empty
+	Kind models.StatisticsKind
+
+	// Client is http.DefaultClient with Timeout if nil
+	Client  *http.Client
+	Timeout time.Duration
+}
+
+// HTTPJSONFetcher is adapter for platforms with per track json stats api
+type HTTPJSONFetcher struct {
+	cfg    HTTPJSONConfig
+	client *http.Client
+}
+
+func NewHTTPJSONFetcher(cfg HTTPJSONConfig) (StatFetcher, error) {
+
+	if cfg.URLTemplate == "" {
+		return nil, ErrNoURLTemplate
+	}
+	if cfg.Mapping.Streams == "" {
+		return nil, fmt.Errorf("%w: no streams path", ErrBadField)
+	}
+	if cfg.Kind == "" {
+		cfg.Kind = models.CumulativeStatistics
+	}
+	if cfg.Mapping.DateLayout == "" {
+		cfg.Mapping.DateLayout = DefaultDateLayout
+	}
+
+	client := cfg.Client
+	if client == nil {
+		client = &http.Client{Timeout: cfg.Timeout}
+	}
+
+	return &HTTPJSONFetcher{cfg: cfg, client: client}, nil
+}
+
+func (fetcher *HTTPJSONFetcher) Fetch(tracks []models.Track) ([]models.Statistics, error) {
+
+	stats := make([]models.Statistics, 0, len(tracks))
+	for _, track := range tracks {
+		trackStats, err := fetcher.fetchTrack(track)
+		if err != nil {
+			return nil, fmt.Errorf("can't fetch stats for track %d with err %w", track.TrackID, err)
+		}
+		stats = append(stats, trackStats...)
+	}
+
+	return stats, nil
+}
+
+func (fetcher *HTTPJSONFetcher) trackURL(track models.Track) string {
+	return strings.NewReplacer(
+		PlaceholderTrackID, strconv.FormatUint(track.TrackID, 10),
+		PlaceholderTitle, url.QueryEscape(track.Title),
+	).Replace(fetcher.cfg.URLTemplate)
+}
+
+func (fetcher *HTTPJSONFetcher) fetchTrack(track models.Track) ([]models.Statistics, error) {
+
+	req, err := http.NewRequest(http.MethodGet, fetcher.trackURL(track), nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range fetcher.cfg.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := fetcher.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %d", ErrUnexpectedStatus, resp.StatusCode)
+	}
+
+	return fetcher.parse(track.TrackID, body)
+}
+
+func (fetcher *HTTPJSONFetcher) parse(trackID uint64, body []byte) ([]models.Statistics, error) {
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("can't decode platform response with err %w", err)
+	}
+
+	mapping := fetcher.cfg.Mapping
+
+	items := []any{doc}
+	if mapping.Items != "" {
+		value, ok := lookup(doc, mapping.Items)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s not found", ErrBadField, mapping.Items)
+		}
+		if items, ok = value.([]any); !ok {
+			return nil, fmt.Errorf("%w: %s is not an array", ErrBadField, mapping.Items)
+		}
+	}
+
+	stats := make([]models.Statistics, 0, len(items))
+	for _, item := range items {
+		stat := models.Statistics{TrackID: trackID, Kind: fetcher.cfg.Kind}
+
+		var err error
+		if stat.Streams, err = uintField(item, mapping.Streams, true); err != nil {
+			return nil, err
+		}
+		if stat.Likes, err = uintField(item, mapping.Likes, false); err != nil {
+			return nil, err
+		}
+		if stat.Date, err = dateField(item, mapping.Date, mapping.DateLayout); err != nil {
+			return nil, err
+		}
+		if stat.Country, err = stringField(item, mapping.Country); err != nil {
+			return nil, err
+		}
+
+		stats = append(stats, stat)
+	}
+
+	return stats, nil
+}
+
+func lookup(doc any, path string) (any, bool) {
+	value := doc
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+func uintField(item any, path string, required bool) (uint64, error) {
+	if path == "" {
+		return 0, nil
+	}
+
+	value, ok := lookup(item, path)
+	if !ok {
+		if required {
+			return 0, fmt.Errorf("%w: %s not found", ErrBadField, path)
+		}
+		return 0, nil
+	}
+
+	var raw string
+	switch v := value.(type) {
+	case json.Number:
+		raw = v.String()
+	case string:
+		raw = v
+	default:
+		return 0, fmt.Errorf("%w: %s is not a number", ErrBadField, path)
+	}
+
+	number, err := strconv.ParseUint(strings.TrimSpace(raw), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s with err %w", ErrBadField, path, err)
+	}
+	return number, nil
+}
+
+func stringField(item any, path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+
+	value, ok := lookup(item, path)
+	if !ok || value == nil {
+		return "", nil
+	}
+
+	raw, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("%w: %s is not a string", ErrBadField, path)
+	}
+	return models.NormalizeCountry(raw), nil
+}
+
+// dateField returns zero date if there is no date, so the caller sets the fetch date
+func dateField(item any, path, layout string) (time.Time, error) {
+	if path == "" {
+		return time.Time{}, nil
+	}
+
+	value, ok := lookup(item, path)
+	if !ok {
+		return time.Time{}, nil
+	}
+
+	raw, ok := value.(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("%w: %s is not a string", ErrBadField, path)
+	}
+
+	date, err := time.Parse(layout, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s with err %w", ErrBadField, path, err)
+	}
+	return date.UTC(), nil
+}
@@ -0,0 +1,58 @@
+package fetcher
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	ErrUnknownPlatform    = errors.New("unknown streaming platform")
+	ErrPlatformRegistered = errors.New("streaming platform is already registered")
+)
+
+// Registry keeps a fetcher adapter per streaming platform
+type Registry struct {
+	mu       sync.RWMutex
+	fetchers map[string]StatFetcher
+}
+
+func NewRegistry() *Registry {
+	return &Registry{fetchers: make(map[string]StatFetcher)}
+}
+
+func (registry *Registry) Register(platform string, fetcher StatFetcher) error {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if _, ok := registry.fetchers[platform]; ok {
+		return fmt.Errorf("%w: %s", ErrPlatformRegistered, platform)
+	}
+	registry.fetchers[platform] = fetcher
+	return nil
+}
+
+func (registry *Registry) Get(platform string) (StatFetcher, error) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	fetcher, ok := registry.fetchers[platform]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownPlatform, platform)
+	}
+	return fetcher, nil
+}
+
+// Platforms returns registered platforms in alphabetical order
+func (registry *Registry) Platforms() []string {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	platforms := make([]string, 0, len(registry.fetchers))
+	for platform := range registry.fetchers {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+	return platforms
+}
@@ -0,0 +1,104 @@
+// Package forecast predicts streams with exponential smoothing.
+// Short histories get simple exponential smoothing, longer ones Holt linear trend.
+// Smoothing factors are picked by a grid search over one step ahead errors
+package forecast
+
+import (
+	"errors"
+	"math"
+)
+
+type Method string
+
+const (
+	SimpleExponential Method = "ses"
+	HoltLinear        Method = "holt"
+)
+
+const (
+	// MinHistory is the number of periods needed for any forecast
+	MinHistory = 2
+	// MinTrendHistory is the number of periods from which the trend is trusted
+	MinTrendHistory = 6
+)
+
+var ErrNotEnoughHistory = errors.New("not enough history to forecast")
+
+// grid of alpha and beta for Fit
+var grid = []float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9}
+
+type Model struct {
+	Method Method
+	Alpha  float64
+	Beta   float64
+	// SSE is the sum of squared one step ahead errors over the history
+	SSE float64
+
+	level float64
+	trend float64
+}
+
+// SES fits simple exponential smoothing, the forecast is flat
+func SES(values []float64, alpha float64) Model {
+
+	model := Model{Method: SimpleExponential, Alpha: alpha, level: values[0]}
+	for _, value := range values[1:] {
+		err := value - model.level
+		model.SSE += err * err
+		model.level += alpha * err
+	}
+
+	return model
+}
+
+// Holt fits Holt linear trend, values must have at least two periods
+func Holt(values []float64, alpha, beta float64) Model {
+
+	model := Model{Method: HoltLinear, Alpha: alpha, Beta: beta, level: values[0], trend: values[1] - values[0]}
+	for _, value := range values[1:] {
+		err := value - (model.level + model.trend)
+		model.SSE += err * err
+
+		prevLevel := model.level
+		model.level = alpha*value + (1-alpha)*(model.level+model.trend)
+		model.trend = beta*(model.level-prevLevel) + (1-beta)*model.trend
+	}
+
+	return model
+}
+
+// Fit picks the method by the history length and the factors with the least SSE
+func Fit(values []float64) (Model, error) {
+
+	if len(values) < MinHistory {
+		return Model{}, ErrNotEnoughHistory
+	}
+
+	best := Model{SSE: math.Inf(1)}
+	for _, alpha := range grid {
+		if len(values) < MinTrendHistory {
+			if model := SES(values, alpha); model.SSE < best.SSE {
+				best = model
+			}
+			continue
+		}
+		for _, beta := range grid {
+			if model := Holt(values, alpha, beta); model.SSE < best.SSE {
+				best = model
+			}
+		}
+	}
+
+	return best, nil
+}
+
+// Predict returns the next horizon periods, streams can't go below zero
+func (model Model) Predict(horizon int) []float64 {
+
+	predictions := make([]float64, horizon)
+	for h := range predictions {
+		predictions[h] = math.Max(model.level+float64(h+1)*model.trend, 0)
+	}
+
+	return predictions
+}
@@ -0,0 +1,32 @@
+package forecast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFit(t *testing.T) {
+
+	_, err := Fit([]float64{10})
+	assert.ErrorIs(t, err, ErrNotEnoughHistory)
+
+	// short history is flat
+	model, err := Fit([]float64{100, 100, 100})
+	assert.Nil(t, err)
+	assert.Equal(t, SimpleExponential, model.Method)
+	assert.Equal(t, []float64{100, 100}, model.Predict(2))
+
+	// steady growth continues
+	model, err = Fit([]float64{100, 110, 120, 130, 140, 150, 160, 170})
+	assert.Nil(t, err)
+	assert.Equal(t, HoltLinear, model.Method)
+	predictions := model.Predict(3)
+	assert.InDelta(t, 180, predictions[0], 0.001)
+	assert.InDelta(t, 200, predictions[2], 0.001)
+
+	// fading track does not go below zero
+	model, err = Fit([]float64{70, 60, 50, 40, 30, 20, 10})
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{0, 0}, model.Predict(3)[1:])
+}
@@ -0,0 +1,450 @@
+package importer
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	repoErrors "github.com/rauzh/cd-core/errors/repo"
+	"github.com/rauzh/cd-core/models"
+	"github.com/rauzh/cd-core/repo"
+	cdtime "github.com/rauzh/cd-core/time"
+	"github.com/rauzh/cd-core/transactor"
+)
+
+var (
+	ErrMissingColumn = errors.New("missing column in report")
+	ErrNoTrack       = errors.New("can't resolve track")
+	ErrBadValue      = errors.New("bad value in report")
+	ErrFutureDate    = errors.New("date is in the future")
+)
+
+const DefaultDateLayout = "2006-01-02"
+
+// Columns are header names of report columns. Rows are resolved to tracks by ISRC,
+// or by Title and Artist nickname if ISRC is empty. Country is optional, rows without it are worldwide
+type Columns struct {
+	ISRC    string
+	Title   string
+	Artist  string
+	Date    string
+	Streams string
+	Likes   string
+	Country string
+}
+
+type Config struct {
+	Columns Columns
+	// Comma is field delimiter, detected from header (tab, semicolon or comma) if zero
+	Comma rune
+	// DateLayout is time layout of Date column, DefaultDateLayout if empty
+	DateLayout string
+	// Platform is stored with imported stats, usually the distributor name
+	Platform string
+	// Kind of report numbers, models.DeltaStatistics if empty as distributors report streams per period
+	Kind models.StatisticsKind
+}
+
+// RowError is a problem with a single report row, such rows are skipped
+type RowError struct {
+	Line int
+	Err  error
+}
+
+func (rowErr RowError) Error() string {
+	return fmt.Sprintf("line %d: %s", rowErr.Line, rowErr.Err)
+}
+
+func (rowErr RowError) Unwrap() error {
+	return rowErr.Err
+}
+
+type Report struct {
+	Imported int
+	// Duplicates are rows already stored or repeated in the report
+	Duplicates int
+	Errors     []RowError
+}
+
+// Importer loads distributor statistics reports
+type Importer struct {
+	trackRepo  repo.TrackRepo
+	artistRepo repo.ArtistRepo
+	statRepo   repo.StatisticsRepo
+	transactor transactor.Transactor
+
+	logger *slog.Logger
+}
+
+func NewImporter(
+	trackRepo repo.TrackRepo,
+	artistRepo repo.ArtistRepo,
+	statRepo repo.StatisticsRepo,
+	transactor transactor.Transactor,
+	logger *slog.Logger) *Importer {
+	return &Importer{
+		trackRepo:  trackRepo,
+		artistRepo: artistRepo,
+		statRepo:   statRepo,
+		transactor: transactor,
+		logger:     logger,
+	}
+}
+
+type statKey struct {
+	trackID uint64
+	country string
+	date    time.Time
+}
+
+// Import stores valid rows of the report in one transaction.
+// Bad rows are skipped and listed in the report, error is returned
+// only if the report can't be read or stored at all
+func (importer *Importer) Import(ctx context.Context, report io.Reader, cfg Config) (*Report, error) {
+
+	if cfg.DateLayout == "" {
+		cfg.DateLayout = DefaultDateLayout
+	}
+	if cfg.Kind == "" {
+		cfg.Kind = models.DeltaStatistics
+	}
+
+	reader, err := newCSVReader(report, cfg.Comma)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("can't read report header with err %w", err)
+	}
+
+	columns, err := cfg.Columns.indexes(header)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Report{}
+
+	err = importer.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+
+		res := newResolver(importer.trackRepo, importer.artistRepo, importer.statRepo)
+		seen := make(map[statKey]struct{})
+		stats := make([]models.Statistics, 0)
+
+		for {
+			row, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			line, _ := reader.FieldPos(0)
+			if err != nil {
+				var parseErr *csv.ParseError
+				if errors.As(err, &parseErr) {
+					result.Errors = append(result.Errors, RowError{Line: parseErr.Line, Err: err})
+					continue
+				}
+				return fmt.Errorf("can't read report with err %w", err)
+			}
+
+			stat, err := columns.parse(row, cfg.DateLayout)
+			if err != nil {
+				result.Errors = append(result.Errors, RowError{Line: line, Err: err})
+				continue
+			}
+
+			stat.TrackID, err = res.track(ctx, columns.value(row, columns.isrc),
+				columns.value(row, columns.title), columns.value(row, columns.artist))
+			if errors.Is(err, ErrNoTrack) {
+				result.Errors = append(result.Errors, RowError{Line: line, Err: err})
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("line %d: can't resolve track with err %w", line, err)
+			}
+			stat.Platform = cfg.Platform
+			stat.Kind = cfg.Kind
+
+			key := statKey{trackID: stat.TrackID, country: stat.Country, date: stat.Date}
+			stored, err := res.stored(ctx, stat.TrackID, cfg.Platform)
+			if err != nil {
+				return err
+			}
+			if _, ok := seen[key]; ok || stored[key] {
+				result.Duplicates++
+				continue
+			}
+			seen[key] = struct{}{}
+
+			stats = append(stats, *stat)
+		}
+
+		if len(stats) == 0 {
+			return nil
+		}
+
+		if err := importer.statRepo.CreateMany(ctx, stats); err != nil {
+			return fmt.Errorf("can't create stats with err %w", err)
+		}
+		result.Imported = len(stats)
+
+		return nil
+	})
+	if err != nil {
+		importer.logger.Error("STAT_IMPORTER Import", "platform", cfg.Platform, slog.Any("error", err))
+		return nil, err
+	}
+
+	importer.logger.Info("STAT_IMPORTER Import", "platform", cfg.Platform,
+		"imported", result.Imported, "duplicates", result.Duplicates, "errors", len(result.Errors))
+
+	return result, nil
+}
+
+// newCSVReader detects delimiter from the header line if comma is zero
+func newCSVReader(report io.Reader, comma rune) (*csv.Reader, error) {
+
+	buffered := bufio.NewReader(report)
+
+	if comma == 0 {
+		header, err := buffered.Peek(buffered.Size())
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+			return nil, fmt.Errorf("can't read report header with err %w", err)
+		}
+		if end := strings.IndexByte(string(header), '\n'); end >= 0 {
+			header = header[:end]
+		}
+		comma = detectComma(string(header))
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	return reader, nil
+}
+
+func detectComma(header string) rune {
+	comma, count := ',', strings.Count(header, ",")
+	for _, candidate := range []rune{'\t', ';'} {
+		if n := strings.Count(header, string(candidate)); n > count {
+			comma, count = candidate, n
+		}
+	}
+	return comma
+}
+
+type columnIndexes struct {
+	isrc, title, artist, date, streams, likes, country int
+}
+
+func (cols Columns) indexes(header []string) (*columnIndexes, error) {
+
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		// excel adds byte order mark to the first column
+		name = strings.TrimPrefix(name, "\ufeff")
+		positions[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	index := func(name string) int {
+		if name == "" {
+			return -1
+		}
+		if i, ok := positions[strings.ToLower(name)]; ok {
+			return i
+		}
+		return -1
+	}
+
+	indexes := &columnIndexes{
+		isrc:    index(cols.ISRC),
+		title:   index(cols.Title),
+		artist:  index(cols.Artist),
+		date:    index(cols.Date),
+		streams: index(cols.Streams),
+		likes:   index(cols.Likes),
+		country: index(cols.Country),
+	}
+
+	if indexes.date < 0 {
+		return nil, fmt.Errorf("%w: date %q", ErrMissingColumn, cols.Date)
+	}
+	if indexes.streams < 0 {
+		return nil, fmt.Errorf("%w: streams %q", ErrMissingColumn, cols.Streams)
+	}
+	if indexes.isrc < 0 && (indexes.title < 0 || indexes.artist < 0) {
+		return nil, fmt.Errorf("%w: isrc or title and artist", ErrMissingColumn)
+	}
+
+	return indexes, nil
+}
+
+func (indexes *columnIndexes) value(row []string, i int) string {
+	if i < 0 || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+// parse validates row values, track is resolved later
+func (indexes *columnIndexes) parse(row []string, dateLayout string) (*models.Statistics, error) {
+
+	stat := &models.Statistics{}
+
+	rawDate := indexes.value(row, indexes.date)
+	date, err := time.Parse(dateLayout, rawDate)
+	if err != nil {
+		return nil, fmt.Errorf("%w: date %q", ErrBadValue, rawDate)
+	}
+	stat.Date = date.UTC()
+	if stat.Date.After(cdtime.GetToday()) {
+		return nil, fmt.Errorf("%w: %s", ErrFutureDate, rawDate)
+	}
+
+	if stat.Streams, err = parseCount(indexes.value(row, indexes.streams), true); err != nil {
+		return nil, fmt.Errorf("streams: %w", err)
+	}
+	if stat.Likes, err = parseCount(indexes.value(row, indexes.likes), false); err != nil {
+		return nil, fmt.Errorf("likes: %w", err)
+	}
+
+	stat.Country = models.NormalizeCountry(indexes.value(row, indexes.country))
+
+	return stat, nil
+}
+
+// parseCount accepts thousands separated numbers like "1 000" or "1,000"
+func parseCount(raw string, required bool) (uint64, error) {
+	raw = strings.NewReplacer(" ", "", ",", "", "\u00a0", "").Replace(raw)
+	if raw == "" {
+		if required {
+			return 0, fmt.Errorf("%w: empty", ErrBadValue)
+		}
+		return 0, nil
+	}
+
+	count, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrBadValue, raw)
+	}
+	return count, nil
+}
+
+// resolver caches tracks and stored stats lookups, reports repeat them on every row
+type resolver struct {
+	trackRepo  repo.TrackRepo
+	artistRepo repo.ArtistRepo
+	statRepo   repo.StatisticsRepo
+
+	byISRC   map[string]uint64
+	byTitle  map[string]uint64
+	byArtist map[string]uint64
+	stats    map[uint64]map[statKey]bool
+}
+
+func newResolver(trackRepo repo.TrackRepo, artistRepo repo.ArtistRepo, statRepo repo.StatisticsRepo) *resolver {
+	return &resolver{
+		trackRepo:  trackRepo,
+		artistRepo: artistRepo,
+		statRepo:   statRepo,
+		byISRC:     make(map[string]uint64),
+		byTitle:    make(map[string]uint64),
+		byArtist:   make(map[string]uint64),
+		stats:      make(map[uint64]map[statKey]bool),
+	}
+}
+
+func (res *resolver) track(ctx context.Context, isrc, title, artist string) (uint64, error) {
+
+	if isrc != "" {
+		isrc = strings.ToUpper(strings.ReplaceAll(isrc, "-", ""))
+		if trackID, ok := res.byISRC[isrc]; ok {
+			return trackID, nil
+		}
+
+		track, err := res.trackRepo.GetByISRC(ctx, isrc)
+		if err == nil {
+			res.byISRC[isrc] = track.TrackID
+			return track.TrackID, nil
+		}
+		if !errors.Is(err, repoErrors.ErrorNotExists) {
+			return 0, err
+		}
+	}
+
+	if title == "" || artist == "" {
+		return 0, fmt.Errorf("%w: isrc %q", ErrNoTrack, isrc)
+	}
+
+	titleKey := strings.ToLower(artist) + "\x00" + strings.ToLower(title)
+	if trackID, ok := res.byTitle[titleKey]; ok {
+		return trackID, nil
+	}
+
+	artistID, err := res.artist(ctx, artist)
+	if err != nil {
+		return 0, err
+	}
+
+	track, err := res.trackRepo.GetByTitleAndArtist(ctx, title, artistID)
+	if errors.Is(err, repoErrors.ErrorNotExists) {
+		return 0, fmt.Errorf("%w: %q by %q", ErrNoTrack, title, artist)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	res.byTitle[titleKey] = track.TrackID
+	return track.TrackID, nil
+}
+
+func (res *resolver) artist(ctx context.Context, nickname string) (uint64, error) {
+
+	key := strings.ToLower(nickname)
+	if artistID, ok := res.byArtist[key]; ok {
+		return artistID, nil
+	}
+
+	artist, err := res.artistRepo.GetByNickname(ctx, nickname)
+	if errors.Is(err, repoErrors.ErrorNotExists) {
+		return 0, fmt.Errorf("%w: unknown artist %q", ErrNoTrack, nickname)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	res.byArtist[key] = artist.ArtistID
+	return artist.ArtistID, nil
+}
+
+// stored returns keys of already stored stats of the track from the platform
+func (res *resolver) stored(ctx context.Context, trackID uint64, platform string) (map[statKey]bool, error) {
+
+	if keys, ok := res.stats[trackID]; ok {
+		return keys, nil
+	}
+
+	stats, err := res.statRepo.GetForTrack(ctx, trackID)
+	if err != nil {
+		return nil, fmt.Errorf("can't get stats for track %d with err %w", trackID, err)
+	}
+
+	keys := make(map[statKey]bool, len(stats))
+	for _, stat := range stats {
+		if stat.Platform == platform {
+			keys[statKey{trackID: trackID, country: stat.Country, date: stat.Date.UTC()}] = true
+		}
+	}
+
+	res.stats[trackID] = keys
+	return keys, nil
+}
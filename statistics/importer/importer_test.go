@@ -0,0 +1,92 @@
+package importer
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	repoErrors "github.com/rauzh/cd-core/errors/repo"
+	"github.com/rauzh/cd-core/models"
+	"github.com/rauzh/cd-core/repo/mocks"
+	cdtime "github.com/rauzh/cd-core/time"
+	transacMock "github.com/rauzh/cd-core/transactor/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var distributorColumns = Columns{
+	ISRC:    "ISRC",
+	Title:   "Track",
+	Artist:  "Artist",
+	Date:    "Date",
+	Streams: "Streams",
+	Likes:   "Saves",
+}
+
+func TestImporter_Import(t *testing.T) {
+
+	trackRepo := mocks.NewTrackRepo(t)
+	artistRepo := mocks.NewArtistRepo(t)
+	statRepo := mocks.NewStatisticsRepo(t)
+	transactor := transacMock.NewTransactor(t)
+
+	transactor.EXPECT().WithinTransaction(mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) })
+
+	trackRepo.EXPECT().GetByISRC(mock.Anything, "USRC17607839").Return(&models.Track{TrackID: 1}, nil).Once()
+	trackRepo.EXPECT().GetByISRC(mock.Anything, "XX0000000000").Return(nil, repoErrors.ErrorNotExists).Once()
+	artistRepo.EXPECT().GetByNickname(mock.Anything, "Zemfira").Return(&models.Artist{ArtistID: 7}, nil).Once()
+	trackRepo.EXPECT().GetByTitleAndArtist(mock.Anything, "Iskala", uint64(7)).Return(&models.Track{TrackID: 2}, nil).Once()
+
+	statRepo.EXPECT().GetForTrack(mock.Anything, uint64(1)).Return([]models.Statistics{
+		{TrackID: 1, Date: cdtime.Date(2024, 4, 1), Platform: "believe"},
+		{TrackID: 1, Date: cdtime.Date(2024, 4, 2), Platform: "spotify"},
+	}, nil).Once()
+	statRepo.EXPECT().GetForTrack(mock.Anything, uint64(2)).Return(nil, nil).Once()
+
+	statRepo.EXPECT().CreateMany(mock.Anything, []models.Statistics{
+		{TrackID: 1, Date: cdtime.Date(2024, 4, 2), Streams: 1200, Likes: 3, Platform: "believe", Kind: models.DeltaStatistics},
+		{TrackID: 2, Date: cdtime.Date(2024, 4, 1), Streams: 50, Platform: "believe", Kind: models.DeltaStatistics},
+	}).Return(nil).Once()
+
+	report := "\ufeffISRC\tTrack\tArtist\tDate\tStreams\tSaves\n" +
+		"US-RC1-76-07839\tSong 2\tBlur\t2024-04-01\t1000\t2\n" + // already stored
+		"USRC17607839\tSong 2\tBlur\t2024-04-02\t1,200\t3\n" +
+		"USRC17607839\tSong 2\tBlur\t2024-04-02\t1200\t3\n" + // repeated in report
+		"\tIskala\tZemfira\t2024-04-01\t50\t\n" +
+		"XX0000000000\t\t\t2024-04-01\t10\t\n" +
+		"USRC17607839\tSong 2\tBlur\t2024-04-03\tmany\t\n" +
+		"USRC17607839\tSong 2\tBlur\t2999-01-01\t1\t\n"
+
+	importer := NewImporter(trackRepo, artistRepo, statRepo, transactor, slog.Default())
+
+	result, err := importer.Import(context.Background(), strings.NewReader(report), Config{
+		Columns:  distributorColumns,
+		Platform: "believe",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, result.Imported)
+	assert.Equal(t, 2, result.Duplicates)
+	if assert.Len(t, result.Errors, 3) {
+		assert.Equal(t, 6, result.Errors[0].Line)
+		assert.ErrorIs(t, result.Errors[0], ErrNoTrack)
+		assert.Equal(t, 7, result.Errors[1].Line)
+		assert.ErrorIs(t, result.Errors[1], ErrBadValue)
+		assert.Equal(t, 8, result.Errors[2].Line)
+		assert.ErrorIs(t, result.Errors[2], ErrFutureDate)
+	}
+}
+
+func TestImporter_MissingColumn(t *testing.T) {
+
+	importer := NewImporter(mocks.NewTrackRepo(t), mocks.NewArtistRepo(t), mocks.NewStatisticsRepo(t),
+		transacMock.NewTransactor(t), slog.Default())
+
+	_, err := importer.Import(context.Background(), strings.NewReader("Track,Date,Streams\n"), Config{
+		Columns: distributorColumns,
+	})
+
+	assert.ErrorIs(t, err, ErrMissingColumn)
+}
@@ -0,0 +1,253 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	repoErrors "github.com/rauzh/cd-core/errors/repo"
+	"github.com/rauzh/cd-core/models"
+	"github.com/rauzh/cd-core/repo"
+	statService "github.com/rauzh/cd-core/statistics/service"
+	cdtime "github.com/rauzh/cd-core/time"
+	"github.com/rauzh/cd-core/transactor"
+)
+
+type Config struct {
+	// Interval between collection runs, the first run starts at once
+	Interval time.Duration
+	// Concurrency is the number of releases fetched at the same time
+	Concurrency int
+	// Jitter is the max random delay before each release fetch,
+	// so platforms are not hit by all releases at once
+	Jitter time.Duration
+	// Platforms returns platforms stats are fetched from, usually Platforms of the fetcher registry.
+	// Release is skipped only if all of them are fetched today, releases are never skipped if nil
+	Platforms func() []string
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Interval:    6 * time.Hour,
+		Concurrency: 4,
+		Jitter:      5 * time.Second,
+	}
+}
+
+// RunReport sums up one collection run
+type RunReport struct {
+	Fetched int
+	// Skipped are releases already fetched today, not published anymore
+	// or with no stats on platforms yet
+	Skipped int
+	Failed  map[uint64]error
+}
+
+// Scheduler periodically fetches statistics of all published releases.
+// The latest stored date of every release and platform is kept as watermark
+// and stored in one transaction with the stats,
+// so restarted scheduler neither stores the same days again nor skips days
+type Scheduler struct {
+	statService statService.IStatisticsService
+	statRepo    repo.StatisticsRepo
+	pbcRepo     repo.PublicationRepo
+	releaseRepo repo.ReleaseRepo
+	watermarks  repo.StatisticsWatermarkRepo
+	transactor  transactor.Transactor
+
+	cfg Config
+
+	logger *slog.Logger
+}
+
+func NewScheduler(
+	statSvc statService.IStatisticsService,
+	statRepo repo.StatisticsRepo,
+	pbcRepo repo.PublicationRepo,
+	releaseRepo repo.ReleaseRepo,
+	watermarks repo.StatisticsWatermarkRepo,
+	t transactor.Transactor,
+	cfg Config,
+	logger *slog.Logger) *Scheduler {
+
+	defaults := DefaultConfig()
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaults.Interval
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = defaults.Concurrency
+	}
+
+	return &Scheduler{
+		statService: statSvc,
+		statRepo:    statRepo,
+		pbcRepo:     pbcRepo,
+		releaseRepo: releaseRepo,
+		watermarks:  watermarks,
+		transactor:  t,
+		cfg:         cfg,
+		logger:      logger,
+	}
+}
+
+// Run collects statistics every interval until ctx is done
+func (scheduler *Scheduler) Run(ctx context.Context) error {
+
+	ticker := time.NewTicker(scheduler.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		report, err := scheduler.RunOnce(ctx)
+		if err != nil {
+			scheduler.logger.Error("STAT_SCHEDULER Run", slog.Any("error", err))
+		} else {
+			scheduler.logger.Info("STAT_SCHEDULER Run",
+				"fetched", report.Fetched, "skipped", report.Skipped, "failed", len(report.Failed))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce fetches statistics of every published release once.
+// Failure of a release is logged and reported, it does not stop the others
+func (scheduler *Scheduler) RunOnce(ctx context.Context) (*RunReport, error) {
+
+	today := cdtime.GetToday()
+
+	publications, err := scheduler.pbcRepo.GetAllUntilDate(ctx, today)
+	if err != nil {
+		return nil, fmt.Errorf("can't get publications with err %w", err)
+	}
+
+	report := &RunReport{Failed: make(map[uint64]error)}
+	var mu sync.Mutex
+
+	sem := make(chan struct{}, scheduler.cfg.Concurrency)
+	var wg sync.WaitGroup
+
+	seen := make(map[uint64]struct{}, len(publications))
+	for _, publication := range publications {
+		if _, ok := seen[publication.ReleaseID]; ok {
+			continue
+		}
+		seen[publication.ReleaseID] = struct{}{}
+
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return report, ctx.Err()
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(releaseID uint64) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			fetched, err := scheduler.fetchRelease(ctx, releaseID, today)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err != nil:
+				scheduler.logger.Error("STAT_SCHEDULER fetchRelease", "release_id", releaseID, slog.Any("error", err))
+				report.Failed[releaseID] = err
+			case fetched:
+				report.Fetched++
+			default:
+				report.Skipped++
+			}
+		}(publication.ReleaseID)
+	}
+	wg.Wait()
+
+	return report, nil
+}
+
+func (scheduler *Scheduler) fetchRelease(ctx context.Context, releaseID uint64, today time.Time) (fetched bool, err error) {
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic while fetching release %d: %v", releaseID, r)
+		}
+	}()
+
+	watermark, err := scheduler.watermarks.Get(ctx, releaseID)
+	if errors.Is(err, repoErrors.ErrorNotExists) {
+		watermark, err = &models.StatisticsWatermark{ReleaseID: releaseID}, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("can't get watermark with err %w", err)
+	}
+
+	if scheduler.cfg.Platforms != nil && watermark.FetchedUntilDate(today, scheduler.cfg.Platforms()) {
+		return false, nil
+	}
+
+	release, err := scheduler.releaseRepo.Get(ctx, releaseID)
+	if err != nil {
+		return false, fmt.Errorf("can't get release with err %w", err)
+	}
+	if release.Status != models.PublishedRelease {
+		return false, nil
+	}
+
+	if err := scheduler.sleepJitter(ctx); err != nil {
+		return false, err
+	}
+
+	stats, err := scheduler.statService.FetchNewByRelease(release, watermark)
+	if errors.Is(err, statService.ErrNoStatsToFetch) {
+		// platforms have nothing for the release yet, it is not a failure
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if len(stats) == 0 {
+		return false, nil
+	}
+
+	watermark.Advance(stats)
+
+	err = scheduler.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := scheduler.statRepo.CreateMany(ctx, stats); err != nil {
+			return fmt.Errorf("can't store stats with err %w", err)
+		}
+		if err := scheduler.watermarks.Upsert(ctx, watermark); err != nil {
+			return fmt.Errorf("can't store watermark with err %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (scheduler *Scheduler) sleepJitter(ctx context.Context) error {
+	if scheduler.cfg.Jitter <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(rand.N(scheduler.cfg.Jitter))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
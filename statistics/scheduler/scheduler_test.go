@@ -0,0 +1,127 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	repoErrors "github.com/rauzh/cd-core/errors/repo"
+	"github.com/rauzh/cd-core/genre"
+	"github.com/rauzh/cd-core/models"
+	rlsService "github.com/rauzh/cd-core/release/service"
+	"github.com/rauzh/cd-core/repo/mocks"
+	statFetcher "github.com/rauzh/cd-core/statistics/fetcher/mocks"
+	statService "github.com/rauzh/cd-core/statistics/service"
+	cdtime "github.com/rauzh/cd-core/time"
+	trackService "github.com/rauzh/cd-core/track/service"
+	transacMock "github.com/rauzh/cd-core/transactor/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestScheduler_RunOnce(t *testing.T) {
+
+	today := cdtime.GetToday()
+	yesterday := today.AddDate(0, 0, -1)
+
+	pbcRepo := mocks.NewPublicationRepo(t)
+	releaseRepo := mocks.NewReleaseRepo(t)
+	statRepo := mocks.NewStatisticsRepo(t)
+	watermarks := mocks.NewStatisticsWatermarkRepo(t)
+	fetcher := statFetcher.NewStatFetcher(t)
+	transactionMock := transacMock.NewTransactor(t)
+
+	taxonomy, _ := genre.DefaultTaxonomy()
+	trkSvc := trackService.NewTrackService(mocks.NewTrackRepo(t), taxonomy, slog.Default())
+	rlsSvc := rlsService.NewReleaseService(trkSvc, transacMock.NewTransactor(t), releaseRepo, taxonomy, slog.Default())
+	statSvc := statService.NewStatisticsService(trkSvc, fetcher, statRepo, rlsSvc, taxonomy, slog.Default())
+
+	pbcRepo.EXPECT().GetAllUntilDate(mock.Anything, today).Return([]models.Publication{
+		{ReleaseID: 1}, {ReleaseID: 2}, {ReleaseID: 3}, {ReleaseID: 1}, {ReleaseID: 4}, {ReleaseID: 5},
+	}, nil)
+
+	transactionMock.EXPECT().WithinTransaction(mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}).Twice()
+
+	// release 1 is fetched until yesterday on spotify, apple reports with lag of a day,
+	// so its yesterday row is new even though spotify already has it
+	release1 := &models.Release{ReleaseID: 1, Status: models.PublishedRelease}
+	watermarks.EXPECT().Get(mock.Anything, uint64(1)).Return(&models.StatisticsWatermark{ReleaseID: 1,
+		FetchedUntil: map[string]time.Time{"spotify": yesterday, "apple": yesterday.AddDate(0, 0, -1)}}, nil)
+	releaseRepo.EXPECT().Get(mock.Anything, uint64(1)).Return(release1, nil)
+	releaseRepo.EXPECT().GetAllTracks(mock.Anything, release1).Return([]models.Track{{TrackID: 10}}, nil)
+	fetcher.EXPECT().Fetch([]models.Track{{TrackID: 10}}).Return([]models.Statistics{
+		{TrackID: 10, Platform: "spotify", Date: yesterday, Streams: 5},
+		{TrackID: 10, Platform: "spotify", Streams: 7},
+		{TrackID: 10, Platform: "apple", Date: yesterday, Streams: 3},
+	}, nil)
+	statRepo.EXPECT().CreateMany(mock.Anything, []models.Statistics{
+		{TrackID: 10, Platform: "spotify", Date: today, Streams: 7},
+		{TrackID: 10, Platform: "apple", Date: yesterday, Streams: 3},
+	}).Return(nil)
+	watermarks.EXPECT().Upsert(mock.Anything, &models.StatisticsWatermark{ReleaseID: 1,
+		FetchedUntil: map[string]time.Time{"spotify": today, "apple": yesterday}}).Return(nil)
+
+	// release 2 is already fetched today on every platform
+	watermarks.EXPECT().Get(mock.Anything, uint64(2)).Return(&models.StatisticsWatermark{ReleaseID: 2,
+		FetchedUntil: map[string]time.Time{"spotify": today, "apple": today, "deezer": today}}, nil)
+
+	// release 3 was never fetched and fails
+	errTracks := errors.New("db is down")
+	release3 := &models.Release{ReleaseID: 3, Status: models.PublishedRelease}
+	watermarks.EXPECT().Get(mock.Anything, uint64(3)).Return(nil, repoErrors.ErrorNotExists)
+	releaseRepo.EXPECT().Get(mock.Anything, uint64(3)).Return(release3, nil)
+	releaseRepo.EXPECT().GetAllTracks(mock.Anything, release3).Return(nil, errTracks)
+
+	// release 4 has no stats on platforms yet
+	release4 := &models.Release{ReleaseID: 4, Status: models.PublishedRelease}
+	watermarks.EXPECT().Get(mock.Anything, uint64(4)).Return(nil, repoErrors.ErrorNotExists)
+	releaseRepo.EXPECT().Get(mock.Anything, uint64(4)).Return(release4, nil)
+	releaseRepo.EXPECT().GetAllTracks(mock.Anything, release4).Return([]models.Track{{TrackID: 40}}, nil)
+	fetcher.EXPECT().Fetch([]models.Track{{TrackID: 40}}).Return(nil, nil)
+
+	// release 5 is fetched today on every platform but deezer registered after that
+	release5 := &models.Release{ReleaseID: 5, Status: models.PublishedRelease}
+	watermarks.EXPECT().Get(mock.Anything, uint64(5)).Return(&models.StatisticsWatermark{ReleaseID: 5,
+		FetchedUntil: map[string]time.Time{"spotify": today, "apple": today}}, nil)
+	releaseRepo.EXPECT().Get(mock.Anything, uint64(5)).Return(release5, nil)
+	releaseRepo.EXPECT().GetAllTracks(mock.Anything, release5).Return([]models.Track{{TrackID: 50}}, nil)
+	fetcher.EXPECT().Fetch([]models.Track{{TrackID: 50}}).Return([]models.Statistics{
+		{TrackID: 50, Platform: "spotify", Date: today, Streams: 5},
+		{TrackID: 50, Platform: "deezer", Date: today, Streams: 2},
+	}, nil)
+	statRepo.EXPECT().CreateMany(mock.Anything, []models.Statistics{
+		{TrackID: 50, Platform: "deezer", Date: today, Streams: 2},
+	}).Return(nil)
+	watermarks.EXPECT().Upsert(mock.Anything, &models.StatisticsWatermark{ReleaseID: 5,
+		FetchedUntil: map[string]time.Time{"spotify": today, "apple": today, "deezer": today}}).Return(nil)
+
+	platforms := func() []string { return []string{"apple", "deezer", "spotify"} }
+	scheduler := NewScheduler(statSvc, statRepo, pbcRepo, releaseRepo, watermarks, transactionMock,
+		Config{Concurrency: 2, Platforms: platforms}, slog.Default())
+
+	report, err := scheduler.RunOnce(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, report.Fetched)
+	assert.Equal(t, 2, report.Skipped)
+	assert.Len(t, report.Failed, 1)
+	assert.ErrorIs(t, report.Failed[3], errTracks)
+}
+
+func TestScheduler_Run(t *testing.T) {
+
+	pbcRepo := mocks.NewPublicationRepo(t)
+	pbcRepo.EXPECT().GetAllUntilDate(mock.Anything, cdtime.GetToday()).Return(nil, nil).Once()
+
+	scheduler := NewScheduler(nil, nil, pbcRepo, nil, nil, nil, Config{}, slog.Default())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.ErrorIs(t, scheduler.Run(ctx), context.Canceled)
+}
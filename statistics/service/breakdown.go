@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/rauzh/cd-core/models"
+	"github.com/rauzh/cd-core/statistics/convert"
+	cdtime "github.com/rauzh/cd-core/time"
+)
+
+type Dimension string
+
+const (
+	ByCountry  Dimension = "country"
+	ByPlatform Dimension = "platform"
+)
+
+// UnknownKey groups stats without the dimension, like worldwide numbers of a platform
+const UnknownKey = "unknown"
+
+var ErrUnknownDimension = errors.New("unknown statistics dimension")
+
+type BreakdownQuery struct {
+	// From and To are inclusive dates, To is today if zero
+	From      time.Time
+	To        time.Time
+	Dimension Dimension
+}
+
+type BreakdownRow struct {
+	Key     string
+	Streams uint64
+	Likes   uint64
+	// PreviousStreams are gained in the period of the same length right before From
+	PreviousStreams uint64
+	// Growth is relative change of Streams against PreviousStreams, zero for new keys
+	Growth float64
+	New    bool
+}
+
+// Breakdown splits streams gained in the range by country or platform,
+// rows are ordered by streams, the largest first
+type Breakdown struct {
+	From      time.Time
+	To        time.Time
+	Dimension Dimension
+	Rows      []BreakdownRow
+
+	TotalStreams uint64
+}
+
+func (statSvc *StatisticsService) TrackBreakdown(trackID uint64, query BreakdownQuery) (*Breakdown, error) {
+	return statSvc.breakdownForTracks([]uint64{trackID}, query)
+}
+
+func (statSvc *StatisticsService) ReleaseBreakdown(releaseID uint64, query BreakdownQuery) (*Breakdown, error) {
+
+	release, err := statSvc.releaseService.Get(releaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return statSvc.breakdownForTracks(release.Tracks, query)
+}
+
+// ArtistBreakdown answers where the artist is growing, all the artist releases are summed up
+func (statSvc *StatisticsService) ArtistBreakdown(artistID uint64, query BreakdownQuery) (*Breakdown, error) {
+
+	releases, err := statSvc.releaseService.GetAllByArtist(artistID)
+	if err != nil {
+		return nil, err
+	}
+
+	trackIDs := make([]uint64, 0)
+	for _, release := range releases {
+		trackIDs = append(trackIDs, release.Tracks...)
+	}
+
+	return statSvc.breakdownForTracks(trackIDs, query)
+}
+
+func (statSvc *StatisticsService) breakdownForTracks(trackIDs []uint64, query BreakdownQuery) (*Breakdown, error) {
+
+	query, err := query.normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	days := int(query.To.Sub(query.From)/(24*time.Hour)) + 1
+	previousFrom := query.From.AddDate(0, 0, -days)
+
+	deltas, err := convert.DeltasBetween(context.Background(), statSvc.repo, trackIDs, previousFrom, query.To)
+	if err != nil {
+		statSvc.logger.Error("STAT_SERVICE breakdown", "from", previousFrom, "to", query.To, slog.Any("error", err))
+		return nil, fmt.Errorf("can't get stats with err %w", err)
+	}
+
+	rows := make(map[string]*BreakdownRow)
+	breakdown := &Breakdown{From: query.From, To: query.To, Dimension: query.Dimension}
+
+	for _, delta := range deltas {
+		key := dimensionKey(delta, query.Dimension)
+		row, ok := rows[key]
+		if !ok {
+			row = &BreakdownRow{Key: key}
+			rows[key] = row
+		}
+
+		if cdtime.Day(delta.Date).Before(query.From) {
+			row.PreviousStreams += delta.Streams
+			continue
+		}
+		row.Streams += delta.Streams
+		row.Likes += delta.Likes
+		breakdown.TotalStreams += delta.Streams
+	}
+
+	for _, row := range rows {
+		if row.PreviousStreams == 0 {
+			row.New = row.Streams > 0
+		} else {
+			row.Growth = (float64(row.Streams) - float64(row.PreviousStreams)) / float64(row.PreviousStreams)
+		}
+		breakdown.Rows = append(breakdown.Rows, *row)
+	}
+
+	sort.Slice(breakdown.Rows, func(i, j int) bool {
+		if breakdown.Rows[i].Streams != breakdown.Rows[j].Streams {
+			return breakdown.Rows[i].Streams > breakdown.Rows[j].Streams
+		}
+		return breakdown.Rows[i].Key < breakdown.Rows[j].Key
+	})
+
+	statSvc.logger.Debug("STAT_SERVICE breakdown", "tracks_len", len(trackIDs), "dimension", query.Dimension,
+		"rows_len", len(breakdown.Rows))
+
+	return breakdown, nil
+}
+
+func dimensionKey(stat models.Statistics, dimension Dimension) string {
+	key := stat.Platform
+	if dimension == ByCountry {
+		key = stat.Country
+	}
+	if key == "" {
+		return UnknownKey
+	}
+	return key
+}
+
+func (query BreakdownQuery) normalize() (BreakdownQuery, error) {
+
+	switch query.Dimension {
+	case ByCountry, ByPlatform:
+	default:
+		return query, fmt.Errorf("%w: %s", ErrUnknownDimension, query.Dimension)
+	}
+
+	if query.To.IsZero() {
+		query.To = cdtime.GetToday()
+	}
+	query.From, query.To = cdtime.Day(query.From), cdtime.Day(query.To)
+
+	if query.From.IsZero() || query.From.After(query.To) {
+		return query, fmt.Errorf("%w: from %s to %s", ErrBadRange,
+			query.From.Format(time.DateOnly), query.To.Format(time.DateOnly))
+	}
+
+	return query, nil
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/rauzh/cd-core/models"
+	"github.com/rauzh/cd-core/statistics/convert"
+	"github.com/rauzh/cd-core/statistics/forecast"
+	cdtime "github.com/rauzh/cd-core/time"
+)
+
+const (
+	// QuarterHorizon is the number of weeks in a quarter
+	QuarterHorizon = 13
+	// forecastHistoryWeeks is how many complete weeks of history are fitted
+	forecastHistoryWeeks = 52
+)
+
+var ErrBadHorizon = errors.New("bad forecast horizon")
+
+// Forecast has weekly buckets of expected streams, likes are not forecasted
+type Forecast struct {
+	// Method is empty for roll-ups, tracks may be fitted with different methods
+	Method forecast.Method
+	// From is the start of the first forecasted week, the current week is not complete yet
+	From    time.Time
+	Buckets []Bucket
+
+	TotalStreams uint64
+
+	// Tracks of a roll-up, tracks without enough history are left out
+	Tracks map[uint64]*Forecast
+}
+
+// Forecast predicts weekly streams of the track for the next horizon weeks
+func (statSvc *StatisticsService) Forecast(trackID uint64, horizon int) (*Forecast, error) {
+
+	forecasts, err := statSvc.forecastTracks([]uint64{trackID}, horizon)
+	if err != nil {
+		return nil, err
+	}
+
+	trackForecast, ok := forecasts[trackID]
+	if !ok {
+		return nil, fmt.Errorf("%w for track %d", forecast.ErrNotEnoughHistory, trackID)
+	}
+
+	return trackForecast, nil
+}
+
+// ReleaseForecast sums up forecasts of the release tracks
+func (statSvc *StatisticsService) ReleaseForecast(releaseID uint64, horizon int) (*Forecast, error) {
+
+	release, err := statSvc.releaseService.Get(releaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	forecasts, err := statSvc.forecastTracks(release.Tracks, horizon)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(forecasts) == 0 {
+		return nil, fmt.Errorf("%w for release %d", forecast.ErrNotEnoughHistory, releaseID)
+	}
+
+	rollUp := &Forecast{
+		From:    forecastStart(),
+		Buckets: forecastBuckets(horizon),
+		Tracks:  forecasts,
+	}
+	for _, trackForecast := range forecasts {
+		for i, bucket := range trackForecast.Buckets {
+			rollUp.Buckets[i].Streams += bucket.Streams
+		}
+		rollUp.TotalStreams += trackForecast.TotalStreams
+	}
+
+	return rollUp, nil
+}
+
+// forecastTracks fits every track separately, tracks without enough history are skipped
+func (statSvc *StatisticsService) forecastTracks(trackIDs []uint64, horizon int) (map[uint64]*Forecast, error) {
+
+	if horizon <= 0 {
+		return nil, fmt.Errorf("%w: %d", ErrBadHorizon, horizon)
+	}
+
+	start := forecastStart()
+	from, to := start.AddDate(0, 0, -7*forecastHistoryWeeks), start.AddDate(0, 0, -1)
+
+	deltas, err := convert.DeltasBetween(context.Background(), statSvc.repo, trackIDs, from, to)
+	if err != nil {
+		statSvc.logger.Error("STAT_SERVICE forecast", "from", from, "to", to, slog.Any("error", err))
+		return nil, fmt.Errorf("can't get stats with err %w", err)
+	}
+
+	forecasts := make(map[uint64]*Forecast)
+	for trackID, history := range weeklyHistory(deltas, from) {
+
+		model, err := forecast.Fit(history)
+		if errors.Is(err, forecast.ErrNotEnoughHistory) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		trackForecast := &Forecast{
+			Method:  model.Method,
+			From:    start,
+			Buckets: forecastBuckets(horizon),
+		}
+		for i, streams := range model.Predict(horizon) {
+			trackForecast.Buckets[i].Streams = uint64(math.Round(streams))
+			trackForecast.TotalStreams += trackForecast.Buckets[i].Streams
+		}
+		forecasts[trackID] = trackForecast
+	}
+
+	statSvc.logger.Debug("STAT_SERVICE forecast", "tracks_len", len(trackIDs), "forecasts_len", len(forecasts))
+
+	return forecasts, nil
+}
+
+// weeklyHistory sums up deltas by track and week. Weeks before the first stats
+// of a track are cut, the track was not released or not tracked yet
+func weeklyHistory(deltas []models.Statistics, from time.Time) map[uint64][]float64 {
+
+	history := make(map[uint64][]float64)
+	for _, delta := range deltas {
+		week := int(bucketStart(delta.Date, Weekly).Sub(from) / cdtime.Week)
+		if history[delta.TrackID] == nil {
+			history[delta.TrackID] = make([]float64, forecastHistoryWeeks)
+		}
+		history[delta.TrackID][week] += float64(delta.Streams)
+	}
+
+	for trackID, weeks := range history {
+		first := 0
+		for first < len(weeks)-1 && weeks[first] == 0 {
+			first++
+		}
+		history[trackID] = weeks[first:]
+	}
+
+	return history
+}
+
+func forecastStart() time.Time {
+	return bucketStart(cdtime.GetToday(), Weekly)
+}
+
+func forecastBuckets(horizon int) []Bucket {
+	buckets := make([]Bucket, horizon)
+	for i, start := 0, forecastStart(); i < horizon; i, start = i+1, nextBucket(start, Weekly) {
+		buckets[i].Start = start
+	}
+	return buckets
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/rauzh/cd-core/genre"
+	"github.com/rauzh/cd-core/models"
+	rlsService "github.com/rauzh/cd-core/release/service"
+	"github.com/rauzh/cd-core/repo/mocks"
+	statFetcher "github.com/rauzh/cd-core/statistics/fetcher/mocks"
+	"github.com/rauzh/cd-core/statistics/forecast"
+	trackService "github.com/rauzh/cd-core/track/service"
+	transacMock "github.com/rauzh/cd-core/transactor/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStatisticsService_ReleaseForecast(t *testing.T) {
+
+	rlsMockRepo := mocks.NewReleaseRepo(t)
+	statMockRepo := mocks.NewStatisticsRepo(t)
+
+	taxonomy, _ := genre.DefaultTaxonomy()
+	trkSvc := trackService.NewTrackService(mocks.NewTrackRepo(t), taxonomy, slog.Default())
+	rlsSvc := rlsService.NewReleaseService(trkSvc, transacMock.NewTransactor(t), rlsMockRepo, taxonomy, slog.Default())
+	statSvc := NewStatisticsService(trkSvc, statFetcher.NewStatFetcher(t), statMockRepo, rlsSvc,
+		taxonomy, slog.Default())
+
+	start := forecastStart()
+	from, to := start.AddDate(0, 0, -7*forecastHistoryWeeks), start.AddDate(0, 0, -1)
+	tracks := []uint64{1, 2}
+
+	// track 1 gains 1000 streams a week for 10 weeks, track 2 was released last week
+	stats := make([]models.Statistics, 0)
+	for week := 10; week > 0; week-- {
+		stats = append(stats, models.Statistics{
+			TrackID: 1, Date: start.AddDate(0, 0, -7*week+2), Streams: uint64(1000 * (11 - week)),
+		})
+	}
+	stats = append(stats, models.Statistics{TrackID: 2, Date: to, Streams: 50, Kind: models.DeltaStatistics})
+
+	rlsMockRepo.EXPECT().Get(mock.AnythingOfType("context.backgroundCtx"), uint64(7)).Return(
+		&models.Release{ReleaseID: 7, Tracks: tracks}, nil).Once()
+	statMockRepo.EXPECT().GetLastForTracksBefore(mock.AnythingOfType("context.backgroundCtx"), tracks, from).Return(
+		nil, nil).Once()
+	statMockRepo.EXPECT().GetForTracksBetween(mock.AnythingOfType("context.backgroundCtx"), tracks, from, to).Return(
+		stats, nil).Once()
+
+	releaseForecast, err := statSvc.ReleaseForecast(7, QuarterHorizon)
+
+	assert.Nil(t, err)
+	assert.Equal(t, start, releaseForecast.From)
+	assert.Len(t, releaseForecast.Buckets, QuarterHorizon)
+	assert.Equal(t, uint64(1000), releaseForecast.Buckets[0].Streams)
+	assert.Equal(t, uint64(13000), releaseForecast.TotalStreams)
+
+	assert.Len(t, releaseForecast.Tracks, 1)
+	assert.Equal(t, forecast.HoltLinear, releaseForecast.Tracks[1].Method)
+}
+
+func TestStatisticsService_ForecastBadHorizon(t *testing.T) {
+
+	taxonomy, _ := genre.DefaultTaxonomy()
+	trkSvc := trackService.NewTrackService(mocks.NewTrackRepo(t), taxonomy, slog.Default())
+	statSvc := NewStatisticsService(trkSvc, statFetcher.NewStatFetcher(t), mocks.NewStatisticsRepo(t), nil,
+		taxonomy, slog.Default())
+
+	_, err := statSvc.Forecast(1, 0)
+	assert.ErrorIs(t, err, ErrBadHorizon)
+}
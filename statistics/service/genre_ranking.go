@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/rauzh/cd-core/genre"
+	"github.com/rauzh/cd-core/models"
+	"github.com/rauzh/cd-core/statistics/convert"
+	cdtime "github.com/rauzh/cd-core/time"
+)
+
+type GenreRankingOptions struct {
+	// Since is the start of the window, zero means cdtime.RelevantPeriod()
+	Since time.Time
+	// Limit is the N of top-N genres, zero means all genres
+	Limit int
+
+	StreamsWeight float64
+	LikesWeight   float64
+
+	// HalfLife is the age at which stats count twice less, zero means no decay
+	HalfLife time.Duration
+}
+
+type GenreScore struct {
+	Genre   string
+	Score   float64
+	Streams uint64
+	Likes   uint64
+}
+
+func DefaultGenreRankingOptions() GenreRankingOptions {
+	return GenreRankingOptions{
+		Since:         cdtime.RelevantPeriod(),
+		Limit:         1,
+		StreamsWeight: 1,
+	}
+}
+
+func (statSvc *StatisticsService) RankGenres(opts GenreRankingOptions) ([]GenreScore, error) {
+
+	if opts.Since.IsZero() {
+		opts.Since = cdtime.RelevantPeriod()
+	}
+
+	ctx := context.Background()
+
+	stats, err := statSvc.repo.GetAllGroupByTracksSince(ctx, opts.Since)
+	if err != nil {
+		statSvc.logger.Error("STAT_SERVICE RankGenres", "since", opts.Since, slog.Any("error", err))
+		return nil, fmt.Errorf("can't get stats with err %w", err)
+	}
+
+	trackIDs := make([]uint64, 0, len(*stats))
+	for trackID := range *stats {
+		trackIDs = append(trackIDs, trackID)
+	}
+	sort.Slice(trackIDs, func(i, j int) bool { return trackIDs[i] < trackIDs[j] })
+
+	today := cdtime.GetToday()
+
+	// the last totals before the window are the baseline of cumulative series,
+	// worldwide rows overlapping per country ones are not counted
+	windowDeltas, err := convert.DeltasBetween(ctx, statSvc.repo, trackIDs, opts.Since, today)
+	if err != nil {
+		statSvc.logger.Error("STAT_SERVICE RankGenres", "since", opts.Since, slog.Any("error", err))
+		return nil, fmt.Errorf("can't get stats with err %w", err)
+	}
+
+	trackDeltas := make(map[uint64][]models.Statistics, len(trackIDs))
+	for _, delta := range windowDeltas {
+		trackDeltas[delta.TrackID] = append(trackDeltas[delta.TrackID], delta)
+	}
+
+	// streams are rolled up to top-level genres, so "trap" counts for "hip-hop"
+	scores := make(map[genre.GenreID]*GenreScore)
+	for _, trackID := range trackIDs {
+
+		deltas := trackDeltas[trackID]
+		if len(deltas) == 0 {
+			continue
+		}
+
+		track, err := statSvc.trackService.Get(trackID)
+		if err != nil {
+			return nil, fmt.Errorf("can't get track %d with err %w", trackID, err)
+		}
+
+		rootGenre := statSvc.taxonomy.Root(statSvc.taxonomy.Canonical(track.Genre))
+
+		score, ok := scores[rootGenre]
+		if !ok {
+			score = &GenreScore{Genre: string(rootGenre)}
+			scores[rootGenre] = score
+		}
+
+		for _, stat := range deltas {
+			decay := decayFactor(today.Sub(stat.Date), opts.HalfLife)
+
+			score.Streams += stat.Streams
+			score.Likes += stat.Likes
+			score.Score += decay * (opts.StreamsWeight*float64(stat.Streams) + opts.LikesWeight*float64(stat.Likes))
+		}
+	}
+
+	ranking := make([]GenreScore, 0, len(scores))
+	for _, score := range scores {
+		ranking = append(ranking, *score)
+	}
+
+	sort.Slice(ranking, func(i, j int) bool {
+		if ranking[i].Score != ranking[j].Score {
+			return ranking[i].Score > ranking[j].Score
+		}
+		if ranking[i].Streams != ranking[j].Streams {
+			return ranking[i].Streams > ranking[j].Streams
+		}
+		return ranking[i].Genre < ranking[j].Genre
+	})
+
+	if opts.Limit > 0 && len(ranking) > opts.Limit {
+		ranking = ranking[:opts.Limit]
+	}
+
+	statSvc.logger.Debug("STAT_SERVICE RankGenres", "genres_len", len(ranking))
+
+	return ranking, nil
+}
+
+func decayFactor(age time.Duration, halfLife time.Duration) float64 {
+	if halfLife <= 0 || age <= 0 {
+		return 1
+	}
+	return math.Pow(0.5, float64(age)/float64(halfLife))
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/rauzh/cd-core/genre"
+	"github.com/rauzh/cd-core/models"
+	rlsService "github.com/rauzh/cd-core/release/service"
+	"github.com/rauzh/cd-core/repo/mocks"
+	statFetcher "github.com/rauzh/cd-core/statistics/fetcher/mocks"
+	cdtime "github.com/rauzh/cd-core/time"
+	trackService "github.com/rauzh/cd-core/track/service"
+	transacMock "github.com/rauzh/cd-core/transactor/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStatisticsService_RankGenres(t *testing.T) {
+
+	trkMockRepo := mocks.NewTrackRepo(t)
+	statMockRepo := mocks.NewStatisticsRepo(t)
+
+	taxonomy, _ := genre.DefaultTaxonomy()
+	trkSvc := trackService.NewTrackService(trkMockRepo, taxonomy, slog.Default())
+	rlsSvc := rlsService.NewReleaseService(trkSvc, transacMock.NewTransactor(t), mocks.NewReleaseRepo(t), taxonomy, slog.Default())
+	statSvc := NewStatisticsService(trkSvc, statFetcher.NewStatFetcher(t), statMockRepo, rlsSvc,
+		taxonomy, slog.Default())
+
+	today := cdtime.GetToday()
+	since := cdtime.RelevantPeriod()
+	stats := map[uint64][]models.Statistics{
+		1: {{TrackID: 1, Date: today, Streams: 60, Likes: 1}},
+		2: {{TrackID: 2, Date: today, Streams: 50, Likes: 10, Kind: models.DeltaStatistics}},
+		// worldwide row overlaps per country ones and is not counted
+		3: {
+			{TrackID: 3, Date: today, Streams: 100, Likes: 1, Kind: models.DeltaStatistics},
+			{TrackID: 3, Date: today, Country: "US", Streams: 40, Likes: 1, Kind: models.DeltaStatistics},
+			{TrackID: 3, Date: today, Country: "DE", Streams: 30, Kind: models.DeltaStatistics},
+		},
+		4: {{TrackID: 4, Date: today.AddDate(0, -1, 0), Streams: 1120, Likes: 1}},
+	}
+	var window []models.Statistics
+	for _, trackID := range []uint64{1, 2, 3, 4} {
+		window = append(window, stats[trackID]...)
+	}
+
+	// only the window is loaded, totals before it are the baseline
+	statMockRepo.EXPECT().GetAllGroupByTracksSince(mock.AnythingOfType("context.backgroundCtx"), since).
+		Return(&stats, nil)
+	statMockRepo.EXPECT().GetForTracksBetween(mock.AnythingOfType("context.backgroundCtx"), []uint64{1, 2, 3, 4}, since, today).
+		Return(window, nil)
+	statMockRepo.EXPECT().GetLastForTracksBefore(mock.AnythingOfType("context.backgroundCtx"), []uint64{1, 2, 3, 4}, since).
+		Return([]models.Statistics{
+			{TrackID: 1, Date: since.AddDate(0, 0, -1), Streams: 10},
+			{TrackID: 4, Date: since.AddDate(0, 0, -1), Streams: 1000},
+		}, nil)
+
+	trkMockRepo.EXPECT().Get(mock.AnythingOfType("context.backgroundCtx"), uint64(1)).Return(&models.Track{Genre: "trap"}, nil)
+	trkMockRepo.EXPECT().Get(mock.AnythingOfType("context.backgroundCtx"), uint64(2)).Return(&models.Track{Genre: "Rap"}, nil)
+	trkMockRepo.EXPECT().Get(mock.AnythingOfType("context.backgroundCtx"), uint64(3)).Return(&models.Track{Genre: "rock"}, nil)
+	trkMockRepo.EXPECT().Get(mock.AnythingOfType("context.backgroundCtx"), uint64(4)).Return(&models.Track{Genre: "jazz"}, nil)
+
+	t.Run("StreamsOnly", func(t *testing.T) {
+		ranking, err := statSvc.(*StatisticsService).RankGenres(GenreRankingOptions{StreamsWeight: 1})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"jazz", "hip-hop", "rock"}, genresOf(ranking))
+		assert.Equal(t, uint64(11), ranking[1].Likes)
+		assert.Equal(t, uint64(70), ranking[2].Streams)
+	})
+
+	t.Run("TieBreak", func(t *testing.T) {
+		ranking, err := statSvc.(*StatisticsService).RankGenres(GenreRankingOptions{LikesWeight: 1, Limit: 2})
+		assert.Nil(t, err)
+		// rock and jazz have the same score, jazz has more streams
+		assert.Equal(t, []string{"hip-hop", "jazz"}, genresOf(ranking))
+	})
+
+	t.Run("Decay", func(t *testing.T) {
+		ranking, err := statSvc.(*StatisticsService).RankGenres(GenreRankingOptions{
+			StreamsWeight: 1, HalfLife: cdtime.Week})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"hip-hop", "rock", "jazz"}, genresOf(ranking))
+	})
+
+	t.Run("RelevantGenre", func(t *testing.T) {
+		relevantGenre, err := statSvc.GetRelevantGenre()
+		assert.Nil(t, err)
+		assert.Equal(t, "jazz", relevantGenre)
+	})
+}
+
+func genresOf(ranking []GenreScore) []string {
+	genres := make([]string, 0, len(ranking))
+	for _, score := range ranking {
+		genres = append(genres, score.Genre)
+	}
+	return genres
+}
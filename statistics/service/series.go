@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/rauzh/cd-core/statistics/convert"
+	cdtime "github.com/rauzh/cd-core/time"
+)
+
+type Granularity string
+
+const (
+	Daily   Granularity = "daily"
+	Weekly  Granularity = "weekly"
+	Monthly Granularity = "monthly"
+)
+
+var (
+	ErrBadRange           = errors.New("bad date range")
+	ErrUnknownGranularity = errors.New("unknown granularity")
+)
+
+type SeriesQuery struct {
+	// From and To are inclusive dates, To is today if zero
+	From time.Time
+	To   time.Time
+	// Granularity is Daily if empty. Weeks start on monday, months on the first day
+	Granularity Granularity
+}
+
+// Bucket holds streams and likes gained from Start till the next bucket
+type Bucket struct {
+	Start   time.Time
+	Streams uint64
+	Likes   uint64
+}
+
+// Series has a bucket for every period of the range, periods without stats are zero.
+// The first bucket may start before From, but counts stats from From only
+type Series struct {
+	From        time.Time
+	To          time.Time
+	Granularity Granularity
+	Buckets     []Bucket
+
+	TotalStreams uint64
+	TotalLikes   uint64
+}
+
+func (statSvc *StatisticsService) TrackSeries(trackID uint64, query SeriesQuery) (*Series, error) {
+	return statSvc.seriesForTracks([]uint64{trackID}, query)
+}
+
+func (statSvc *StatisticsService) ReleaseSeries(releaseID uint64, query SeriesQuery) (*Series, error) {
+
+	release, err := statSvc.releaseService.Get(releaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return statSvc.seriesForTracks(release.Tracks, query)
+}
+
+// ArtistSeries sums up all the tracks of all the artist releases
+func (statSvc *StatisticsService) ArtistSeries(artistID uint64, query SeriesQuery) (*Series, error) {
+
+	releases, err := statSvc.releaseService.GetAllByArtist(artistID)
+	if err != nil {
+		return nil, err
+	}
+
+	trackIDs := make([]uint64, 0)
+	for _, release := range releases {
+		trackIDs = append(trackIDs, release.Tracks...)
+	}
+
+	return statSvc.seriesForTracks(trackIDs, query)
+}
+
+func (statSvc *StatisticsService) seriesForTracks(trackIDs []uint64, query SeriesQuery) (*Series, error) {
+
+	query, err := query.normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	deltas, err := convert.DeltasBetween(context.Background(), statSvc.repo, trackIDs, query.From, query.To)
+	if err != nil {
+		statSvc.logger.Error("STAT_SERVICE series", "from", query.From, "to", query.To, slog.Any("error", err))
+		return nil, fmt.Errorf("can't get stats with err %w", err)
+	}
+
+	series := &Series{
+		From:        query.From,
+		To:          query.To,
+		Granularity: query.Granularity,
+	}
+
+	// every bucket is created up front, so gaps stay in the series as zeros
+	index := make(map[time.Time]int)
+	for start := bucketStart(query.From, query.Granularity); !start.After(query.To); start = nextBucket(start, query.Granularity) {
+		index[start] = len(series.Buckets)
+		series.Buckets = append(series.Buckets, Bucket{Start: start})
+	}
+
+	for _, delta := range deltas {
+		bucket := &series.Buckets[index[bucketStart(delta.Date, query.Granularity)]]
+		bucket.Streams += delta.Streams
+		bucket.Likes += delta.Likes
+
+		series.TotalStreams += delta.Streams
+		series.TotalLikes += delta.Likes
+	}
+
+	statSvc.logger.Debug("STAT_SERVICE series", "tracks_len", len(trackIDs), "buckets_len", len(series.Buckets))
+
+	return series, nil
+}
+
+func (query SeriesQuery) normalize() (SeriesQuery, error) {
+
+	if query.Granularity == "" {
+		query.Granularity = Daily
+	}
+	switch query.Granularity {
+	case Daily, Weekly, Monthly:
+	default:
+		return query, fmt.Errorf("%w: %s", ErrUnknownGranularity, query.Granularity)
+	}
+
+	if query.To.IsZero() {
+		query.To = cdtime.GetToday()
+	}
+	query.From, query.To = cdtime.Day(query.From), cdtime.Day(query.To)
+
+	if query.From.IsZero() || query.From.After(query.To) {
+		return query, fmt.Errorf("%w: from %s to %s", ErrBadRange,
+			query.From.Format(time.DateOnly), query.To.Format(time.DateOnly))
+	}
+
+	return query, nil
+}
+
+func bucketStart(date time.Time, granularity Granularity) time.Time {
+	date = cdtime.Day(date)
+	switch granularity {
+	case Weekly:
+		return cdtime.WeekStart(date)
+	case Monthly:
+		return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return date
+	}
+}
+
+func nextBucket(start time.Time, granularity Granularity) time.Time {
+	switch granularity {
+	case Weekly:
+		return start.AddDate(0, 0, 7)
+	case Monthly:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
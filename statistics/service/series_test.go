@@ -0,0 +1,76 @@
+package service
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/rauzh/cd-core/genre"
+	"github.com/rauzh/cd-core/models"
+	rlsService "github.com/rauzh/cd-core/release/service"
+	"github.com/rauzh/cd-core/repo/mocks"
+	statFetcher "github.com/rauzh/cd-core/statistics/fetcher/mocks"
+	cdtime "github.com/rauzh/cd-core/time"
+	trackService "github.com/rauzh/cd-core/track/service"
+	transacMock "github.com/rauzh/cd-core/transactor/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStatisticsService_ReleaseSeries(t *testing.T) {
+
+	rlsMockRepo := mocks.NewReleaseRepo(t)
+	statMockRepo := mocks.NewStatisticsRepo(t)
+
+	taxonomy, _ := genre.DefaultTaxonomy()
+	trkSvc := trackService.NewTrackService(mocks.NewTrackRepo(t), taxonomy, slog.Default())
+	rlsSvc := rlsService.NewReleaseService(trkSvc, transacMock.NewTransactor(t), rlsMockRepo, taxonomy, slog.Default())
+	statSvc := NewStatisticsService(trkSvc, statFetcher.NewStatFetcher(t), statMockRepo, rlsSvc,
+		taxonomy, slog.Default())
+
+	// 2024-04-29 is monday
+	from, to := cdtime.Date(2024, 4, 30), cdtime.Date(2024, 5, 14)
+	tracks := []uint64{1, 2}
+
+	rlsMockRepo.EXPECT().Get(mock.AnythingOfType("context.backgroundCtx"), uint64(7)).Return(
+		&models.Release{ReleaseID: 7, Tracks: tracks}, nil)
+
+	statMockRepo.EXPECT().GetLastForTracksBefore(mock.AnythingOfType("context.backgroundCtx"), tracks, from).Return(
+		[]models.Statistics{
+			{TrackID: 1, Date: cdtime.Date(2024, 4, 20), Streams: 1000, Likes: 10},
+		}, nil)
+	statMockRepo.EXPECT().GetForTracksBetween(mock.AnythingOfType("context.backgroundCtx"), tracks, from, to).Return(
+		[]models.Statistics{
+			{TrackID: 1, Date: cdtime.Date(2024, 5, 1), Streams: 1100, Likes: 11},
+			{TrackID: 1, Date: cdtime.Date(2024, 5, 13), Streams: 1150, Likes: 11},
+			{TrackID: 2, Date: cdtime.Date(2024, 5, 2), Streams: 40, Kind: models.DeltaStatistics},
+		}, nil)
+
+	series, err := statSvc.ReleaseSeries(7, SeriesQuery{From: from, To: to, Granularity: Weekly})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []Bucket{
+		{Start: cdtime.Date(2024, 4, 29), Streams: 140, Likes: 1},
+		// the week without stats is kept
+		{Start: cdtime.Date(2024, 5, 6)},
+		{Start: cdtime.Date(2024, 5, 13), Streams: 50},
+	}, series.Buckets)
+	assert.Equal(t, uint64(190), series.TotalStreams)
+	assert.Equal(t, uint64(1), series.TotalLikes)
+}
+
+func TestSeriesQuery_Normalize(t *testing.T) {
+
+	_, err := SeriesQuery{From: cdtime.Date(2024, 5, 2), To: cdtime.Date(2024, 5, 1)}.normalize()
+	assert.ErrorIs(t, err, ErrBadRange)
+
+	_, err = SeriesQuery{From: cdtime.Date(2024, 5, 1), Granularity: "yearly"}.normalize()
+	assert.ErrorIs(t, err, ErrUnknownGranularity)
+
+	query, err := SeriesQuery{From: cdtime.Date(2024, 5, 1)}.normalize()
+	assert.Nil(t, err)
+	assert.Equal(t, Daily, query.Granularity)
+	assert.Equal(t, cdtime.GetToday(), query.To)
+
+	assert.Equal(t, cdtime.Date(2024, 5, 1), bucketStart(cdtime.Date(2024, 5, 31), Monthly))
+	assert.Equal(t, cdtime.Date(2024, 5, 13), bucketStart(cdtime.Date(2024, 5, 19), Weekly))
+}
@@ -8,21 +8,38 @@ import (
 
 	cdtime "github.com/rauzh/cd-core/time"
 
+	"github.com/rauzh/cd-core/genre"
 	"github.com/rauzh/cd-core/models"
 
 	releaseService "github.com/rauzh/cd-core/release/service"
 	"github.com/rauzh/cd-core/repo"
+	"github.com/rauzh/cd-core/statistics/convert"
 	"github.com/rauzh/cd-core/statistics/fetcher"
 	ts "github.com/rauzh/cd-core/track/service"
 )
 
+var (
+	ErrNoStatsToFetch = errors.New("no stats to fetch")
+	ErrNoStats        = errors.New("no stats")
+)
+
 type IStatisticsService interface {
 	Create(*models.Statistics) error
 	FetchByRelease(release *models.Release) error
+	FetchNewByRelease(release *models.Release, watermark *models.StatisticsWatermark) ([]models.Statistics, error)
 	GetForTrack(uint64) ([]models.Statistics, error)
 	GetByID(uint64) (*models.Statistics, error)
 	GetRelevantGenre() (string, error)
+	RankGenres(opts GenreRankingOptions) ([]GenreScore, error)
 	GetLatestStatForTrack(trackID uint64) (*models.Statistics, error)
+	TrackSeries(trackID uint64, query SeriesQuery) (*Series, error)
+	ReleaseSeries(releaseID uint64, query SeriesQuery) (*Series, error)
+	ArtistSeries(artistID uint64, query SeriesQuery) (*Series, error)
+	Forecast(trackID uint64, horizon int) (*Forecast, error)
+	ReleaseForecast(releaseID uint64, horizon int) (*Forecast, error)
+	TrackBreakdown(trackID uint64, query BreakdownQuery) (*Breakdown, error)
+	ReleaseBreakdown(releaseID uint64, query BreakdownQuery) (*Breakdown, error)
+	ArtistBreakdown(artistID uint64, query BreakdownQuery) (*Breakdown, error)
 }
 
 type StatisticsService struct {
@@ -30,6 +47,7 @@ type StatisticsService struct {
 	releaseService releaseService.IReleaseService
 	fetcher        fetcher.StatFetcher
 	repo           repo.StatisticsRepo
+	taxonomy       genre.ITaxonomy
 
 	logger *slog.Logger
 }
@@ -39,12 +57,14 @@ func NewStatisticsService(
 	f fetcher.StatFetcher,
 	r repo.StatisticsRepo,
 	rls releaseService.IReleaseService,
+	taxonomy genre.ITaxonomy,
 	logger *slog.Logger) IStatisticsService {
 	return &StatisticsService{
 		trackService:   ts,
 		releaseService: rls,
 		fetcher:        f,
 		repo:           r,
+		taxonomy:       taxonomy,
 		logger:         logger,
 	}
 }
@@ -91,69 +111,71 @@ func (statSvc *StatisticsService) GetForTrack(trackID uint64) ([]models.Statisti
 
 func (statSvc *StatisticsService) FetchByRelease(release *models.Release) error {
 
+	stats, err := statSvc.FetchNewByRelease(release, &models.StatisticsWatermark{ReleaseID: release.ReleaseID})
+	if err != nil {
+		return err
+	}
+
+	if err = statSvc.repo.CreateMany(context.Background(), stats); err != nil {
+		statSvc.logger.Error("STAT_SERVICE FetchByRelease can't create many", "release_id", release.ReleaseID, slog.Any("error", err))
+		return fmt.Errorf("can't create stats with err %w", err)
+	}
+
+	statSvc.logger.Info("STAT_SERVICE FetchByRelease", "release_id", release.ReleaseID, "stats", len(stats))
+	return nil
+}
+
+// FetchNewByRelease returns only stats dated after the watermark of their platform,
+// so already stored days are not duplicated. Storing them is left to the caller
+func (statSvc *StatisticsService) FetchNewByRelease(release *models.Release, watermark *models.StatisticsWatermark) ([]models.Statistics, error) {
+
 	tracks, err := statSvc.releaseService.GetAllTracks(release)
 	if err != nil {
-		statSvc.logger.Error("STAT_SERVICE FetchByRelease can't get tracks", "release", release.ReleaseID, slog.Any("error", err))
-		return fmt.Errorf("can't fetch stats with err %w", err)
+		statSvc.logger.Error("STAT_SERVICE FetchNewByRelease can't get tracks", "release", release.ReleaseID, slog.Any("error", err))
+		return nil, fmt.Errorf("can't fetch stats with err %w", err)
 	}
 
 	stats, err := statSvc.fetcher.Fetch(tracks)
 	if err != nil {
-		statSvc.logger.Error("STAT_SERVICE FetchByRelease can't fetch", "release", release.ReleaseID, slog.Any("error", err))
-		return fmt.Errorf("can't fetch stats with err %w", err)
+		statSvc.logger.Error("STAT_SERVICE FetchNewByRelease can't fetch", "release", release.ReleaseID, slog.Any("error", err))
+		return nil, fmt.Errorf("can't fetch stats with err %w", err)
 	}
 
-	statSvc.logger.Debug("FetchByRelease", "stats", stats)
+	statSvc.logger.Debug("FetchNewByRelease", "stats", stats)
 
 	if len(stats) < 1 {
-		statSvc.logger.Info("STAT_SERVICE FetchByRelease no stats to fetch", "release", release.ReleaseID)
-		return errors.New("no stats to fetch")
+		statSvc.logger.Info("STAT_SERVICE FetchNewByRelease no stats to fetch", "release", release.ReleaseID)
+		return nil, ErrNoStatsToFetch
 	}
 
+	newStats := make([]models.Statistics, 0, len(stats))
 	for _, stat := range stats {
-		stat.Date = cdtime.GetToday()
-	}
-
-	if err = statSvc.repo.CreateMany(context.Background(), stats); err != nil {
-		statSvc.logger.Error("STAT_SERVICE FetchByRelease can't create many", "release_id", release.ReleaseID, slog.Any("error", err))
-		return fmt.Errorf("can't create stats with err %w", err)
+		// adapters without per date stats leave date empty
+		if stat.Date.IsZero() {
+			stat.Date = cdtime.GetToday()
+		}
+		if watermark.IsNew(&stat) {
+			newStats = append(newStats, stat)
+		}
 	}
 
-	statSvc.logger.Info("STAT_SERVICE FetchByRelease", "release_id", release.ReleaseID)
-
-	return nil
+	statSvc.logger.Debug("STAT_SERVICE FetchNewByRelease", "release_id", release.ReleaseID, "new_stats", len(newStats))
+	return newStats, nil
 }
 
+// GetRelevantGenre returns the top genre by streams gained since cdtime.RelevantPeriod()
 func (statSvc *StatisticsService) GetRelevantGenre() (string, error) {
 
-	stats, err := statSvc.repo.GetAllGroupByTracksSince(context.Background(), cdtime.RelevantPeriod())
+	ranking, err := statSvc.RankGenres(DefaultGenreRankingOptions())
 	if err != nil {
-		return "", fmt.Errorf("can't get stats with err %w", err)
-	}
-
-	genres := make(map[string]uint64)
-	for trackID, statsPerTrack := range *stats {
-		track, err := statSvc.trackService.Get(trackID)
-		if err != nil {
-			return "", fmt.Errorf("can't get track %d with err %w", trackID, err)
-		}
-
-		for _, stat := range statsPerTrack {
-			genres[track.Genre] += stat.Streams
-		}
+		return "", err
 	}
 
-	var relevantGenre string
-	var maxStreamsPerGenre uint64
-
-	for genre, streams := range genres {
-		if streams > maxStreamsPerGenre {
-			maxStreamsPerGenre = streams
-			relevantGenre = genre
-		}
+	if len(ranking) == 0 {
+		return "", nil
 	}
 
-	return relevantGenre, err
+	return ranking[0].Genre, nil
 }
 
 func (statSvc *StatisticsService) GetLatestStatForTrack(trackID uint64) (*models.Statistics, error) {
@@ -170,15 +192,12 @@ func (statSvc *StatisticsService) GetLatestStatForTrack(trackID uint64) (*models
 		"get latest stat for track", trackID,
 		"stats len", len(stats))
 
-	latestStatDate := stats[0].Date
-	latestStat := stats[0]
-
-	for _, stat := range stats {
-		if stat.Date.After(latestStatDate) {
-			latestStatDate = stat.Date
-			latestStat = stat
-		}
+	// the latest total of all platforms, deltas are added up
+	totals := convert.Totals(stats)
+	if len(totals) == 0 {
+		return nil, fmt.Errorf("%w for track %d", ErrNoStats, trackID)
 	}
+	latestStat := totals[len(totals)-1]
 
 	return &latestStat, nil
 }
@@ -31,3 +31,23 @@ func CheckDateWeekLater(expectedDate time.Time) bool {
 func Date(year, month, day int) time.Time {
 	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
 }
+
+func PreviousRelevantPeriod() time.Time {
+	return RelevantPeriod().AddDate(0, -3, 0)
+}
+
+// Day truncates date to the start of its day in UTC, zero date stays zero
+func Day(date time.Time) time.Time {
+	if date.IsZero() {
+		return date
+	}
+	date = date.UTC()
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// WeekStart returns the monday of the week
+func WeekStart(date time.Time) time.Time {
+	date = Day(date)
+	// time.Sunday is 0, weeks start on monday
+	return date.AddDate(0, 0, -(int(date.Weekday())+6)%7)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/rauzh/cd-core/genre"
 	"github.com/rauzh/cd-core/models"
 
 	"github.com/rauzh/cd-core/repo"
@@ -17,19 +18,23 @@ type ITrackService interface {
 }
 
 type TrackService struct {
-	repo repo.TrackRepo
+	repo     repo.TrackRepo
+	taxonomy genre.ITaxonomy
 
 	logger *slog.Logger
 }
 
-func NewTrackService(r repo.TrackRepo, logger *slog.Logger) ITrackService {
-	return &TrackService{repo: r, logger: logger}
+func NewTrackService(r repo.TrackRepo, taxonomy genre.ITaxonomy, logger *slog.Logger) ITrackService {
+	return &TrackService{repo: r, taxonomy: taxonomy, logger: logger}
 }
 
 func (trkSvc *TrackService) validate(track *models.Track) error {
-	if track.Genre == "" {
+	// known aliases are stored by canonical id, unknown genres are kept as top-level ones
+	genreID := trkSvc.taxonomy.Canonical(track.Genre)
+	if genreID == "" {
 		return trackErrors.ErrNoGenre
 	}
+	track.Genre = string(genreID)
 
 	if len(track.Artists) < 1 {
 		return trackErrors.ErrNoArtist
@@ -70,5 +75,7 @@ func (trkSvc *TrackService) Get(trackID uint64) (*models.Track, error) {
 	if err != nil {
 		return nil, fmt.Errorf("can't get track with err %w", err)
 	}
+
+	track.Genre = string(trkSvc.taxonomy.Canonical(track.Genre))
 	return track, nil
 }
@@ -1,9 +1,11 @@
 package service
 
 import (
+	"context"
 	"log/slog"
 	"testing"
 
+	"github.com/rauzh/cd-core/genre"
 	"github.com/rauzh/cd-core/models"
 
 	mocks "github.com/rauzh/cd-core/repo/mocks"
@@ -23,9 +25,45 @@ func TestTrackService_Get(t *testing.T) {
 		Artists:  []uint64{82, 4},
 	}, nil).Once()
 
-	ts := NewTrackService(mockTrackRepo, slog.Default())
+	taxonomy, _ := genre.DefaultTaxonomy()
+	ts := NewTrackService(mockTrackRepo, taxonomy, slog.Default())
 
 	track, err := ts.Get(1234)
 	assert.Nil(t, err)
 	assert.Equal(t, "rock", track.Genre)
 }
+
+func TestTrackService_Create(t *testing.T) {
+
+	tests := []struct {
+		name  string
+		genre string
+		want  string
+	}{
+		{name: "Alias", genre: "Rap", want: "hip-hop"},
+		{name: "Unknown", genre: "Lo-Fi", want: "lo-fi"},
+		{name: "UnknownWithSpaces", genre: " ambient ", want: "ambient"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			mockTrackRepo := mocks.NewTrackRepo(t)
+			mockTrackRepo.EXPECT().Create(mock.Anything, mock.MatchedBy(func(track *models.Track) bool {
+				return track.Genre == tt.want
+			})).Return(uint64(1234), nil).Once()
+
+			taxonomy, _ := genre.DefaultTaxonomy()
+			ts := NewTrackService(mockTrackRepo, taxonomy, slog.Default())
+
+			trackID, err := ts.Create(context.Background(), &models.Track{
+				Title:   "aa",
+				Genre:   tt.genre,
+				Type:    "single",
+				Artists: []uint64{82},
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, uint64(1234), trackID)
+		})
+	}
+}
@@ -1,8 +1,61 @@
 package transactor
 
-import "context"
+import (
+	"context"
+	"sync"
+)
 
 //go:generate mockery --name Transactor --with-expecter
 type Transactor interface {
 	WithinTransaction(context.Context, func(ctx context.Context) error) error
 }
+
+type afterCommitKey struct{}
+
+type afterCommitHooks struct {
+	mu  sync.Mutex
+	fns []func()
+}
+
+// AfterCommit runs fn after the transaction of ctx is committed and drops it if the transaction fails.
+// Outside of a transaction started by WithAfterCommit transactor fn runs right away
+func AfterCommit(ctx context.Context, fn func()) {
+	hooks, ok := ctx.Value(afterCommitKey{}).(*afterCommitHooks)
+	if !ok {
+		fn()
+		return
+	}
+
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+	hooks.fns = append(hooks.fns, fn)
+}
+
+// WithAfterCommit makes t run AfterCommit hooks once the outermost transaction is committed,
+// nested transactions join the outer one and leave the hooks to it
+func WithAfterCommit(t Transactor) Transactor {
+	return &afterCommitTransactor{Transactor: t}
+}
+
+type afterCommitTransactor struct {
+	Transactor
+}
+
+func (t *afterCommitTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(afterCommitKey{}).(*afterCommitHooks); ok {
+		return t.Transactor.WithinTransaction(ctx, fn)
+	}
+
+	hooks := &afterCommitHooks{}
+	if err := t.Transactor.WithinTransaction(context.WithValue(ctx, afterCommitKey{}, hooks), fn); err != nil {
+		return err
+	}
+
+	hooks.mu.Lock()
+	fns := hooks.fns
+	hooks.mu.Unlock()
+	for _, fn := range fns {
+		fn()
+	}
+	return nil
+}
@@ -12,7 +12,7 @@ type ATtrm struct {
 }
 
 func NewATtrm(trm *manager.Manager) transactor.Transactor {
-	return &ATtrm{trm: trm}
+	return transactor.WithAfterCommit(&ATtrm{trm: trm})
 }
 
 func (transactor *ATtrm) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
@@ -11,6 +11,7 @@ import (
 	"github.com/rauzh/cd-core/models"
 
 	"github.com/rauzh/cd-core/repo"
+	cdtime "github.com/rauzh/cd-core/time"
 	userErrors "github.com/rauzh/cd-core/user/errors"
 )
 
@@ -71,6 +72,7 @@ func (usrSvc *UserService) Create(newUser *models.User) error {
 	}
 
 	newUser.Type = models.NonMemberUser
+	newUser.DateCreation = cdtime.GetToday()
 
 	err = usrSvc.repo.Create(context.Background(), newUser)
 	if err != nil {